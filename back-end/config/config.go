@@ -180,3 +180,106 @@ func KeyEmotes() string {
 	}
 	return os.Getenv("KeyEmotes")
 }
+
+// Políticas de vencimiento y recordatorios de trabajos (en días).
+func JobExpiryDays() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("JOB_EXPIRY_DAYS")
+}
+func JobReminderDays() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("JOB_REMINDER_DAYS")
+}
+func JobAutoCompleteDays() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("JOB_AUTOCOMPLETE_DAYS")
+}
+func JobCloseNoticeDays() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("JOB_CLOSE_NOTICE_DAYS")
+}
+
+// PushProvider define el proveedor de notificaciones push: "expo" (por defecto) o "fake".
+func PushProvider() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("PUSH_PROVIDER")
+}
+
+// LocationFuzzSecret es la clave usada para desplazar de forma estable las ubicaciones públicas.
+// Es obligatoria (al menos 32 caracteres aleatorios, p. ej. openssl rand -hex 32) y no debe cambiar:
+// si cambia, todas las ubicaciones aproximadas se mueven.
+func LocationFuzzSecret() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("LOCATION_FUZZ_SECRET")
+}
+
+// Pesos del ranking de trabajos recomendados ("para ti"); vacíos usan los valores por defecto.
+func RecommendationWeightTags() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("RECOMMENDATION_WEIGHT_TAGS")
+}
+func RecommendationWeightDistance() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("RECOMMENDATION_WEIGHT_DISTANCE")
+}
+func RecommendationWeightBudget() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("RECOMMENDATION_WEIGHT_BUDGET")
+}
+func RecommendationWeightFreshness() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("RECOMMENDATION_WEIGHT_FRESHNESS")
+}
+func RecommendationWeightEmployer() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("RECOMMENDATION_WEIGHT_EMPLOYER")
+}
+func PublicAPIURL() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("PUBLIC_API_URL")
+}
+
+// Listas de moderación automática separadas por comas; las entradas con prefijo "re:" son expresiones regulares.
+// Las palabras bloqueadas rechazan el texto, las de revisión lo retienen y las enmascaradas se ocultan.
+func ModerationBlockedWords() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("MODERATION_BLOCKED_WORDS")
+}
+func ModerationReviewWords() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("MODERATION_REVIEW_WORDS")
+}
+func ModerationMaskedWords() string {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("godotenv.Load error")
+	}
+	return os.Getenv("MODERATION_MASKED_WORDS")
+}
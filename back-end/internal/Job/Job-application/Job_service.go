@@ -3,6 +3,10 @@ package Jobapplication
 import (
 	jobdomain "back-end/internal/Job/Job-domain"
 	jobinfrastructure "back-end/internal/Job/Job-infrastructure"
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -14,17 +18,35 @@ import (
 // JobService se encarga de la lógica de negocio relacionada con los jobs.
 type JobService struct {
 	JobRepository *jobinfrastructure.JobRepository
+	Policy        JobPolicy
+	Weights       jobdomain.RecommendationWeights // Pesos del ranking "para ti"
 }
 
 // NewJobService crea una nueva instancia de JobService.
 func NewJobService(jobRepository *jobinfrastructure.JobRepository) *JobService {
 	return &JobService{
 		JobRepository: jobRepository,
+		Policy:        LoadJobPolicy(),
+		Weights:       LoadRecommendationWeights(),
 	}
 }
 
 // CreateJob crea una nueva publicación de trabajo a partir de la información del request y el ID del usuario creador.
 func (js *JobService) CreateJob(createReq jobdomain.CreateJobRequest, userID primitive.ObjectID) (primitive.ObjectID, error) {
+	if createReq.WorkerID == userID {
+		return primitive.NilObjectID, errors.New("no te podes solicitar un trabajo")
+	}
+	// En una solicitud directa el horario propuesto se valida contra la agenda del trabajador
+	if createReq.Slot != nil {
+		if err := createReq.Slot.Validate(time.Now()); err != nil {
+			return primitive.NilObjectID, err
+		}
+		if !createReq.WorkerID.IsZero() {
+			if err := js.checkWorkerSlot(createReq.WorkerID, primitive.NilObjectID, *createReq.Slot); err != nil {
+				return primitive.NilObjectID, err
+			}
+		}
+	}
 	newJob := jobdomain.Job{
 		UserID:              userID,
 		Title:               createReq.Title,
@@ -44,38 +66,61 @@ func (js *JobService) CreateJob(createReq jobdomain.CreateJobRequest, userID pri
 		Available:           true,
 		WorkerID:            createReq.WorkerID,
 		JobType:             createReq.JobType,
+		ExpiresAt:           js.Policy.ExpiresAt(time.Now(), createReq.ExpiresInDays),
+		ScheduledSlot:       createReq.Slot,
 	}
 
-	jobID, err := js.JobRepository.CreateJob(newJob)
-	if err != nil {
-		return jobID, err
-	}
-	// notificar a los usuarios interesados
-	// si es una solicitud mandalo al trabajador en cuestion
-	if newJob.WorkerID != primitive.NilObjectID {
-		if newJob.WorkerID == userID {
-			return jobID, errors.New("no te podes solicitar un trabajo")
-		}
-		err = js.JobRepository.SendNotificationToWorker(newJob.WorkerID, fmt.Sprintf("Nuevo trabajo asignado: %s", newJob.Title), "Se te ha asignado un nuevo trabajo.")
-		return jobID, err
-	}
-	go js.notifyUsersForJob(newJob, jobID)
-	return jobID, nil
+	// Las notificaciones (al trabajador solicitado o a los usuarios interesados) las entrega el outbox
+	return js.JobRepository.CreateJob(newJob)
 }
 
 // ApplyToJob permite que un trabajador se postule a un job.
-func (js *JobService) ApplyToJob(jobID, applicantID primitive.ObjectID, proposal string, price float64) error {
-	return js.JobRepository.ApplyToJob(jobID, applicantID, proposal, price)
+func (js *JobService) ApplyToJob(applicantID primitive.ObjectID, application jobdomain.JobData) error {
+	return js.JobRepository.ApplyToJob(application.JobId, applicantID, application)
 }
 
 // AssignJob asigna a un trabajador a un job, cambiando el estado a "in_progress".
-func (js *JobService) AssignJob(jobID, workerID primitive.ObjectID) error {
-	return js.JobRepository.AssignJob(jobID, workerID)
+// slot es el horario propuesto; si es nil se mantiene el que ya tenga el job.
+func (js *JobService) AssignJob(jobID, workerID primitive.ObjectID, slot *jobdomain.TimeSlot) error {
+	if slot != nil {
+		if err := slot.Validate(time.Now()); err != nil {
+			return err
+		}
+	} else {
+		job, err := js.JobRepository.GetJobByID(jobID)
+		if err != nil {
+			return err
+		}
+		slot = job.ScheduledSlot
+	}
+	if slot != nil {
+		if err := js.checkWorkerSlot(workerID, jobID, *slot); err != nil {
+			return err
+		}
+	}
+	return js.JobRepository.AssignJob(jobID, workerID, slot)
 }
 
 // ReassignJob permite reasignar el job a un nuevo trabajador en caso de inconvenientes.
-func (js *JobService) ReassignJob(jobID, newWorkerID primitive.ObjectID) error {
-	return js.JobRepository.ReassignJob(jobID, newWorkerID)
+// Si no se propone otro horario se mantiene el del job, siempre que el nuevo trabajador esté libre.
+func (js *JobService) ReassignJob(jobID, newWorkerID primitive.ObjectID, slot *jobdomain.TimeSlot) error {
+	if slot != nil {
+		if err := slot.Validate(time.Now()); err != nil {
+			return err
+		}
+	} else {
+		job, err := js.JobRepository.GetJobByID(jobID)
+		if err != nil {
+			return err
+		}
+		slot = job.ScheduledSlot
+	}
+	if slot != nil {
+		if err := js.checkWorkerSlot(newWorkerID, jobID, *slot); err != nil {
+			return err
+		}
+	}
+	return js.JobRepository.ReassignJob(jobID, newWorkerID, slot)
 }
 
 // ProvideEmployerFeedback permite que el empleador deje feedback sobre el trabajador.
@@ -120,13 +165,25 @@ func (js *JobService) RegisterPayment(jobID primitive.ObjectID, amount float64)
 func (js *JobService) UpdateJobPaymentStatus(jobID primitive.ObjectID, status string, paymentIntentID string) error {
 	return js.JobRepository.UpdateJobPaymentStatus(jobID, status, paymentIntentID)
 }
-func (js *JobService) GetJobByIDForEmployee(jobID primitive.ObjectID) (*jobdomain.GetJobByIDForEmployee, error) {
-	return js.JobRepository.GetJobByIDForEmployee(jobID)
-
+func (js *JobService) GetJobByIDForEmployee(jobID, viewerID primitive.ObjectID) (*jobdomain.GetJobByIDForEmployee, error) {
+	job, err := js.JobRepository.GetJobByIDForEmployee(jobID)
+	if err != nil {
+		return nil, err
+	}
+	publicJobForEmployee(job, viewerID)
+	return job, nil
 }
 
-func (js *JobService) FindJobsByTagsAndLocation(jobFilter jobdomain.FindJobsByTagsAndLocation, page int) ([]jobdomain.JobDetailsUsers, error) {
-	return js.JobRepository.FindJobsByTagsAndLocation(jobFilter, page)
+func (js *JobService) FindJobsByTagsAndLocation(jobFilter jobdomain.FindJobsByTagsAndLocation, page int, viewerID primitive.ObjectID) (*jobdomain.JobSearchResult, error) {
+	result, err := js.JobRepository.FindJobsByTagsAndLocation(jobFilter, page)
+	if err != nil {
+		return nil, err
+	}
+	publicJobsDetails(result.Jobs, viewerID)
+	if err := js.markFavoriteJobs(result.Jobs, viewerID); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 func (js *JobService) UpdateJobStatusToCompleted(jobId, UserId primitive.ObjectID) (*jobdomain.Job, error) {
 	return js.JobRepository.UpdateJobStatusToCompleted(jobId, UserId)
@@ -136,17 +193,31 @@ func (js *JobService) GetJobTokenAdmin(jobId, UserId primitive.ObjectID) (*jobdo
 	return Job, err
 }
 func (js *JobService) GetJobDetailvisited(jobId primitive.ObjectID) (*jobdomain.JobDetailsUsers, error) {
-	return js.JobRepository.GetJobDetailvisited(jobId)
+	job, err := js.JobRepository.GetJobDetailvisited(jobId)
+	if err != nil {
+		return nil, err
+	}
+	// Endpoint público: siempre ubicación aproximada
+	publicJobDetails(job, primitive.NilObjectID)
+	return job, nil
 }
 
 // Realiza una petición GET para obtener los trabajos del perfil del usuario con paginación
-func (js *JobService) GetJobsProfile(jobID primitive.ObjectID, page int) ([]jobdomain.Job, error) {
-	return js.JobRepository.GetJobsByUserID(jobID, page)
-
+func (js *JobService) GetJobsProfile(jobID primitive.ObjectID, page int, viewerID primitive.ObjectID) ([]jobdomain.Job, error) {
+	jobs, err := js.JobRepository.GetJobsByUserID(jobID, page)
+	if err != nil {
+		return nil, err
+	}
+	publicJobs(jobs, viewerID)
+	return jobs, nil
 }
-func (js *JobService) GetJobsByUserIDForEmploye(jobID primitive.ObjectID, page int) ([]jobdomain.Job, error) {
-	return js.JobRepository.GetJobsByUserIDForEmploye(jobID, page)
-
+func (js *JobService) GetJobsByUserIDForEmploye(jobID primitive.ObjectID, page int, viewerID primitive.ObjectID) ([]jobdomain.Job, error) {
+	jobs, err := js.JobRepository.GetJobsByUserIDForEmploye(jobID, page)
+	if err != nil {
+		return nil, err
+	}
+	publicJobs(jobs, viewerID)
+	return jobs, nil
 }
 func (js *JobService) GetLatestJobsForWorker(jobID primitive.ObjectID) (float64, error) {
 	return js.JobRepository.GetAverageRatingForWorker(jobID)
@@ -157,56 +228,184 @@ func (js *JobService) GetLatestJobsForEmployer(jobID primitive.ObjectID) (float6
 
 }
 func (js *JobService) GetJobsAssignedNoCompleted(jobID primitive.ObjectID, page int) ([]jobdomain.JobDetailsUsers, error) {
-	return js.JobRepository.GetJobsAssignedNoCompleted(jobID, page)
+	jobs, err := js.JobRepository.GetJobsAssignedNoCompleted(jobID, page)
+	if err != nil {
+		return nil, err
+	}
+	publicJobsDetails(jobs, jobID)
+	return jobs, nil
+}
 
+// GetJobsAssignedCompleted lista los trabajos completados de userID; viewerID es quien consulta
+// (NilObjectID en el perfil público) y decide si ve las ubicaciones exactas.
+func (js *JobService) GetJobsAssignedCompleted(userID, viewerID primitive.ObjectID, page int) ([]jobdomain.JobDetailsUsers, error) {
+	jobs, err := js.JobRepository.GetJobsAssignedCompleted(userID, page)
+	if err != nil {
+		return nil, err
+	}
+	publicJobsDetails(jobs, viewerID)
+	return jobs, nil
 }
-func (js *JobService) GetJobsAssignedCompleted(jobID primitive.ObjectID, page int) ([]jobdomain.JobDetailsUsers, error) {
-	return js.JobRepository.GetJobsAssignedCompleted(jobID, page)
 
+// RegisterOutboxHandlers registra los handlers de los eventos del módulo de trabajos.
+func (js *JobService) RegisterOutboxHandlers(d *outbox.Dispatcher) {
+	js.JobRepository.RegisterOutboxHandlers(d)
+	d.Handle(jobdomain.EventJobCreated, func(ctx context.Context, event outbox.Event) error {
+		var payload jobdomain.JobCreatedEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		job, err := js.JobRepository.GetJobByID(payload.JobID)
+		if err != nil {
+			return err
+		}
+		notified, err := js.notifyUsersForJob(ctx, *job, payload.JobID, event.ID.Hex())
+		if err != nil {
+			return err
+		}
+		return js.notifyJobAlerts(ctx, *job, event.ID.Hex(), notified)
+	})
 }
-func (js *JobService) notifyUsersForJob(job jobdomain.Job, jobID primitive.ObjectID) {
-	// 1. Buscar usuarios relevantes
-	UsersPushTokens, err := js.JobRepository.FindUsersByTagsAndLocationPushToken(job.Tags, job.Location)
-	if err != nil {
-		fmt.Println("Error al buscar usuarios:", err)
-		return
+
+// notifyUsersForJob avisa el nuevo trabajo a los usuarios cercanos con alguno de sus tags, de a páginas.
+// sourceID identifica el evento de origen para no duplicar notificaciones si se reintenta.
+// Devuelve los usuarios que ya recibieron la notificación del trabajo.
+func (js *JobService) notifyUsersForJob(ctx context.Context, job jobdomain.Job, jobID primitive.ObjectID, sourceID string) (map[primitive.ObjectID]bool, error) {
+	notified := map[primitive.ObjectID]bool{}
+	for page := 1; ; page++ {
+		users, err := js.JobRepository.FindUsersByTagsAndLocationPushToken(job.Tags, job.Location, page)
+		if err != nil {
+			return nil, fmt.Errorf("error al buscar usuarios: %v", err)
+		}
+		if err := js.notifyJobPage(ctx, job, jobID, sourceID, users); err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			notified[user.ID] = true
+		}
+		if len(users) < jobinfrastructure.JobNotifyPageSize {
+			return notified, nil
+		}
 	}
-	var pushTokens []string
+}
+
+// notifyJobPage guarda la notificación del trabajo para todos los usuarios de la página y envía el
+// push a los que tienen dispositivos registrados.
+func (js *JobService) notifyJobPage(ctx context.Context, job jobdomain.Job, jobID primitive.ObjectID, sourceID string, UsersPushTokens []jobdomain.UserPushTokenId) error {
 	var Users []primitive.ObjectID
+	tokensByUser := map[primitive.ObjectID][]string{}
 
 	for _, user := range UsersPushTokens {
+		Users = append(Users, user.ID)
+		tokens := user.PushTokens
 		if user.PushToken != "" {
-			pushTokens = append(pushTokens, user.PushToken)
-			Users = append(Users, user.ID)
+			tokens = append(tokens, user.PushToken)
+		}
+		if len(tokens) > 0 {
+			tokensByUser[user.ID] = tokens
 		}
 	}
-	const batchSize = 100
-	for i := 0; i < len(pushTokens); i += batchSize {
-		end := i + batchSize
-		if end > len(pushTokens) {
-			end = len(pushTokens)
+	title := fmt.Sprintf("Nuevo trabajo: %s", job.Title)
+	message := "Se ha publicado un nuevo trabajo que podría interesarte."
+
+	// 1. Guardar en el centro de notificaciones, tengan o no push
+	notifications := make([]notificationdomain.Notification, len(Users))
+	for i, userID := range Users {
+		notifications[i] = notificationdomain.Notification{
+			UserID:   userID,
+			Type:     notificationdomain.TypeNewJob,
+			Title:    title,
+			Body:     message,
+			Data:     map[string]string{"jobId": jobID.Hex()},
+			SourceID: sourceID,
 		}
-		batch := pushTokens[i:end]
-		err := js.JobRepository.SendBatchNotification(batch, fmt.Sprintf("Nuevo trabajo: %s", job.Title), "Se ha publicado un nuevo trabajo que podría interesarte.")
+	}
+	if err := js.JobRepository.SaveNotifications(ctx, notifications...); err != nil {
+		return err
+	}
+
+	// 2. Aplicar las preferencias de cada usuario con dispositivos antes del push. Si el evento se
+	// reintenta, los usuarios que ya recibieron (o tienen pospuesto) el push no lo reciben de nuevo
+	pushed, err := js.JobRepository.PushedUsers(ctx, sourceID, Users)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var pushUsers []primitive.ObjectID
+	for _, user := range UsersPushTokens {
+		if _, ok := tokensByUser[user.ID]; !ok || pushed[user.ID] {
+			continue
+		}
+		prefs := notificationdomain.PreferencesOrDefault(user.NotificationPreferences)
+		if !prefs.Allows(notificationdomain.CategoryNewJobs) {
+			continue
+		}
+		if prefs.NewJobsDigest {
+			if err := js.JobRepository.QueueJobDigest(ctx, user.ID, jobID, job.Title); err != nil {
+				return err
+			}
+			continue
+		}
+		allowed, err := js.JobRepository.TakeDailyPush(ctx, user.ID, prefs, sourceID, now)
 		if err != nil {
-			fmt.Println("Error al enviar notificaciones:", err)
-			return
+			return err
+		}
+		if !allowed {
+			continue
 		}
+		// En horario silencioso el push se envía cuando termine
+		if prefs.InQuietHours(now) {
+			n := push.Notification{Title: title, Body: message, ChannelID: "jobs"}
+			if err := js.JobRepository.DeferPush(ctx, user.ID, notificationdomain.CategoryNewJobs, n, prefs, now); err != nil {
+				return err
+			}
+			if err := js.JobRepository.MarkPushed(ctx, sourceID, user.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		pushUsers = append(pushUsers, user.ID)
 	}
 
-	// 3. Actualizar el documento "Para Ti"
-	err = js.JobRepository.AddJobToUsersRecommendations(Users, jobID)
-	if err != nil {
-		fmt.Println("Error al actualizar recomendaciones:", err)
-		return
+	// 3. Enviar notificaciones en lotes de usuarios completos y registrar la entrega de cada lote
+	const batchSize = 100
+	var batchUsers []primitive.ObjectID
+	var batchTokens []string
+	flush := func() error {
+		if len(batchTokens) == 0 {
+			return nil
+		}
+		if err := js.JobRepository.SendBatchNotification(batchTokens, title, message); err != nil {
+			return fmt.Errorf("error al enviar notificaciones: %v", err)
+		}
+		if err := js.JobRepository.MarkPushed(ctx, sourceID, batchUsers...); err != nil {
+			return err
+		}
+		batchUsers, batchTokens = nil, nil
+		return nil
 	}
+	for _, userID := range pushUsers {
+		batchUsers = append(batchUsers, userID)
+		batchTokens = append(batchTokens, tokensByUser[userID]...)
+		if len(batchTokens) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	return nil
 }
 
-func (js *JobService) GetRecommendedJobsForUser(userID primitive.ObjectID, page int) ([]jobdomain.JobDetailsUsers, error) {
-	return js.JobRepository.GetRecommendedJobsForUser(userID, page)
-}
 func (js *JobService) GetJobRequestsReceived(userID primitive.ObjectID, page int) ([]jobdomain.Job, error) {
-	return js.JobRepository.GetJobRequestsReceived(userID, page)
+	jobs, err := js.JobRepository.GetJobRequestsReceived(userID, page)
+	if err != nil {
+		return nil, err
+	}
+	publicJobs(jobs, userID)
+	return jobs, nil
 }
 func (js *JobService) AcceptJobRequest(jobID, workerID primitive.ObjectID) error {
 	job, err := js.JobRepository.GetJobByID(jobID)
@@ -217,6 +416,12 @@ func (js *JobService) AcceptJobRequest(jobID, workerID primitive.ObjectID) error
 	if job.WorkerID != workerID {
 		return errors.New("no autorizado: no eres el destinatario de la solicitud")
 	}
+	// La agenda pudo cambiar desde que se envió la solicitud
+	if job.ScheduledSlot != nil {
+		if err := js.checkWorkerSlot(workerID, jobID, *job.ScheduledSlot); err != nil {
+			return err
+		}
+	}
 	// Asigna al trabajador y cambia el estado
 	selectedApp := jobdomain.Application{
 		ApplicantID: workerID,
@@ -229,14 +434,12 @@ func (js *JobService) AcceptJobRequest(jobID, workerID primitive.ObjectID) error
 			"assignedApplication": selectedApp,
 			"status":              jobdomain.JobStatusInProgress,
 			"updatedAt":           time.Now(),
+			"inProgressSince":     time.Now(),
 		},
-	}
-	if err := js.JobRepository.UpdateJob(jobID, update); err != nil {
-		return err
+		"$unset": bson.M{"reminderSentAt": "", "closeNoticeAt": ""},
 	}
 	// Notifica al creador del job
-	go js.JobRepository.SendNotificationToWorker(job.UserID, "Solicitud aceptada", "El trabajador aceptó tu solicitud de trabajo.")
-	return nil
+	return js.JobRepository.UpdateJobAndNotify(jobID, update, job.UserID, notificationdomain.TypeJobRequest, "Solicitud aceptada", "El trabajador aceptó tu solicitud de trabajo.")
 }
 func (js *JobService) RejectJobRequest(jobID, workerID primitive.ObjectID) error {
 	job, err := js.JobRepository.GetJobByID(jobID)
@@ -254,10 +457,6 @@ func (js *JobService) RejectJobRequest(jobID, workerID primitive.ObjectID) error
 			"updatedAt": time.Now(),
 		},
 	}
-	if err := js.JobRepository.UpdateJob(jobID, update); err != nil {
-		return err
-	}
 	// Notifica al creador del job
-	go js.JobRepository.SendNotificationToWorker(job.UserID, "Solicitud rechazada", "El trabajador rechazó tu solicitud de trabajo.")
-	return nil
+	return js.JobRepository.UpdateJobAndNotify(jobID, update, job.UserID, notificationdomain.TypeJobRequest, "Solicitud rechazada", "El trabajador rechazó tu solicitud de trabajo.")
 }
@@ -0,0 +1,149 @@
+package Jobapplication
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/pkg/push"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateJobAlert guarda una búsqueda como alerta de trabajos.
+func (js *JobService) CreateJobAlert(userID primitive.ObjectID, req jobdomain.JobAlertRequest) (primitive.ObjectID, error) {
+	now := time.Now()
+	alert := jobdomain.JobAlert{
+		UserID:    userID,
+		Name:      req.Name,
+		Filter:    req.Filter,
+		Location:  alertLocation(req.Filter),
+		Frequency: req.Frequency,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return js.JobRepository.CreateJobAlert(context.Background(), alert)
+}
+
+// GetJobAlerts lista las alertas del usuario.
+func (js *JobService) GetJobAlerts(userID primitive.ObjectID) ([]jobdomain.JobAlert, error) {
+	return js.JobRepository.ListJobAlerts(context.Background(), userID)
+}
+
+// UpdateJobAlert reemplaza el nombre, el filtro y la frecuencia de una alerta.
+func (js *JobService) UpdateJobAlert(userID, alertID primitive.ObjectID, req jobdomain.JobAlertRequest) error {
+	return js.JobRepository.UpdateJobAlert(context.Background(), userID, alertID, bson.M{
+		"name":      req.Name,
+		"filter":    req.Filter,
+		"location":  alertLocation(req.Filter),
+		"frequency": req.Frequency,
+	})
+}
+
+// SetJobAlertPaused pausa o reanuda una alerta.
+func (js *JobService) SetJobAlertPaused(userID, alertID primitive.ObjectID, paused bool) error {
+	return js.JobRepository.UpdateJobAlert(context.Background(), userID, alertID, bson.M{"paused": paused})
+}
+
+// DeleteJobAlert elimina una alerta.
+func (js *JobService) DeleteJobAlert(userID, alertID primitive.ObjectID) error {
+	return js.JobRepository.DeleteJobAlert(context.Background(), userID, alertID)
+}
+
+func alertLocation(filter jobdomain.FindJobsByTagsAndLocation) jobdomain.GeoPoint {
+	return jobdomain.GeoPoint{Type: "Point", Coordinates: []float64{filter.Longitude, filter.Latitude}}
+}
+
+// notifyJobAlerts avisa a los dueños de las alertas que cumple el trabajo recién publicado.
+// Se omiten los usuarios de skip, que ya recibieron el aviso del trabajo, y cada usuario
+// recibe un único aviso aunque tenga varias alertas que coincidan.
+func (js *JobService) notifyJobAlerts(ctx context.Context, job jobdomain.Job, sourceID string, skip map[primitive.ObjectID]bool) error {
+	alerts, err := js.JobRepository.FindActiveAlertsNear(ctx, job.Location)
+	if err != nil {
+		return fmt.Errorf("error al buscar alertas: %v", err)
+	}
+
+	// Si un usuario tiene alertas instantáneas y diarias que coinciden, gana la instantánea
+	matched := map[primitive.ObjectID]jobdomain.JobAlert{}
+	for _, alert := range alerts {
+		if skip[alert.UserID] || !alert.Matches(job) {
+			continue
+		}
+		if current, ok := matched[alert.UserID]; !ok || current.Frequency == jobdomain.AlertFrequencyDaily {
+			matched[alert.UserID] = alert
+		}
+	}
+
+	// Si el evento se reintenta, no se repite el push a quienes ya lo recibieron
+	userIDs := make([]primitive.ObjectID, 0, len(matched))
+	for userID := range matched {
+		userIDs = append(userIDs, userID)
+	}
+	pushed, err := js.JobRepository.PushedUsers(ctx, sourceID, userIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for userID, alert := range matched {
+		if pushed[userID] {
+			continue
+		}
+		if alert.Frequency == jobdomain.AlertFrequencyDaily {
+			if err := js.JobRepository.QueueAlertDigest(ctx, userID, alert.ID, job.ID, job.Title); err != nil {
+				return err
+			}
+			continue
+		}
+
+		title := fmt.Sprintf("Alerta \"%s\": %s", alert.Name, job.Title)
+		message := "Se publicó un trabajo que coincide con tu búsqueda guardada."
+		err := js.JobRepository.SaveNotifications(ctx, notificationdomain.Notification{
+			UserID:   userID,
+			Type:     notificationdomain.TypeJobAlert,
+			Title:    title,
+			Body:     message,
+			Data:     map[string]string{"jobId": job.ID.Hex(), "alertId": alert.ID.Hex()},
+			SourceID: sourceID,
+		})
+		if err != nil {
+			return err
+		}
+
+		prefs, err := js.JobRepository.GetNotificationPreferences(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !prefs.Allows(notificationdomain.CategoryNewJobs) {
+			continue
+		}
+		allowed, err := js.JobRepository.TakeDailyPush(ctx, userID, prefs, sourceID, now)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			continue
+		}
+		// En horario silencioso el push se envía cuando termine
+		if prefs.InQuietHours(now) {
+			n := push.Notification{Title: title, Body: message, ChannelID: "jobs"}
+			if err := js.JobRepository.DeferPush(ctx, userID, notificationdomain.CategoryNewJobs, n, prefs, now); err != nil {
+				return err
+			}
+			if err := js.JobRepository.MarkPushed(ctx, sourceID, userID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := js.JobRepository.SendNotificationToWorker(userID, title, message); err != nil && !errors.Is(err, push.ErrNoTokens) {
+			return err
+		}
+		if err := js.JobRepository.MarkPushed(ctx, sourceID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package Jobapplication
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/pkg/ical"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// appointmentDateFormat es el formato de fecha usado en los textos de las notificaciones.
+const appointmentDateFormat = "02/01/2006 15:04 MST"
+
+// calendarFeedHistory es cuánto hacia atrás incluye el feed iCalendar.
+const calendarFeedHistory = 30 * 24 * time.Hour
+
+// ProposeAppointment propone una fecha para un trabajo en curso. Puede proponerla el empleador
+// o el trabajador asignado; la otra parte recibe el aviso.
+func (js *JobService) ProposeAppointment(userID, jobID primitive.ObjectID, req jobdomain.AppointmentRequest) (primitive.ObjectID, error) {
+	job, err := js.JobRepository.GetJobByID(jobID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if job.Status != jobdomain.JobStatusInProgress || job.AssignedApplication == nil || job.AssignedApplication.ApplicantID.IsZero() {
+		return primitive.NilObjectID, errors.New("solo se pueden agendar trabajos en curso")
+	}
+	now := time.Now()
+	appointment := jobdomain.Appointment{
+		JobID:      job.ID,
+		JobTitle:   job.Title,
+		EmployerID: job.UserID,
+		WorkerID:   job.AssignedApplication.ApplicantID,
+		ProposedBy: userID,
+		Slot:       req.Slot,
+		Location:   job.Location,
+		Note:       req.Note,
+		Status:     jobdomain.AppointmentProposed,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if !appointment.IsParticipant(userID) {
+		return primitive.NilObjectID, errors.New("no autorizado: no participas de este trabajo")
+	}
+	if err := js.checkAppointmentSlot(appointment, userID, req.Slot); err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	title := fmt.Sprintf("Nueva propuesta de cita: %s", job.Title)
+	message := fmt.Sprintf("Te propusieron realizar el trabajo el %s.", req.Slot.Start.UTC().Format(appointmentDateFormat))
+	return js.JobRepository.CreateAppointment(context.Background(), appointment, appointment.Counterpart(userID), title, message)
+}
+
+// RescheduleAppointment propone una nueva fecha; la cita vuelve a quedar pendiente de aceptación.
+func (js *JobService) RescheduleAppointment(userID, appointmentID primitive.ObjectID, req jobdomain.AppointmentRequest) error {
+	ctx := context.Background()
+	appointment, err := js.JobRepository.GetAppointment(ctx, appointmentID)
+	if err != nil {
+		return err
+	}
+	if !appointment.IsParticipant(userID) {
+		return errors.New("no autorizado: no participas de esta cita")
+	}
+	if !appointment.Active {
+		return errors.New("la cita fue cancelada")
+	}
+	if err := js.checkAppointmentSlot(*appointment, userID, req.Slot); err != nil {
+		return err
+	}
+
+	filter := bson.M{"status": appointment.Status, "sequence": appointment.Sequence}
+	update := bson.M{"$set": bson.M{
+		"slot":          req.Slot,
+		"note":          req.Note,
+		"proposedBy":    userID,
+		"status":        jobdomain.AppointmentProposed,
+		"remindersSent": 0,
+		"sequence":      appointment.Sequence + 1,
+		"updatedAt":     time.Now(),
+	}}
+	title := fmt.Sprintf("Cita reprogramada: %s", appointment.JobTitle)
+	message := fmt.Sprintf("Te propusieron una nueva fecha: %s.", req.Slot.Start.UTC().Format(appointmentDateFormat))
+	// Hasta que se acepte la nueva fecha el trabajo queda sin horario acordado
+	return js.JobRepository.UpdateAppointmentAndNotify(ctx, *appointment, filter, update, nil, appointment.Counterpart(userID), title, message)
+}
+
+// AcceptAppointment acepta la fecha propuesta por la otra parte.
+func (js *JobService) AcceptAppointment(userID, appointmentID primitive.ObjectID) error {
+	ctx := context.Background()
+	appointment, err := js.JobRepository.GetAppointment(ctx, appointmentID)
+	if err != nil {
+		return err
+	}
+	if !appointment.IsParticipant(userID) {
+		return errors.New("no autorizado: no participas de esta cita")
+	}
+	if appointment.Status != jobdomain.AppointmentProposed {
+		return errors.New("la cita no está pendiente de aceptación")
+	}
+	if appointment.ProposedBy == userID {
+		return errors.New("la cita la tiene que aceptar la otra parte")
+	}
+	if !appointment.Slot.Start.After(time.Now()) {
+		return errors.New("la fecha propuesta ya pasó; proponé una nueva")
+	}
+	// La agenda del trabajador pudo cambiar desde la propuesta
+	conflict, err := js.JobRepository.HasScheduleConflict(ctx, appointment.WorkerID, appointment.JobID, appointment.Slot)
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return ErrScheduleConflict
+	}
+
+	filter := bson.M{"status": jobdomain.AppointmentProposed, "sequence": appointment.Sequence}
+	update := bson.M{"$set": bson.M{
+		"status":    jobdomain.AppointmentAccepted,
+		"sequence":  appointment.Sequence + 1,
+		"updatedAt": time.Now(),
+	}}
+	title := fmt.Sprintf("Cita confirmada: %s", appointment.JobTitle)
+	message := fmt.Sprintf("La cita del %s quedó confirmada.", appointment.Slot.Start.UTC().Format(appointmentDateFormat))
+	return js.JobRepository.UpdateAppointmentAndNotify(ctx, *appointment, filter, update, &appointment.Slot, appointment.ProposedBy, title, message)
+}
+
+// CancelAppointment cancela una cita propuesta o aceptada.
+func (js *JobService) CancelAppointment(userID, appointmentID primitive.ObjectID) error {
+	ctx := context.Background()
+	appointment, err := js.JobRepository.GetAppointment(ctx, appointmentID)
+	if err != nil {
+		return err
+	}
+	if !appointment.IsParticipant(userID) {
+		return errors.New("no autorizado: no participas de esta cita")
+	}
+	if !appointment.Active {
+		return errors.New("la cita ya fue cancelada")
+	}
+
+	filter := bson.M{"active": true, "sequence": appointment.Sequence}
+	update := bson.M{"$set": bson.M{
+		"status":    jobdomain.AppointmentCancelled,
+		"active":    false,
+		"sequence":  appointment.Sequence + 1,
+		"updatedAt": time.Now(),
+	}}
+	title := fmt.Sprintf("Cita cancelada: %s", appointment.JobTitle)
+	message := fmt.Sprintf("Se canceló la cita del %s.", appointment.Slot.Start.UTC().Format(appointmentDateFormat))
+	return js.JobRepository.UpdateAppointmentAndNotify(ctx, *appointment, filter, update, nil, appointment.Counterpart(userID), title, message)
+}
+
+// checkAppointmentSlot valida el horario propuesto. Si lo propone el empleador se respeta el
+// calendario del trabajador; en todos los casos se evita superponer otro trabajo en curso.
+func (js *JobService) checkAppointmentSlot(appointment jobdomain.Appointment, proposerID primitive.ObjectID, slot jobdomain.TimeSlot) error {
+	if err := slot.Validate(time.Now()); err != nil {
+		return err
+	}
+	if proposerID == appointment.EmployerID {
+		return js.checkWorkerSlot(appointment.WorkerID, appointment.JobID, slot)
+	}
+	conflict, err := js.JobRepository.HasScheduleConflict(context.Background(), appointment.WorkerID, appointment.JobID, slot)
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return ErrScheduleConflict
+	}
+	return nil
+}
+
+// GetJobAppointments devuelve el historial de citas de un trabajo a sus participantes.
+func (js *JobService) GetJobAppointments(userID, jobID primitive.ObjectID) ([]jobdomain.Appointment, error) {
+	job, err := js.JobRepository.GetJobByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	isWorker := job.AssignedApplication != nil && job.AssignedApplication.ApplicantID == userID
+	if job.UserID != userID && !isWorker {
+		return nil, errors.New("no autorizado: no participas de este trabajo")
+	}
+	return js.JobRepository.ListJobAppointments(context.Background(), jobID)
+}
+
+// GetUpcomingAppointments devuelve las próximas citas del usuario.
+func (js *JobService) GetUpcomingAppointments(userID primitive.ObjectID) ([]jobdomain.Appointment, error) {
+	return js.JobRepository.ListUserAppointments(context.Background(), userID, time.Now(), false)
+}
+
+// AppointmentICS exporta una cita como archivo .ics.
+func (js *JobService) AppointmentICS(userID, appointmentID primitive.ObjectID) ([]byte, error) {
+	appointment, err := js.JobRepository.GetAppointment(context.Background(), appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !appointment.IsParticipant(userID) {
+		return nil, errors.New("no autorizado: no participas de esta cita")
+	}
+	return ical.Calendar("", appointmentEvent(*appointment)), nil
+}
+
+// CalendarFeedToken devuelve el token secreto del feed iCalendar del usuario.
+func (js *JobService) CalendarFeedToken(userID primitive.ObjectID) (string, error) {
+	return js.JobRepository.GetCalendarToken(context.Background(), userID)
+}
+
+// RotateCalendarFeedToken invalida la URL del feed anterior y genera una nueva.
+func (js *JobService) RotateCalendarFeedToken(userID primitive.ObjectID) (string, error) {
+	return js.JobRepository.RotateCalendarToken(context.Background(), userID)
+}
+
+// CalendarFeed arma el feed iCalendar del dueño del token. Incluye las citas canceladas
+// recientes para que los clientes las quiten de su calendario.
+func (js *JobService) CalendarFeed(token string) ([]byte, error) {
+	ctx := context.Background()
+	userID, err := js.JobRepository.FindUserByCalendarToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := js.JobRepository.ListUserAppointments(ctx, userID, time.Now().Add(-calendarFeedHistory), true)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]ical.Event, len(appointments))
+	for i, appointment := range appointments {
+		events[i] = appointmentEvent(appointment)
+	}
+	return ical.Calendar("Nexo Vecinal", events...), nil
+}
+
+func appointmentEvent(appointment jobdomain.Appointment) ical.Event {
+	status := ical.StatusTentative
+	switch appointment.Status {
+	case jobdomain.AppointmentAccepted:
+		status = ical.StatusConfirmed
+	case jobdomain.AppointmentCancelled:
+		status = ical.StatusCancelled
+	}
+	return ical.Event{
+		UID:         appointment.ID.Hex() + "@nexovecinal",
+		Sequence:    appointment.Sequence,
+		Start:       appointment.Slot.Start,
+		End:         appointment.Slot.End,
+		Stamp:       appointment.UpdatedAt,
+		Summary:     appointment.JobTitle,
+		Description: appointment.Note,
+		Coordinates: appointment.Location.Coordinates,
+		Status:      status,
+	}
+}
+
+// StartAppointmentReminders envía periódicamente los recordatorios de citas aceptadas.
+func (js *JobService) StartAppointmentReminders(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := js.SendAppointmentReminders(); err != nil {
+				fmt.Println("Error enviando recordatorios de citas:", err)
+			}
+		}
+	}()
+}
+
+// SendAppointmentReminders avisa a ambas partes de las citas que entran en una ventana de
+// AppointmentReminderLeads. Si se saltearon recordatorios (por ejemplo, una cita aceptada con
+// poca anticipación) se envía uno solo.
+func (js *JobService) SendAppointmentReminders() error {
+	ctx := context.Background()
+	now := time.Now()
+	appointments, err := js.JobRepository.FindAppointmentsForReminders(ctx, now, now.Add(jobdomain.AppointmentReminderLeads[0]))
+	if err != nil {
+		return err
+	}
+	for _, appointment := range appointments {
+		due := appointment.RemindersDue(now)
+		if due <= appointment.RemindersSent {
+			continue
+		}
+		title := fmt.Sprintf("Recordatorio de cita: %s", appointment.JobTitle)
+		message := fmt.Sprintf("Tenés una cita el %s.", appointment.Slot.Start.UTC().Format(appointmentDateFormat))
+		if err := js.JobRepository.MarkAppointmentReminder(ctx, appointment, due, title, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package Jobapplication
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// markFavoriteJobs marca los trabajos que el usuario guardó en favoritos.
+func (js *JobService) markFavoriteJobs(jobs []jobdomain.JobDetailsUsers, viewerID primitive.ObjectID) error {
+	ids := make([]primitive.ObjectID, len(jobs))
+	for i := range jobs {
+		ids[i] = jobs[i].ID
+	}
+	favorites, err := js.JobRepository.FavoriteJobIDs(context.Background(), viewerID, ids)
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		jobs[i].IsFavorite = favorites[jobs[i].ID]
+	}
+	return nil
+}
+
+// RequestFavoriteWorker envía una solicitud de trabajo directa a un trabajador favorito.
+func (js *JobService) RequestFavoriteWorker(createReq jobdomain.CreateJobRequest, userID, workerID primitive.ObjectID) (primitive.ObjectID, error) {
+	isFavorite, err := js.JobRepository.IsFavoriteWorker(context.Background(), userID, workerID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if !isFavorite {
+		return primitive.NilObjectID, errors.New("el trabajador no está en tus favoritos")
+	}
+	createReq.WorkerID = workerID
+	createReq.JobType = "solicitud"
+	return js.CreateJob(createReq, userID)
+}
@@ -0,0 +1,99 @@
+package Jobapplication
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// negotiation devuelve el trabajo y la postulación de applicantID, comprobando que userID
+// sea el empleador o el propio postulante.
+func (js *JobService) negotiation(userID, jobID, applicantID primitive.ObjectID) (*jobdomain.Job, jobdomain.Application, error) {
+	job, err := js.JobRepository.GetJobByID(jobID)
+	if err != nil {
+		return nil, jobdomain.Application{}, err
+	}
+	if userID != job.UserID && userID != applicantID {
+		return nil, jobdomain.Application{}, errors.New("no autorizado: no participas de esta postulación")
+	}
+	for _, app := range job.Applicants {
+		if app.ApplicantID == applicantID {
+			return job, app, nil
+		}
+	}
+	return nil, jobdomain.Application{}, errors.New("postulación no encontrada")
+}
+
+// GetOffers devuelve la negociación de una postulación al empleador y al postulante.
+func (js *JobService) GetOffers(userID, jobID, applicantID primitive.ObjectID) ([]jobdomain.Offer, error) {
+	_, app, err := js.negotiation(userID, jobID, applicantID)
+	if err != nil {
+		return nil, err
+	}
+	return app.Thread(), nil
+}
+
+// CounterOffer agrega una contraoferta a la negociación y avisa a la otra parte.
+func (js *JobService) CounterOffer(userID, jobID, applicantID primitive.ObjectID, req jobdomain.OfferRequest) (*jobdomain.Offer, error) {
+	job, app, err := js.negotiation(userID, jobID, applicantID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != jobdomain.JobStatusOpen {
+		return nil, errors.New("el trabajo ya no está abierto")
+	}
+	if err := app.CanCounter(userID); err != nil {
+		return nil, err
+	}
+
+	offer := jobdomain.NewOffer(userID, req, time.Now())
+	thread := append(app.Thread(), offer)
+	if thread[0].ID.IsZero() {
+		// La oferta implícita de una postulación antigua se guarda con su propio ID
+		thread[0].ID = primitive.NewObjectID()
+	}
+	notifyUserID := applicantID
+	if userID == applicantID {
+		notifyUserID = job.UserID
+	}
+	title := "Nueva contraoferta"
+	message := fmt.Sprintf("Recibiste una contraoferta de $%.2f para \"%s\".", offer.Price, job.Title)
+	if err := js.JobRepository.SaveOffer(context.Background(), jobID, applicantID, len(app.Offers), thread, notifyUserID, title, message); err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// AcceptOffer acepta la última oferta de la otra parte: el postulante queda asignado con esos
+// términos y el precio acordado pasa a ser el finalCost del trabajo.
+func (js *JobService) AcceptOffer(userID, jobID, applicantID, offerID primitive.ObjectID) error {
+	job, app, err := js.negotiation(userID, jobID, applicantID)
+	if err != nil {
+		return err
+	}
+	if job.Status != jobdomain.JobStatusOpen {
+		return errors.New("el trabajo ya no está abierto")
+	}
+	offer, err := app.LatestOffer(userID, offerID)
+	if err != nil {
+		return err
+	}
+	if job.ScheduledSlot != nil {
+		if err := js.checkWorkerSlot(applicantID, jobID, *job.ScheduledSlot); err != nil {
+			return err
+		}
+	}
+
+	// Cuando acepta el postulante se avisa al empleador; el trabajador recibe el aviso de asignación
+	var notifyUserID primitive.ObjectID
+	if userID == applicantID {
+		notifyUserID = job.UserID
+	}
+	title := "Oferta aceptada"
+	message := fmt.Sprintf("Se aceptó tu oferta de $%.2f para \"%s\".", offer.Price, job.Title)
+	return js.JobRepository.AcceptOffer(context.Background(), job, app.WithAcceptedOffer(offer), len(app.Offers), notifyUserID, title, message)
+}
@@ -0,0 +1,247 @@
+package Jobapplication
+
+import (
+	"back-end/config"
+	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/internal/notifications/notificationdomain"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// policyBatchSize limita la cantidad de jobs procesados por regla en cada ejecución.
+const policyBatchSize = 200
+
+// JobPolicy agrupa los plazos (en días) de vencimiento, recordatorios y cierre automático.
+type JobPolicy struct {
+	ExpiryDays       int // Vigencia por defecto de un job abierto
+	ReminderDays     int // Antigüedad a partir de la cual se envía un recordatorio
+	AutoCompleteDays int // Días en curso tras los cuales el job se completa automáticamente
+	CloseNoticeDays  int // Aviso previo antes de vencer o completar automáticamente
+}
+
+// LoadJobPolicy lee los plazos desde la configuración, usando valores por defecto si no están definidos.
+// Una configuración inconsistente detiene el arranque.
+func LoadJobPolicy() JobPolicy {
+	policy := JobPolicy{
+		ExpiryDays:       daysOrDefault(config.JobExpiryDays(), 30),
+		ReminderDays:     daysOrDefault(config.JobReminderDays(), 7),
+		AutoCompleteDays: daysOrDefault(config.JobAutoCompleteDays(), 30),
+		CloseNoticeDays:  daysOrDefault(config.JobCloseNoticeDays(), 3),
+	}
+	if err := policy.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	return policy
+}
+
+// Validate exige que el aviso de cierre llegue antes del completado automático.
+func (p JobPolicy) Validate() error {
+	if p.AutoCompleteDays <= p.CloseNoticeDays {
+		return fmt.Errorf("JOB_AUTOCOMPLETE_DAYS (%d) debe ser mayor que JOB_CLOSE_NOTICE_DAYS (%d)", p.AutoCompleteDays, p.CloseNoticeDays)
+	}
+	return nil
+}
+
+func daysOrDefault(value string, def int) int {
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		return def
+	}
+	return days
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// ExpiresAt calcula la fecha de vencimiento de un job; si requested es 0 se usa la vigencia por defecto.
+func (p JobPolicy) ExpiresAt(from time.Time, requested int) time.Time {
+	if requested <= 0 {
+		requested = p.ExpiryDays
+	}
+	return from.Add(days(requested))
+}
+
+// StartJobPolicyScheduler ejecuta periódicamente las políticas de vencimiento, recordatorio y cierre.
+func (js *JobService) StartJobPolicyScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			js.RunJobPolicies(time.Now())
+		}
+	}()
+}
+
+// RunJobPolicies aplica todas las reglas sobre los jobs abiertos y en curso.
+func (js *JobService) RunJobPolicies(now time.Time) {
+	js.remindStaleOpenJobs(now)
+	js.noticeExpiringOpenJobs(now)
+	js.expireOpenJobs(now)
+	js.remindInProgressJobs(now)
+	js.noticeAutoCompleteJobs(now)
+	js.autoCompleteJobs(now)
+}
+
+// expiresBefore matchea los jobs vencidos a la fecha indicada; los jobs anteriores a esta
+// política no tienen expiresAt y se toma createdAt más la vigencia por defecto.
+func (js *JobService) expiresBefore(t time.Time) []bson.M {
+	return []bson.M{
+		{"expiresAt": bson.M{"$lte": t}},
+		{"expiresAt": bson.M{"$exists": false}, "createdAt": bson.M{"$lte": t.Add(-days(js.Policy.ExpiryDays))}},
+	}
+}
+
+// inProgressBefore matchea los jobs en curso desde antes de t (usa updatedAt si falta inProgressSince).
+func inProgressBefore(t time.Time) []bson.M {
+	return []bson.M{
+		{"inProgressSince": bson.M{"$lte": t}},
+		{"inProgressSince": bson.M{"$exists": false}, "updatedAt": bson.M{"$lte": t}},
+	}
+}
+
+func (js *JobService) remindStaleOpenJobs(now time.Time) {
+	filter := bson.M{
+		"status":         jobdomain.JobStatusOpen,
+		"available":      true,
+		"createdAt":      bson.M{"$lte": now.Add(-days(js.Policy.ReminderDays))},
+		"reminderSentAt": bson.M{"$exists": false},
+	}
+	jobs, err := js.JobRepository.FindJobsByFilter(filter, policyBatchSize)
+	if err != nil {
+		fmt.Println("Error buscando jobs abiertos sin actividad:", err)
+		return
+	}
+	for _, job := range jobs {
+		if js.markJob(job, filter, bson.M{"reminderSentAt": now}) {
+			js.notifyUser(job, job.UserID, "Tu trabajo sigue abierto", fmt.Sprintf("\"%s\" todavía no tiene un trabajador asignado. Revisá las postulaciones.", job.Title))
+		}
+	}
+}
+
+func (js *JobService) noticeExpiringOpenJobs(now time.Time) {
+	filter := bson.M{
+		"status":        jobdomain.JobStatusOpen,
+		"available":     true,
+		"$or":           js.expiresBefore(now.Add(days(js.Policy.CloseNoticeDays))),
+		"closeNoticeAt": bson.M{"$exists": false},
+	}
+	jobs, err := js.JobRepository.FindJobsByFilter(filter, policyBatchSize)
+	if err != nil {
+		fmt.Println("Error buscando jobs por vencer:", err)
+		return
+	}
+	for _, job := range jobs {
+		if js.markJob(job, filter, bson.M{"closeNoticeAt": now}) {
+			js.notifyUser(job, job.UserID, "Tu trabajo está por vencer", fmt.Sprintf("\"%s\" se cerrará en %d días si no asignás a un trabajador.", job.Title, js.Policy.CloseNoticeDays))
+		}
+	}
+}
+
+func (js *JobService) expireOpenJobs(now time.Time) {
+	filter := bson.M{
+		"status":        jobdomain.JobStatusOpen,
+		"$or":           js.expiresBefore(now),
+		"closeNoticeAt": bson.M{"$lte": now.Add(-days(js.Policy.CloseNoticeDays))},
+	}
+	jobs, err := js.JobRepository.FindJobsByFilter(filter, policyBatchSize)
+	if err != nil {
+		fmt.Println("Error buscando jobs vencidos:", err)
+		return
+	}
+	for _, job := range jobs {
+		if js.markJob(job, filter, bson.M{"status": jobdomain.JobStatusExpired, "updatedAt": now}) {
+			js.notifyUser(job, job.UserID, "Tu trabajo venció", fmt.Sprintf("\"%s\" se cerró por falta de asignación. Podés volver a publicarlo.", job.Title))
+		}
+	}
+}
+
+func (js *JobService) remindInProgressJobs(now time.Time) {
+	filter := bson.M{
+		"status":         jobdomain.JobStatusInProgress,
+		"$or":            inProgressBefore(now.Add(-days(js.Policy.ReminderDays))),
+		"reminderSentAt": bson.M{"$exists": false},
+	}
+	jobs, err := js.JobRepository.FindJobsByFilter(filter, policyBatchSize)
+	if err != nil {
+		fmt.Println("Error buscando jobs en curso:", err)
+		return
+	}
+	for _, job := range jobs {
+		if js.markJob(job, filter, bson.M{"reminderSentAt": now}) {
+			js.notifyParties(job, "¿Terminaron el trabajo?", fmt.Sprintf("\"%s\" sigue en curso. Si ya finalizó, marcalo como completado.", job.Title))
+		}
+	}
+}
+
+func (js *JobService) noticeAutoCompleteJobs(now time.Time) {
+	filter := bson.M{
+		"status":        jobdomain.JobStatusInProgress,
+		"$or":           inProgressBefore(now.Add(-days(js.Policy.AutoCompleteDays - js.Policy.CloseNoticeDays))),
+		"closeNoticeAt": bson.M{"$exists": false},
+	}
+	jobs, err := js.JobRepository.FindJobsByFilter(filter, policyBatchSize)
+	if err != nil {
+		fmt.Println("Error buscando jobs a completar:", err)
+		return
+	}
+	for _, job := range jobs {
+		if js.markJob(job, filter, bson.M{"closeNoticeAt": now}) {
+			js.notifyParties(job, "El trabajo se completará automáticamente", fmt.Sprintf("\"%s\" se marcará como completado en %d días si nadie lo actualiza.", job.Title, js.Policy.CloseNoticeDays))
+		}
+	}
+}
+
+func (js *JobService) autoCompleteJobs(now time.Time) {
+	filter := bson.M{
+		"status":        jobdomain.JobStatusInProgress,
+		"$or":           inProgressBefore(now.Add(-days(js.Policy.AutoCompleteDays))),
+		"closeNoticeAt": bson.M{"$lte": now.Add(-days(js.Policy.CloseNoticeDays))},
+	}
+	jobs, err := js.JobRepository.FindJobsByFilter(filter, policyBatchSize)
+	if err != nil {
+		fmt.Println("Error buscando jobs vencidos en curso:", err)
+		return
+	}
+	for _, job := range jobs {
+		// Reutiliza el flujo normal para actualizar contadores y métricas; si el job cambió de estado
+		// desde la búsqueda no se toca
+		completed, err := js.JobRepository.AutoCompleteJob(job.ID, job.UserID, filter)
+		if err != nil {
+			fmt.Println("Error completando job automáticamente:", job.ID.Hex(), err)
+			continue
+		}
+		if !completed {
+			continue
+		}
+		js.notifyParties(job, "Trabajo completado", fmt.Sprintf("\"%s\" se marcó como completado automáticamente. Ya podés dejar tu opinión.", job.Title))
+	}
+}
+
+// markJob aplica set al job solo si todavía cumple filter, el filtro con el que la regla lo eligió:
+// si mientras tanto lo asignaron o completaron no se pisa su estado. Devuelve si se actualizó.
+func (js *JobService) markJob(job jobdomain.Job, filter, set bson.M) bool {
+	updated, err := js.JobRepository.UpdateJobIf(job.ID, filter, bson.M{"$set": set})
+	if err != nil {
+		fmt.Println("Error actualizando job:", job.ID.Hex(), err)
+	}
+	return updated
+}
+
+// notifyParties avisa al empleador y, si existe, al trabajador asignado.
+func (js *JobService) notifyParties(job jobdomain.Job, title, message string) {
+	js.notifyUser(job, job.UserID, title, message)
+	if job.AssignedApplication != nil && !job.AssignedApplication.ApplicantID.IsZero() {
+		js.notifyUser(job, job.AssignedApplication.ApplicantID, title, message)
+	}
+}
+
+func (js *JobService) notifyUser(job jobdomain.Job, userID primitive.ObjectID, title, message string) {
+	if err := js.JobRepository.QueueUserNotification(userID, notificationdomain.TypeJobReminder, title, message, job.ID); err != nil {
+		fmt.Println("Error notificando job:", job.ID.Hex(), err)
+	}
+}
@@ -0,0 +1,71 @@
+package Jobapplication
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/pkg/geoprivacy"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Solo el dueño del trabajo y el trabajador asignado (o el destinatario de una solicitud)
+// ven la ubicación exacta; el resto recibe una ubicación aproximada y estable por trabajo.
+// Las consultas siguen usando el punto exacto guardado en la base.
+
+func approximateLocation(jobID primitive.ObjectID, location *jobdomain.GeoPoint) {
+	location.Coordinates = geoprivacy.Approximate(jobID, location.Coordinates)
+	location.Approximate = true
+}
+
+func canSeeExactLocation(viewerID primitive.ObjectID, parties ...primitive.ObjectID) bool {
+	if viewerID.IsZero() {
+		return false
+	}
+	for _, party := range parties {
+		if party == viewerID {
+			return true
+		}
+	}
+	return false
+}
+
+func publicJob(job *jobdomain.Job, viewerID primitive.ObjectID) {
+	parties := []primitive.ObjectID{job.UserID, job.WorkerID}
+	if job.AssignedApplication != nil {
+		parties = append(parties, job.AssignedApplication.ApplicantID)
+	}
+	if !canSeeExactLocation(viewerID, parties...) {
+		approximateLocation(job.ID, &job.Location)
+	}
+}
+
+func publicJobs(jobs []jobdomain.Job, viewerID primitive.ObjectID) {
+	for i := range jobs {
+		publicJob(&jobs[i], viewerID)
+	}
+}
+
+func publicJobDetails(job *jobdomain.JobDetailsUsers, viewerID primitive.ObjectID) {
+	parties := []primitive.ObjectID{job.UserID}
+	if job.AssignedTo != nil {
+		parties = append(parties, job.AssignedTo.ApplicantID)
+	}
+	if !canSeeExactLocation(viewerID, parties...) {
+		approximateLocation(job.ID, &job.Location)
+	}
+}
+
+func publicJobsDetails(jobs []jobdomain.JobDetailsUsers, viewerID primitive.ObjectID) {
+	for i := range jobs {
+		publicJobDetails(&jobs[i], viewerID)
+	}
+}
+
+func publicJobForEmployee(job *jobdomain.GetJobByIDForEmployee, viewerID primitive.ObjectID) {
+	parties := []primitive.ObjectID{job.UserID}
+	if job.AssignedTo != nil {
+		parties = append(parties, *job.AssignedTo)
+	}
+	if !canSeeExactLocation(viewerID, parties...) {
+		approximateLocation(job.ID, &job.Location)
+	}
+}
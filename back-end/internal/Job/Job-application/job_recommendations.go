@@ -0,0 +1,100 @@
+package Jobapplication
+
+import (
+	"back-end/config"
+	jobdomain "back-end/internal/Job/Job-domain"
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// recommendationCandidates es la cantidad de trabajos abiertos que se puntúan por consulta,
+	// tomados entre los que comparten algún tag con el perfil y están dentro del radio.
+	recommendationCandidates = 300
+	// defaultRecommendationRadius se usa si el usuario no configuró su radio (en metros).
+	defaultRecommendationRadius = 25000
+)
+
+// LoadRecommendationWeights lee los pesos del ranking desde la configuración,
+// usando los valores por defecto para los que no estén definidos.
+func LoadRecommendationWeights() jobdomain.RecommendationWeights {
+	def := jobdomain.DefaultRecommendationWeights()
+	return jobdomain.RecommendationWeights{
+		TagAffinity:    weightOrDefault(config.RecommendationWeightTags(), def.TagAffinity),
+		Distance:       weightOrDefault(config.RecommendationWeightDistance(), def.Distance),
+		BudgetFit:      weightOrDefault(config.RecommendationWeightBudget(), def.BudgetFit),
+		Freshness:      weightOrDefault(config.RecommendationWeightFreshness(), def.Freshness),
+		EmployerRating: weightOrDefault(config.RecommendationWeightEmployer(), def.EmployerRating),
+	}
+}
+
+func weightOrDefault(value string, def float64) float64 {
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil || weight < 0 {
+		return def
+	}
+	return weight
+}
+
+// GetRecommendedJobsForUser devuelve los trabajos "Para Ti" ordenados por puntaje.
+// Con nearestFirst se ordenan del más cercano al más lejano.
+func (js *JobService) GetRecommendedJobsForUser(userID primitive.ObjectID, page int, nearestFirst bool) ([]jobdomain.JobDetailsUsers, error) {
+	ctx := context.Background()
+	user, err := js.JobRepository.GetRecommendationUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := js.JobRepository.GetRecommendationHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	history.ProfileTags = user.Tags
+
+	radius := user.Ratio
+	if radius <= 0 {
+		radius = defaultRecommendationRadius
+	}
+	profile := jobdomain.NewRecommendationProfile(userID, history, radius)
+	candidates, err := js.JobRepository.FindRecommendationCandidates(ctx, userID, user.Location, radius, profile.Tags(), recommendationCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := jobdomain.RankJobs(profile, candidates, js.Weights, time.Now())
+	if nearestFirst {
+		sort.SliceStable(jobs, func(a, b int) bool {
+			return distanceOrMax(jobs[a]) < distanceOrMax(jobs[b])
+		})
+	}
+
+	const pageSize = 10
+	start := (page - 1) * pageSize
+	if start >= len(jobs) {
+		return []jobdomain.JobDetailsUsers{}, nil
+	}
+	jobs = jobs[start:min(start+pageSize, len(jobs))]
+
+	// La distancia exacta solo se usa para puntuar; se muestra redondeada
+	for i := range jobs {
+		if jobs[i].DistanceMeters != nil {
+			fuzzed := jobdomain.FuzzDistance(*jobs[i].DistanceMeters)
+			jobs[i].DistanceMeters = &fuzzed
+		}
+	}
+	publicJobsDetails(jobs, userID)
+	if err := js.markFavoriteJobs(jobs, userID); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func distanceOrMax(job jobdomain.JobDetailsUsers) float64 {
+	if job.DistanceMeters == nil {
+		return float64(1 << 62)
+	}
+	return *job.DistanceMeters
+}
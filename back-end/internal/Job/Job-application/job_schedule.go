@@ -0,0 +1,35 @@
+package Jobapplication
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrWorkerUnavailable = errors.New("el trabajador no está disponible en ese horario")
+	ErrScheduleConflict  = errors.New("el trabajador ya tiene un trabajo en curso en ese horario")
+)
+
+// checkWorkerSlot verifica el calendario del trabajador y que no tenga otro trabajo en curso
+// superpuesto. jobID se excluye de la búsqueda de conflictos (puede ser NilObjectID).
+func (js *JobService) checkWorkerSlot(workerID, jobID primitive.ObjectID, slot jobdomain.TimeSlot) error {
+	ctx := context.Background()
+	available, err := js.JobRepository.IsWorkerAvailable(ctx, workerID, slot)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return ErrWorkerUnavailable
+	}
+	conflict, err := js.JobRepository.HasScheduleConflict(ctx, workerID, jobID, slot)
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return ErrScheduleConflict
+	}
+	return nil
+}
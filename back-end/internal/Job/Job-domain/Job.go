@@ -1,6 +1,9 @@
 package jobdomain
 
 import (
+	"back-end/internal/notifications/notificationdomain"
+	"errors"
+	"math"
 	"time"
 
 	"github.com/go-playground/validator"
@@ -16,6 +19,7 @@ const (
 	JobStatusCompleted  JobStatus = "completed"   // Finalizado y cerrado
 	JobStatusCancelled  JobStatus = "cancelled"   // Cancelado
 	JobStatusRejected   JobStatus = "rejected"    // Rechazado
+	JobStatusExpired    JobStatus = "expired"     // Vencido sin asignar
 )
 
 // Feedback representa la opinión y puntuación que puede dejar un usuario.
@@ -29,6 +33,7 @@ type Feedback struct {
 type GeoPoint struct {
 	Type        string    `bson:"type" json:"type"`               // Siempre "Point"
 	Coordinates []float64 `bson:"coordinates" json:"coordinates"` // [longitud, latitud]
+	Approximate bool      `bson:"-" json:"approximate,omitempty"` // true si las coordenadas fueron desplazadas por privacidad
 }
 
 // Application representa la postulación de un usuario con su propuesta y precio.
@@ -37,6 +42,10 @@ type Application struct {
 	Proposal    string             `json:"proposal" bson:"proposal" validate:"max=100"` // Máximo 100 caracteres
 	Price       float64            `json:"price" bson:"price"`
 	AppliedAt   time.Time          `json:"appliedAt" bson:"appliedAt"`
+	// Términos de la oferta vigente (en assignedApplication, los de la oferta aceptada)
+	EstimatedMinutes  int     `json:"estimatedMinutes,omitempty" bson:"estimatedMinutes,omitempty"`
+	MaterialsIncluded bool    `json:"materialsIncluded" bson:"materialsIncluded"`
+	Offers            []Offer `json:"offers,omitempty" bson:"offers,omitempty"` // Negociación entre empleador y postulante
 }
 
 // Job representa la estructura de una publicación de trabajo o necesidad.
@@ -63,18 +72,26 @@ type Job struct {
 	Available           bool               `json:"Available" bson:"available"`
 	WorkerID            primitive.ObjectID `json:"workerId,omitempty" bson:"workerId,omitempty"`
 	JobType             string             `json:"jobType" bson:"jobType"` // "publicacion" o "solicitud"
+	ExpiresAt           time.Time          `json:"expiresAt" bson:"expiresAt,omitempty"`
+	InProgressSince     *time.Time         `json:"inProgressSince,omitempty" bson:"inProgressSince,omitempty"` // Fecha en la que pasó a "in_progress"
+	ReminderSentAt      *time.Time         `json:"reminderSentAt,omitempty" bson:"reminderSentAt,omitempty"`   // Último recordatorio enviado en el estado actual
+	CloseNoticeAt       *time.Time         `json:"closeNoticeAt,omitempty" bson:"closeNoticeAt,omitempty"`     // Aviso previo al cierre/completado automático
+	ScheduledSlot       *TimeSlot          `json:"scheduledSlot,omitempty" bson:"scheduledSlot,omitempty"`     // Horario acordado con el trabajador
+	Held                bool               `json:"held,omitempty" bson:"held,omitempty"`                       // Retenido por la moderación automática hasta que lo apruebe un administrador
 }
 
 // CreateJobRequest representa la información necesaria para crear un job.
 type CreateJobRequest struct {
-	Title       string             `json:"title" validate:"required,min=3,max=100"`         // Título del trabajo (requerido, entre 3 y 100 caracteres)
-	Description string             `json:"description" validate:"required,min=10,max=1000"` // Descripción detallada (requerido, entre 10 y 1000 caracteres)
-	Location    GeoPoint           `json:"location,omitempty"`                              // Ubicación o zona del trabajo (requerido)
-	Tags        []string           `json:"tags" validate:"required,dive,required"`          // Etiquetas para clasificar el trabajo (al menos una requerida)
-	Budget      float64            `json:"budget" validate:"required,gt=0"`                 // Presupuesto estimado (debe ser mayor a 2000)
-	Image       string             `json:"Image"`
-	WorkerID    primitive.ObjectID `json:"workerId,omitempty"`
-	JobType     string             `json:"jobType"` // Tipo de trabajo: "publicacion" o "solicitud"
+	Title         string             `json:"title" validate:"required,min=3,max=100"`         // Título del trabajo (requerido, entre 3 y 100 caracteres)
+	Description   string             `json:"description" validate:"required,min=10,max=1000"` // Descripción detallada (requerido, entre 10 y 1000 caracteres)
+	Location      GeoPoint           `json:"location,omitempty"`                              // Ubicación o zona del trabajo (requerido)
+	Tags          []string           `json:"tags" validate:"required,dive,required"`          // Etiquetas para clasificar el trabajo (al menos una requerida)
+	Budget        float64            `json:"budget" validate:"required,gt=0"`                 // Presupuesto estimado (debe ser mayor a 2000)
+	Image         string             `json:"Image"`
+	WorkerID      primitive.ObjectID `json:"workerId,omitempty"`
+	JobType       string             `json:"jobType"`                                         // Tipo de trabajo: "publicacion" o "solicitud"
+	ExpiresInDays int                `json:"expiresInDays" validate:"omitempty,min=1,max=90"` // Días hasta el vencimiento (opcional, por defecto JOB_EXPIRY_DAYS)
+	Slot          *TimeSlot          `json:"slot,omitempty"`                                  // Horario propuesto al trabajador en una solicitud directa
 }
 
 func (u *CreateJobRequest) ValidateCreateJobRequest() error {
@@ -111,23 +128,66 @@ type TransferPaymentRequest struct {
 type TransferPaymentResponse struct {
 	TransferID string `json:"transfer_id"`
 }
+
+// Criterios de orden de la búsqueda de trabajos.
+const (
+	JobSortRelevance  = "relevance"   // Por puntaje de texto (requiere title)
+	JobSortDistance   = "distance"    // Más cercanos primero
+	JobSortBudgetAsc  = "budget_asc"  // Menor presupuesto primero
+	JobSortBudgetDesc = "budget_desc" // Mayor presupuesto primero
+	JobSortRecent     = "recent"      // Más nuevos primero
+)
+
 type FindJobsByTagsAndLocation struct {
-	Tags           []string `json:"tags"`
-	Longitude      float64  `json:"longitude" validate:"required,gte=-180,lte=180"`
-	Latitude       float64  `json:"latitude" validate:"required,gte=-90,lte=90"`
-	RadiusInMeters float64  `json:"radius" validate:"required,gt=0,lte=100000"`
-	Title          string   `json:"title" validate:"max=100"`
+	Tags           []string   `json:"tags" bson:"tags"`
+	Longitude      float64    `json:"longitude" bson:"longitude" validate:"required,gte=-180,lte=180"`
+	Latitude       float64    `json:"latitude" bson:"latitude" validate:"required,gte=-90,lte=90"`
+	RadiusInMeters float64    `json:"radius" bson:"radius" validate:"required,gt=0,lte=100000"`
+	Title          string     `json:"title" bson:"title" validate:"max=100"` // Texto libre: busca en título, descripción y etiquetas
+	BudgetMin      float64    `json:"budgetMin" bson:"budgetMin" validate:"gte=0"`
+	BudgetMax      float64    `json:"budgetMax" bson:"budgetMax" validate:"gte=0"` // 0 significa sin máximo
+	Status         JobStatus  `json:"status" bson:"status" validate:"omitempty,oneof=open in_progress"`
+	CreatedFrom    *time.Time `json:"createdFrom,omitempty" bson:"createdFrom,omitempty"`
+	CreatedTo      *time.Time `json:"createdTo,omitempty" bson:"createdTo,omitempty"`
+	SortBy         string     `json:"sortBy" bson:"sortBy" validate:"omitempty,oneof=relevance distance budget_asc budget_desc recent"`
 }
 
 func (u *FindJobsByTagsAndLocation) Validate() error {
 	validate := validator.New()
-	return validate.Struct(u)
+	if err := validate.Struct(u); err != nil {
+		return err
+	}
+	if u.BudgetMax > 0 && u.BudgetMin > u.BudgetMax {
+		return errors.New("budgetMin no puede ser mayor que budgetMax")
+	}
+	if u.CreatedFrom != nil && u.CreatedTo != nil && u.CreatedFrom.After(*u.CreatedTo) {
+		return errors.New("createdFrom no puede ser posterior a createdTo")
+	}
+	// Sin texto no hay puntaje de relevancia: por defecto se ordena por fecha
+	if u.SortBy == "" || (u.SortBy == JobSortRelevance && u.Title == "") {
+		if u.Title != "" {
+			u.SortBy = JobSortRelevance
+		} else {
+			u.SortBy = JobSortRecent
+		}
+	}
+	return nil
+}
+
+// JobSearchResult es una página de resultados de la búsqueda de trabajos.
+type JobSearchResult struct {
+	Jobs     []JobDetailsUsers `json:"jobs"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
 }
 
 type JobData struct {
-	JobId    primitive.ObjectID `json:"JobId" validate:"required"`
-	Price    float64            `json:"price" validate:"required"`
-	Proposal string             `json:"Proposal" validate:"required,min=3,max=100"`
+	JobId             primitive.ObjectID `json:"JobId" validate:"required"`
+	Price             float64            `json:"price" validate:"required"`
+	Proposal          string             `json:"Proposal" validate:"required,min=3,max=100"`
+	EstimatedMinutes  int                `json:"estimatedMinutes" validate:"gte=0,lte=43200"`
+	MaterialsIncluded bool               `json:"materialsIncluded"`
 }
 
 func (job JobData) ValidateJobData() error {
@@ -150,6 +210,10 @@ type ApplicantDetails struct {
 	Proposal    string             `json:"proposal" bson:"proposal"`
 	Price       float64            `json:"price" bson:"price"`
 	AppliedAt   time.Time          `json:"appliedAt" bson:"appliedAt"`
+	// Términos vigentes y negociación de la postulación
+	EstimatedMinutes  int     `json:"estimatedMinutes,omitempty" bson:"estimatedMinutes,omitempty"`
+	MaterialsIncluded bool    `json:"materialsIncluded" bson:"materialsIncluded"`
+	Offers            []Offer `json:"offers,omitempty" bson:"offers,omitempty"`
 	// UserData contiene la información del usuario postulante (extraída con $lookup)
 	UserData *User `json:"userData,omitempty" bson:"userData,omitempty"`
 }
@@ -178,6 +242,19 @@ type JobDetailsUsers struct {
 	PaymentAmount    float64           `json:"paymentAmount" bson:"paymentAmount"`
 	PaymentIntentID  string            `json:"paymentIntentId" bson:"paymentIntentId"`
 	Images           []string          `json:"Images" bson:"Images"`
+	Score            float64           `json:"score,omitempty" bson:"score,omitempty"` // Relevancia en búsquedas por texto
+	DistanceMeters   *float64          `json:"distanceMeters,omitempty" bson:"distanceMeters,omitempty"`
+	IsFavorite       bool              `json:"isFavorite" bson:"-"` // El trabajo está en los favoritos de quien consulta
+	ScheduledSlot    *TimeSlot         `json:"scheduledSlot,omitempty" bson:"scheduledSlot,omitempty"`
+}
+
+// FuzzDistance redondea una distancia en metros antes de mostrarla: hasta 1 km a los 100 m
+// superiores (mínimo 100 m) y a partir de ahí a los 500 m más cercanos.
+func FuzzDistance(meters float64) float64 {
+	if meters < 1000 {
+		return math.Max(100, math.Ceil(meters/100)*100)
+	}
+	return math.Round(meters/500) * 500
 }
 
 type GetJobByIDForEmployee struct {
@@ -201,6 +278,9 @@ type GetJobByIDForEmployee struct {
 	AssignedTo       *primitive.ObjectID `json:"assignedTo,omitempty" bson:"assignedTo,omitempty"`
 }
 type UserPushTokenId struct {
-	ID        primitive.ObjectID `bson:"_id"`
-	PushToken string             `bson:"pushToken"`
+	ID         primitive.ObjectID `bson:"_id"`
+	PushToken  string             `bson:"pushToken"`
+	PushTokens []string           `bson:"pushTokens"` // Un token por dispositivo
+
+	NotificationPreferences *notificationdomain.Preferences `bson:"notificationPreferences"`
 }
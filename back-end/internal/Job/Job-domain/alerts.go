@@ -0,0 +1,98 @@
+package jobdomain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Frecuencias de aviso de una alerta de trabajos.
+const (
+	AlertFrequencyInstant = "instant" // Push en el momento en que se publica el trabajo
+	AlertFrequencyDaily   = "daily"   // Se agrega al resumen diario de nuevos trabajos
+)
+
+// MaxAlertsPerUser limita la cantidad de búsquedas guardadas por usuario.
+const MaxAlertsPerUser = 10
+
+// JobAlert es una búsqueda guardada: avisa al usuario cuando se publica un trabajo que la cumple.
+type JobAlert struct {
+	ID        primitive.ObjectID        `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID        `json:"userId" bson:"userId"`
+	Name      string                    `json:"name" bson:"name"`
+	Filter    FindJobsByTagsAndLocation `json:"filter" bson:"filter"`
+	Location  GeoPoint                  `json:"-" bson:"location"` // Centro del filtro, indexado para buscar alertas cercanas a un trabajo
+	Frequency string                    `json:"frequency" bson:"frequency"`
+	Paused    bool                      `json:"paused" bson:"paused"`
+	CreatedAt time.Time                 `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time                 `json:"updatedAt" bson:"updatedAt"`
+}
+
+// JobAlertRequest es el cuerpo para crear o editar una alerta.
+type JobAlertRequest struct {
+	Name      string                    `json:"name" validate:"required,min=1,max=60"`
+	Filter    FindJobsByTagsAndLocation `json:"filter"`
+	Frequency string                    `json:"frequency" validate:"required,oneof=instant daily"`
+}
+
+func (r *JobAlertRequest) Validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return err
+	}
+	return r.Filter.Validate()
+}
+
+// Matches indica si el trabajo cumple los filtros de la alerta, salvo la distancia,
+// que se resuelve en la consulta geoespacial.
+func (a JobAlert) Matches(job Job) bool {
+	f := a.Filter
+	if job.UserID == a.UserID {
+		return false
+	}
+	if len(f.Tags) > 0 && !sharesTag(f.Tags, job.Tags) {
+		return false
+	}
+	if f.Status != "" && f.Status != job.Status {
+		return false
+	}
+	if f.BudgetMin > 0 && job.Budget < f.BudgetMin {
+		return false
+	}
+	if f.BudgetMax > 0 && job.Budget > f.BudgetMax {
+		return false
+	}
+	if f.Title != "" && !matchesText(f.Title, job) {
+		return false
+	}
+	if f.CreatedFrom != nil && job.CreatedAt.Before(*f.CreatedFrom) {
+		return false
+	}
+	if f.CreatedTo != nil && job.CreatedAt.After(*f.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+func sharesTag(wanted, tags []string) bool {
+	for _, w := range wanted {
+		for _, t := range tags {
+			if strings.EqualFold(w, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesText replica la búsqueda de texto: alcanza con que aparezca alguna de las palabras.
+func matchesText(query string, job Job) bool {
+	text := strings.ToLower(job.Title + " " + job.Description + " " + strings.Join(job.Tags, " "))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package jobdomain
+
+import (
+	"time"
+
+	"github.com/go-playground/validator"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AppointmentStatus es el estado de una cita.
+type AppointmentStatus string
+
+const (
+	AppointmentProposed  AppointmentStatus = "proposed"  // Esperando que la otra parte la acepte
+	AppointmentAccepted  AppointmentStatus = "accepted"  // Fecha acordada
+	AppointmentCancelled AppointmentStatus = "cancelled" // Cancelada por alguna de las partes
+)
+
+// AppointmentReminderLeads son las anticipaciones con las que se recuerda una cita aceptada.
+var AppointmentReminderLeads = []time.Duration{24 * time.Hour, time.Hour}
+
+// Appointment es la cita acordada para realizar un trabajo en curso. Cada trabajo tiene
+// como máximo una cita activa (propuesta o aceptada); reprogramar la vuelve a proponer.
+type Appointment struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	JobID      primitive.ObjectID `json:"jobId" bson:"jobId"`
+	JobTitle   string             `json:"jobTitle" bson:"jobTitle"`
+	EmployerID primitive.ObjectID `json:"employerId" bson:"employerId"`
+	WorkerID   primitive.ObjectID `json:"workerId" bson:"workerId"`
+	ProposedBy primitive.ObjectID `json:"proposedBy" bson:"proposedBy"`
+	Slot       TimeSlot           `json:"slot" bson:"slot"`
+	Location   GeoPoint           `json:"location" bson:"location"` // Ubicación exacta del trabajo; solo la ven las partes
+	Note       string             `json:"note,omitempty" bson:"note,omitempty"`
+	Status     AppointmentStatus  `json:"status" bson:"status"`
+	// Active es true mientras la cita está propuesta o aceptada; un índice único parcial
+	// sobre jobId garantiza una sola cita activa por trabajo.
+	Active        bool      `json:"-" bson:"active"`
+	RemindersSent int       `json:"-" bson:"remindersSent"` // Recordatorios de AppointmentReminderLeads ya enviados
+	Sequence      int       `json:"-" bson:"sequence"`      // Versión para iCalendar; sube con cada cambio
+	CreatedAt     time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// AppointmentRequest es el cuerpo para proponer o reprogramar una cita.
+type AppointmentRequest struct {
+	Slot TimeSlot `json:"slot"`
+	Note string   `json:"note" validate:"max=300"`
+}
+
+// Validate valida la nota y que el horario sea futuro y razonable.
+func (r AppointmentRequest) Validate(now time.Time) error {
+	if err := validator.New().Struct(r); err != nil {
+		return err
+	}
+	return r.Slot.Validate(now)
+}
+
+// IsParticipant indica si el usuario es el empleador o el trabajador de la cita.
+func (a Appointment) IsParticipant(userID primitive.ObjectID) bool {
+	return userID == a.EmployerID || userID == a.WorkerID
+}
+
+// Counterpart devuelve la otra parte de la cita.
+func (a Appointment) Counterpart(userID primitive.ObjectID) primitive.ObjectID {
+	if userID == a.EmployerID {
+		return a.WorkerID
+	}
+	return a.EmployerID
+}
+
+// RemindersDue devuelve cuántos recordatorios deberían haberse enviado a now. Si es mayor
+// que RemindersSent corresponde enviar uno (solo uno, aunque se hayan salteado varios).
+func (a Appointment) RemindersDue(now time.Time) int {
+	if a.Status != AppointmentAccepted || !now.Before(a.Slot.Start) {
+		return a.RemindersSent
+	}
+	due := 0
+	for _, lead := range AppointmentReminderLeads {
+		if !now.Before(a.Slot.Start.Add(-lead)) {
+			due++
+		}
+	}
+	return due
+}
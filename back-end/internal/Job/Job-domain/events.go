@@ -0,0 +1,42 @@
+package jobdomain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tipos de eventos del outbox emitidos por el módulo de trabajos.
+const (
+	EventJobCreated          = "job.created"           // Notificar usuarios cercanos y actualizar "Para Ti"
+	EventJobAssigned         = "job.assigned"          // Avisar al trabajador asignado
+	EventUserNotification    = "job.user_notification" // Push genérico a un usuario
+	EventJobPublishedMetrics = "job.metrics.published"
+	EventJobCompletedMetrics = "job.metrics.completed"
+)
+
+// JobCreatedEvent es el payload de EventJobCreated.
+type JobCreatedEvent struct {
+	JobID primitive.ObjectID `bson:"jobId"`
+}
+
+// JobAssignedEvent es el payload de EventJobAssigned.
+type JobAssignedEvent struct {
+	WorkerID primitive.ObjectID `bson:"workerId"`
+	JobTitle string             `bson:"jobTitle"`
+}
+
+// UserNotificationEvent es el payload de EventUserNotification.
+type UserNotificationEvent struct {
+	UserID  primitive.ObjectID `bson:"userId"`
+	Type    string             `bson:"type"` // Tipo de notificación del centro de notificaciones
+	Title   string             `bson:"title"`
+	Message string             `bson:"message"`
+	Data    map[string]string  `bson:"data,omitempty"`
+}
+
+// JobMetricsEvent es el payload de los eventos de métricas.
+type JobMetricsEvent struct {
+	Gender    string    `bson:"gender"`
+	BirthDate time.Time `bson:"birthDate"`
+}
@@ -0,0 +1,119 @@
+package jobdomain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxOffersPerApplication acota la cantidad de ofertas de una negociación.
+const MaxOffersPerApplication = 20
+
+// Offer es una oferta o contraoferta dentro de la negociación de una postulación.
+type Offer struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id"`
+	ProposedBy        primitive.ObjectID `json:"proposedBy" bson:"proposedBy"`
+	Price             float64            `json:"price" bson:"price"`
+	EstimatedMinutes  int                `json:"estimatedMinutes" bson:"estimatedMinutes"` // Duración estimada; 0 si no se indicó
+	MaterialsIncluded bool               `json:"materialsIncluded" bson:"materialsIncluded"`
+	Message           string             `json:"message,omitempty" bson:"message,omitempty"`
+	Accepted          bool               `json:"accepted" bson:"accepted"`
+	CreatedAt         time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// OfferRequest es el cuerpo de una contraoferta.
+type OfferRequest struct {
+	Price             float64 `json:"price" validate:"required,gt=0"`
+	EstimatedMinutes  int     `json:"estimatedMinutes" validate:"gte=0,lte=43200"` // Hasta 30 días
+	MaterialsIncluded bool    `json:"materialsIncluded"`
+	Message           string  `json:"message" validate:"max=200"`
+}
+
+func (req OfferRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(req)
+}
+
+// AcceptOfferRequest identifica la oferta que se acepta, para no aceptar una que cambió mientras tanto.
+type AcceptOfferRequest struct {
+	OfferID primitive.ObjectID `json:"offerId" validate:"required"`
+}
+
+// NewOffer arma una oferta de userID a partir del pedido.
+func NewOffer(userID primitive.ObjectID, req OfferRequest, now time.Time) Offer {
+	return Offer{
+		ID:                primitive.NewObjectID(),
+		ProposedBy:        userID,
+		Price:             req.Price,
+		EstimatedMinutes:  req.EstimatedMinutes,
+		MaterialsIncluded: req.MaterialsIncluded,
+		Message:           req.Message,
+		CreatedAt:         now,
+	}
+}
+
+// Thread devuelve la negociación de la postulación. Las postulaciones anteriores a las
+// contraofertas no tienen Offers: su precio cuenta como la primera oferta del postulante.
+func (a Application) Thread() []Offer {
+	if len(a.Offers) > 0 {
+		return a.Offers
+	}
+	return []Offer{{
+		ProposedBy:        a.ApplicantID,
+		Price:             a.Price,
+		EstimatedMinutes:  a.EstimatedMinutes,
+		MaterialsIncluded: a.MaterialsIncluded,
+		Message:           a.Proposal,
+		CreatedAt:         a.AppliedAt,
+	}}
+}
+
+// CanCounter indica si userID puede hacer una contraoferta: las partes se turnan.
+func (a Application) CanCounter(userID primitive.ObjectID) error {
+	thread := a.Thread()
+	if thread[len(thread)-1].ProposedBy == userID {
+		return errors.New("esperá la respuesta a tu última oferta")
+	}
+	if len(thread) >= MaxOffersPerApplication {
+		return errors.New("la negociación alcanzó el máximo de ofertas")
+	}
+	return nil
+}
+
+// LatestOffer devuelve la última oferta; solo la puede aceptar la otra parte.
+func (a Application) LatestOffer(userID, offerID primitive.ObjectID) (Offer, error) {
+	thread := a.Thread()
+	latest := thread[len(thread)-1]
+	if latest.ID != offerID {
+		return Offer{}, errors.New("la oferta ya no es la vigente")
+	}
+	if latest.ProposedBy == userID {
+		return Offer{}, errors.New("la oferta la tiene que aceptar la otra parte")
+	}
+	return latest, nil
+}
+
+// WithAcceptedOffer devuelve la postulación con los términos de la oferta aceptada.
+func (a Application) WithAcceptedOffer(offer Offer) Application {
+	thread := append([]Offer(nil), a.Thread()...)
+	thread[len(thread)-1].Accepted = true
+	a.Offers = thread
+	a.Price = offer.Price
+	a.EstimatedMinutes = offer.EstimatedMinutes
+	a.MaterialsIncluded = offer.MaterialsIncluded
+	return a
+}
+
+// ForAssignment devuelve la postulación con la última oferta aceptada, como queda al asignar o
+// reasignar directamente al postulante: asignarlo es aceptar su última oferta, así que no se puede
+// mientras haya una contraoferta del empleador pendiente de respuesta.
+func (a Application) ForAssignment() (Application, error) {
+	thread := a.Thread()
+	latest := thread[len(thread)-1]
+	if latest.ProposedBy != a.ApplicantID {
+		return Application{}, errors.New("hay una contraoferta pendiente de respuesta del postulante")
+	}
+	return a.WithAcceptedOffer(latest), nil
+}
@@ -0,0 +1,76 @@
+package jobdomain
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestForAssignment(t *testing.T) {
+	applicantID := primitive.NewObjectID()
+	employerID := primitive.NewObjectID()
+	now := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+	offer := func(by primitive.ObjectID, price float64, minutes int) Offer {
+		return Offer{ID: primitive.NewObjectID(), ProposedBy: by, Price: price, EstimatedMinutes: minutes, CreatedAt: now}
+	}
+
+	tests := []struct {
+		name    string
+		app     Application
+		wantErr bool
+		price   float64
+		minutes int
+		offers  int
+	}{
+		{
+			name:    "postulación sin negociación",
+			app:     Application{ApplicantID: applicantID, Price: 1000, EstimatedMinutes: 60, AppliedAt: now},
+			price:   1000,
+			minutes: 60,
+			offers:  1,
+		},
+		{
+			name: "reasignación con precio negociado",
+			app: Application{ApplicantID: applicantID, Price: 1000, Offers: []Offer{
+				offer(applicantID, 1000, 60),
+				offer(employerID, 800, 60),
+				offer(applicantID, 900, 90),
+			}},
+			price:   900,
+			minutes: 90,
+			offers:  3,
+		},
+		{
+			name: "contraoferta del empleador pendiente",
+			app: Application{ApplicantID: applicantID, Price: 1000, Offers: []Offer{
+				offer(applicantID, 1000, 60),
+				offer(employerID, 800, 60),
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.app.ForAssignment()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("esperaba un error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if got.Price != tt.price || got.EstimatedMinutes != tt.minutes {
+				t.Errorf("términos = (%v, %d), esperaba (%v, %d)", got.Price, got.EstimatedMinutes, tt.price, tt.minutes)
+			}
+			if len(got.Offers) != tt.offers || !got.Offers[len(got.Offers)-1].Accepted {
+				t.Errorf("la última de %d ofertas debería quedar aceptada: %+v", tt.offers, got.Offers)
+			}
+			if len(tt.app.Offers) > 0 && tt.app.Offers[len(tt.app.Offers)-1].Accepted {
+				t.Error("no debería modificar las ofertas de la postulación original")
+			}
+		})
+	}
+}
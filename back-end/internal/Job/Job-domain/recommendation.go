@@ -0,0 +1,212 @@
+package jobdomain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RecommendationWeights pondera cada señal del ranking "para ti". No hace falta que sumen 1:
+// el puntaje final se normaliza por la suma de los pesos.
+type RecommendationWeights struct {
+	TagAffinity    float64 // Coincidencia con los tags del usuario y de sus postulaciones
+	Distance       float64 // Cercanía al usuario dentro de su radio
+	BudgetFit      float64 // Parecido con los presupuestos a los que suele postularse
+	Freshness      float64 // Antigüedad de la publicación
+	EmployerRating float64 // Reputación del empleador
+}
+
+// DefaultRecommendationWeights son los pesos usados cuando no están configurados.
+func DefaultRecommendationWeights() RecommendationWeights {
+	return RecommendationWeights{
+		TagAffinity:    0.35,
+		Distance:       0.25,
+		BudgetFit:      0.10,
+		Freshness:      0.20,
+		EmployerRating: 0.10,
+	}
+}
+
+func (w RecommendationWeights) total() float64 {
+	return w.TagAffinity + w.Distance + w.BudgetFit + w.Freshness + w.EmployerRating
+}
+
+// Peso de cada fuente en la afinidad por tags.
+const (
+	profileTagWeight   = 1.0
+	appliedTagWeight   = 0.5
+	completedTagWeight = 1.0
+)
+
+// freshnessHalfLife es la antigüedad a la que la señal de frescura vale la mitad.
+const freshnessHalfLife = 72 * time.Hour
+
+// neutralScore se usa cuando falta el dato de una señal (sin ubicación, sin historial, sin opiniones).
+const neutralScore = 0.5
+
+// RecommendationHistory es lo que el usuario hizo antes: sus postulaciones y trabajos completados.
+type RecommendationHistory struct {
+	ProfileTags   []string
+	AppliedTags   [][]string
+	CompletedTags [][]string
+	Budgets       []float64 // Presupuestos de los trabajos a los que se postuló o completó
+}
+
+// RecommendationProfile resume los intereses del usuario para puntuar trabajos.
+type RecommendationProfile struct {
+	UserID          primitive.ObjectID
+	TagAffinity     map[string]float64 // Tag en minúsculas -> afinidad en [0, 1]
+	RadiusMeters    float64            // Distancia a partir de la cual la señal de cercanía vale 0
+	PreferredBudget float64            // Mediana de Budgets; 0 si no hay historial
+}
+
+// NewRecommendationProfile construye el perfil a partir del historial del usuario.
+func NewRecommendationProfile(userID primitive.ObjectID, history RecommendationHistory, radiusMeters float64) RecommendationProfile {
+	affinity := map[string]float64{}
+	add := func(tags []string, weight float64) {
+		for _, tag := range tags {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				affinity[tag] += weight
+			}
+		}
+	}
+	add(history.ProfileTags, profileTagWeight)
+	for _, tags := range history.AppliedTags {
+		add(tags, appliedTagWeight)
+	}
+	for _, tags := range history.CompletedTags {
+		add(tags, completedTagWeight)
+	}
+
+	// Se normaliza para que el tag más fuerte valga 1
+	var max float64
+	for _, v := range affinity {
+		max = math.Max(max, v)
+	}
+	for tag := range affinity {
+		affinity[tag] /= max
+	}
+
+	return RecommendationProfile{
+		UserID:          userID,
+		TagAffinity:     affinity,
+		RadiusMeters:    radiusMeters,
+		PreferredBudget: median(history.Budgets),
+	}
+}
+
+// RecommendationCandidate es un trabajo abierto con los datos necesarios para puntuarlo.
+type RecommendationCandidate struct {
+	Job            JobDetailsUsers
+	DistanceMeters *float64 // nil si el usuario no tiene ubicación
+	EmployerRating float64  // Promedio de 1 a 5; 0 si el empleador no tiene opiniones
+}
+
+// Tags devuelve los tags con afinidad del perfil, ordenados, para filtrar los candidatos.
+func (p RecommendationProfile) Tags() []string {
+	tags := make([]string, 0, len(p.TagAffinity))
+	for tag := range p.TagAffinity {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ScoreJob puntúa un trabajo para el perfil dado. Devuelve un valor en [0, 1].
+func ScoreJob(profile RecommendationProfile, candidate RecommendationCandidate, weights RecommendationWeights, now time.Time) float64 {
+	total := weights.total()
+	if total <= 0 {
+		return 0
+	}
+	score := weights.TagAffinity*tagScore(profile, candidate.Job.Tags) +
+		weights.Distance*distanceScore(profile, candidate.DistanceMeters) +
+		weights.BudgetFit*budgetScore(profile, candidate.Job.Budget) +
+		weights.Freshness*freshnessScore(candidate.Job.CreatedAt, now) +
+		weights.EmployerRating*employerScore(candidate.EmployerRating)
+	return score / total
+}
+
+// RankJobs ordena los candidatos de mayor a menor puntaje; a igual puntaje, el más nuevo primero.
+// El puntaje queda en Score de cada trabajo.
+func RankJobs(profile RecommendationProfile, candidates []RecommendationCandidate, weights RecommendationWeights, now time.Time) []JobDetailsUsers {
+	jobs := make([]JobDetailsUsers, len(candidates))
+	for i, candidate := range candidates {
+		jobs[i] = candidate.Job
+		jobs[i].Score = ScoreJob(profile, candidate, weights, now)
+		jobs[i].DistanceMeters = candidate.DistanceMeters
+	}
+	sort.SliceStable(jobs, func(a, b int) bool {
+		if jobs[a].Score != jobs[b].Score {
+			return jobs[a].Score > jobs[b].Score
+		}
+		return jobs[a].CreatedAt.After(jobs[b].CreatedAt)
+	})
+	return jobs
+}
+
+// tagScore suma la afinidad de los tags del trabajo, con tope 1.
+func tagScore(profile RecommendationProfile, tags []string) float64 {
+	var score float64
+	for _, tag := range tags {
+		score += profile.TagAffinity[strings.ToLower(strings.TrimSpace(tag))]
+	}
+	return math.Min(score, 1)
+}
+
+// distanceScore decrece linealmente de 1 (en el lugar) a 0 (en el borde del radio).
+func distanceScore(profile RecommendationProfile, distance *float64) float64 {
+	if distance == nil || profile.RadiusMeters <= 0 {
+		return neutralScore
+	}
+	return clamp01(1 - *distance/profile.RadiusMeters)
+}
+
+// budgetScore es 1 si el presupuesto coincide con el habitual del usuario y baja con la diferencia relativa.
+func budgetScore(profile RecommendationProfile, budget float64) float64 {
+	if profile.PreferredBudget <= 0 || budget <= 0 {
+		return neutralScore
+	}
+	return clamp01(1 - math.Abs(budget-profile.PreferredBudget)/math.Max(budget, profile.PreferredBudget))
+}
+
+// freshnessScore decae a la mitad cada freshnessHalfLife.
+func freshnessScore(createdAt, now time.Time) float64 {
+	age := now.Sub(createdAt)
+	if age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(freshnessHalfLife))
+}
+
+// employerScore lleva el promedio de 1 a 5 a [0, 1]; los empleadores sin opiniones quedan neutros.
+func employerScore(rating float64) float64 {
+	if rating <= 0 {
+		return neutralScore
+	}
+	return clamp01((rating - 1) / 4)
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func median(values []float64) float64 {
+	var positive []float64
+	for _, v := range values {
+		if v > 0 {
+			positive = append(positive, v)
+		}
+	}
+	if len(positive) == 0 {
+		return 0
+	}
+	sort.Float64s(positive)
+	mid := len(positive) / 2
+	if len(positive)%2 == 0 {
+		return (positive[mid-1] + positive[mid]) / 2
+	}
+	return positive[mid]
+}
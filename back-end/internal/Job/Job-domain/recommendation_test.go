@@ -0,0 +1,310 @@
+package jobdomain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const epsilon = 1e-9
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < epsilon
+}
+
+func distance(meters float64) *float64 {
+	return &meters
+}
+
+func TestNewRecommendationProfile(t *testing.T) {
+	userID := primitive.NewObjectID()
+	tests := []struct {
+		name     string
+		history  RecommendationHistory
+		affinity map[string]float64
+		budget   float64
+	}{
+		{
+			name:     "sin historial",
+			history:  RecommendationHistory{},
+			affinity: map[string]float64{},
+			budget:   0,
+		},
+		{
+			name: "normaliza por el tag más fuerte",
+			history: RecommendationHistory{
+				ProfileTags:   []string{"Plomería"},
+				AppliedTags:   [][]string{{"plomería", "pintura"}},
+				CompletedTags: [][]string{{"jardinería"}},
+			},
+			affinity: map[string]float64{"plomería": 1, "pintura": 0.5 / 1.5, "jardinería": 1 / 1.5},
+		},
+		{
+			name: "ignora tags vacíos y espacios",
+			history: RecommendationHistory{
+				ProfileTags: []string{"  Pintura ", "", "   "},
+			},
+			affinity: map[string]float64{"pintura": 1},
+		},
+		{
+			name:     "mediana de presupuestos impares",
+			history:  RecommendationHistory{Budgets: []float64{3000, 1000, 2000}},
+			affinity: map[string]float64{},
+			budget:   2000,
+		},
+		{
+			name:     "mediana de presupuestos pares sin los no positivos",
+			history:  RecommendationHistory{Budgets: []float64{4000, 0, 1000, -5, 2000, 3000}},
+			affinity: map[string]float64{},
+			budget:   2500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := NewRecommendationProfile(userID, tt.history, 5000)
+			if profile.UserID != userID || profile.RadiusMeters != 5000 {
+				t.Fatalf("perfil con usuario o radio incorrecto: %+v", profile)
+			}
+			if len(profile.TagAffinity) != len(tt.affinity) {
+				t.Fatalf("TagAffinity = %v, se esperaba %v", profile.TagAffinity, tt.affinity)
+			}
+			for tag, want := range tt.affinity {
+				if got := profile.TagAffinity[tag]; !almostEqual(got, want) {
+					t.Errorf("TagAffinity[%q] = %v, se esperaba %v", tag, got, want)
+				}
+			}
+			if !almostEqual(profile.PreferredBudget, tt.budget) {
+				t.Errorf("PreferredBudget = %v, se esperaba %v", profile.PreferredBudget, tt.budget)
+			}
+		})
+	}
+}
+
+func TestRecommendationProfileTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile RecommendationProfile
+		want    []string
+	}{
+		{name: "sin afinidad", profile: RecommendationProfile{}, want: []string{}},
+		{
+			name:    "ordenados",
+			profile: RecommendationProfile{TagAffinity: map[string]float64{"plomería": 1, "electricidad": 0.2, "pintura": 0.5}},
+			want:    []string{"electricidad", "pintura", "plomería"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.profile.Tags()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Tags() = %v, se esperaba %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Tags() = %v, se esperaba %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestScoreJob(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	profile := RecommendationProfile{
+		TagAffinity:     map[string]float64{"plomería": 1, "pintura": 0.4},
+		RadiusMeters:    10000,
+		PreferredBudget: 2000,
+	}
+	tests := []struct {
+		name      string
+		profile   RecommendationProfile
+		candidate RecommendationCandidate
+		weights   RecommendationWeights
+		want      float64
+	}{
+		{
+			name:    "pesos en cero",
+			profile: profile,
+			weights: RecommendationWeights{},
+			want:    0,
+		},
+		{
+			name:      "afinidad por tags con tope 1",
+			profile:   profile,
+			candidate: RecommendationCandidate{Job: JobDetailsUsers{Tags: []string{"Plomería", "pintura"}}},
+			weights:   RecommendationWeights{TagAffinity: 1},
+			want:      1,
+		},
+		{
+			name:      "afinidad parcial",
+			profile:   profile,
+			candidate: RecommendationCandidate{Job: JobDetailsUsers{Tags: []string{"pintura", "electricidad"}}},
+			weights:   RecommendationWeights{TagAffinity: 1},
+			want:      0.4,
+		},
+		{
+			name:      "distancia a mitad del radio",
+			profile:   profile,
+			candidate: RecommendationCandidate{DistanceMeters: distance(5000)},
+			weights:   RecommendationWeights{Distance: 1},
+			want:      0.5,
+		},
+		{
+			name:      "distancia fuera del radio",
+			profile:   profile,
+			candidate: RecommendationCandidate{DistanceMeters: distance(20000)},
+			weights:   RecommendationWeights{Distance: 1},
+			want:      0,
+		},
+		{
+			name:      "sin ubicación es neutro",
+			profile:   profile,
+			candidate: RecommendationCandidate{},
+			weights:   RecommendationWeights{Distance: 1},
+			want:      neutralScore,
+		},
+		{
+			name:      "presupuesto igual al habitual",
+			profile:   profile,
+			candidate: RecommendationCandidate{Job: JobDetailsUsers{Budget: 2000}},
+			weights:   RecommendationWeights{BudgetFit: 1},
+			want:      1,
+		},
+		{
+			name:      "presupuesto del doble",
+			profile:   profile,
+			candidate: RecommendationCandidate{Job: JobDetailsUsers{Budget: 4000}},
+			weights:   RecommendationWeights{BudgetFit: 1},
+			want:      0.5,
+		},
+		{
+			name:      "sin presupuesto habitual es neutro",
+			profile:   RecommendationProfile{},
+			candidate: RecommendationCandidate{Job: JobDetailsUsers{Budget: 4000}},
+			weights:   RecommendationWeights{BudgetFit: 1},
+			want:      neutralScore,
+		},
+		{
+			name:      "recién publicado",
+			profile:   profile,
+			candidate: RecommendationCandidate{Job: JobDetailsUsers{CreatedAt: now}},
+			weights:   RecommendationWeights{Freshness: 1},
+			want:      1,
+		},
+		{
+			name:      "frescura a la vida media",
+			profile:   profile,
+			candidate: RecommendationCandidate{Job: JobDetailsUsers{CreatedAt: now.Add(-freshnessHalfLife)}},
+			weights:   RecommendationWeights{Freshness: 1},
+			want:      0.5,
+		},
+		{
+			name:      "empleador con 5 estrellas",
+			profile:   profile,
+			candidate: RecommendationCandidate{EmployerRating: 5},
+			weights:   RecommendationWeights{EmployerRating: 1},
+			want:      1,
+		},
+		{
+			name:      "empleador con 3 estrellas",
+			profile:   profile,
+			candidate: RecommendationCandidate{EmployerRating: 3},
+			weights:   RecommendationWeights{EmployerRating: 1},
+			want:      0.5,
+		},
+		{
+			name:      "empleador sin opiniones es neutro",
+			profile:   profile,
+			candidate: RecommendationCandidate{},
+			weights:   RecommendationWeights{EmployerRating: 1},
+			want:      neutralScore,
+		},
+		{
+			name:    "normaliza por la suma de los pesos",
+			profile: profile,
+			candidate: RecommendationCandidate{
+				Job:            JobDetailsUsers{Tags: []string{"plomería"}, CreatedAt: now},
+				DistanceMeters: distance(10000),
+			},
+			weights: RecommendationWeights{TagAffinity: 2, Distance: 1, Freshness: 1},
+			want:    (2*1 + 1*0 + 1*1) / 4.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScoreJob(tt.profile, tt.candidate, tt.weights, now); !almostEqual(got, tt.want) {
+				t.Errorf("ScoreJob() = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankJobs(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	profile := RecommendationProfile{TagAffinity: map[string]float64{"plomería": 1, "pintura": 0.5}}
+	weights := RecommendationWeights{TagAffinity: 1}
+
+	newer := primitive.NewObjectID()
+	older := primitive.NewObjectID()
+	best := primitive.NewObjectID()
+	worst := primitive.NewObjectID()
+
+	tests := []struct {
+		name       string
+		candidates []RecommendationCandidate
+		want       []primitive.ObjectID
+		scores     []float64
+	}{
+		{
+			name:       "sin candidatos",
+			candidates: nil,
+			want:       []primitive.ObjectID{},
+			scores:     []float64{},
+		},
+		{
+			name: "mayor puntaje primero",
+			candidates: []RecommendationCandidate{
+				{Job: JobDetailsUsers{ID: worst, Tags: []string{"electricidad"}}},
+				{Job: JobDetailsUsers{ID: best, Tags: []string{"plomería"}}},
+				{Job: JobDetailsUsers{ID: older, Tags: []string{"pintura"}}},
+			},
+			want:   []primitive.ObjectID{best, older, worst},
+			scores: []float64{1, 0.5, 0},
+		},
+		{
+			name: "a igual puntaje el más nuevo primero",
+			candidates: []RecommendationCandidate{
+				{Job: JobDetailsUsers{ID: older, Tags: []string{"pintura"}, CreatedAt: now.Add(-2 * time.Hour)}},
+				{Job: JobDetailsUsers{ID: newer, Tags: []string{"pintura"}, CreatedAt: now.Add(-time.Hour)}},
+			},
+			want:   []primitive.ObjectID{newer, older},
+			scores: []float64{0.5, 0.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := RankJobs(profile, tt.candidates, weights, now)
+			if len(jobs) != len(tt.want) {
+				t.Fatalf("RankJobs() devolvió %d trabajos, se esperaban %d", len(jobs), len(tt.want))
+			}
+			for i, job := range jobs {
+				if job.ID != tt.want[i] {
+					t.Errorf("posición %d: ID = %v, se esperaba %v", i, job.ID, tt.want[i])
+				}
+				if !almostEqual(job.Score, tt.scores[i]) {
+					t.Errorf("posición %d: Score = %v, se esperaba %v", i, job.Score, tt.scores[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRankJobsKeepsDistance(t *testing.T) {
+	meters := distance(1500)
+	jobs := RankJobs(RecommendationProfile{}, []RecommendationCandidate{{DistanceMeters: meters}}, DefaultRecommendationWeights(), time.Now())
+	if len(jobs) != 1 || jobs[0].DistanceMeters != meters {
+		t.Fatalf("RankJobs() no conservó la distancia del candidato: %+v", jobs)
+	}
+}
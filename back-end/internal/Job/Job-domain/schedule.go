@@ -0,0 +1,29 @@
+package jobdomain
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxSlotDuration es la duración máxima de un horario propuesto para un trabajo.
+const MaxSlotDuration = 12 * time.Hour
+
+// TimeSlot es el horario [Start, End) propuesto por el empleador para realizar el trabajo.
+type TimeSlot struct {
+	Start time.Time `json:"start" bson:"start"`
+	End   time.Time `json:"end" bson:"end"`
+}
+
+// Validate exige un horario futuro, que termine después de empezar y no dure más de MaxSlotDuration.
+func (s TimeSlot) Validate(now time.Time) error {
+	if !s.End.After(s.Start) {
+		return errors.New("el horario debe terminar después de empezar")
+	}
+	if s.End.Sub(s.Start) > MaxSlotDuration {
+		return errors.New("el horario no puede durar más de 12 horas")
+	}
+	if !s.Start.After(now) {
+		return errors.New("el horario debe ser futuro")
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package jobinfrastructure
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (j *JobRepository) alertsCollection() *mongo.Collection {
+	return j.mongoClient.Database("NEXO-VECINAL").Collection("JobAlerts")
+}
+
+// EnsureAlertIndexes crea los índices de las alertas de trabajos.
+func (j *JobRepository) EnsureAlertIndexes(ctx context.Context) error {
+	_, err := j.alertsCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "location", Value: "2dsphere"}}},
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+	})
+	return err
+}
+
+// alertCountersCollection guarda cuántas alertas tiene cada usuario ({_id: userId, count}).
+func (j *JobRepository) alertCountersCollection() *mongo.Collection {
+	return j.mongoClient.Database("NEXO-VECINAL").Collection("JobAlertCounters")
+}
+
+// SyncAlertCounters recalcula los contadores de alertas a partir de las alertas guardadas.
+// Se ejecuta al iniciar para que los usuarios con alertas previas arranquen con el conteo real.
+func (j *JobRepository) SyncAlertCounters(ctx context.Context) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$userId", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           "JobAlertCounters",
+			"on":             "_id",
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}}},
+	}
+	cursor, err := j.alertsCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+var errAlertLimit = errors.New("límite de alertas alcanzado")
+
+// CreateJobAlert guarda una alerta respetando el máximo por usuario. El contador se incrementa
+// solo si está por debajo del máximo; si ya lo alcanzó, el upsert choca con el documento existente.
+func (j *JobRepository) CreateJobAlert(ctx context.Context, alert jobdomain.JobAlert) (primitive.ObjectID, error) {
+	alert.ID = primitive.NewObjectID()
+	err := j.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		_, err := j.alertCountersCollection().UpdateOne(sessCtx,
+			bson.M{"_id": alert.UserID, "count": bson.M{"$lt": jobdomain.MaxAlertsPerUser}},
+			bson.M{"$inc": bson.M{"count": 1}},
+			options.Update().SetUpsert(true),
+		)
+		if mongo.IsDuplicateKeyError(err) {
+			return errAlertLimit
+		}
+		if err != nil {
+			return err
+		}
+		_, err = j.alertsCollection().InsertOne(sessCtx, alert)
+		return err
+	})
+	if errors.Is(err, errAlertLimit) {
+		return primitive.NilObjectID, fmt.Errorf("se permiten hasta %d alertas por usuario", jobdomain.MaxAlertsPerUser)
+	}
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return alert.ID, nil
+}
+
+// ListJobAlerts devuelve las alertas del usuario, más nuevas primero.
+func (j *JobRepository) ListJobAlerts(ctx context.Context, userID primitive.ObjectID) ([]jobdomain.JobAlert, error) {
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+	cursor, err := j.alertsCollection().Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	alerts := []jobdomain.JobAlert{}
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// UpdateJobAlert aplica set sobre una alerta del usuario.
+func (j *JobRepository) UpdateJobAlert(ctx context.Context, userID, alertID primitive.ObjectID, set bson.M) error {
+	set["updatedAt"] = time.Now()
+	res, err := j.alertsCollection().UpdateOne(ctx, bson.M{"_id": alertID, "userId": userID}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("alerta no encontrada")
+	}
+	return nil
+}
+
+// DeleteJobAlert elimina una alerta del usuario.
+func (j *JobRepository) DeleteJobAlert(ctx context.Context, userID, alertID primitive.ObjectID) error {
+	return j.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		res, err := j.alertsCollection().DeleteOne(sessCtx, bson.M{"_id": alertID, "userId": userID})
+		if err != nil {
+			return err
+		}
+		if res.DeletedCount == 0 {
+			return errors.New("alerta no encontrada")
+		}
+		_, err = j.alertCountersCollection().UpdateOne(sessCtx,
+			bson.M{"_id": userID, "count": bson.M{"$gt": 0}},
+			bson.M{"$inc": bson.M{"count": -1}},
+		)
+		return err
+	})
+}
+
+// FindActiveAlertsNear devuelve las alertas activas cuyo radio incluye la ubicación dada.
+// El resto de los filtros se evalúa con JobAlert.Matches.
+func (j *JobRepository) FindActiveAlertsNear(ctx context.Context, location jobdomain.GeoPoint) ([]jobdomain.JobAlert, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: bson.M{
+			"near":          bson.M{"type": "Point", "coordinates": location.Coordinates},
+			"distanceField": "distance",
+			"maxDistance":   100000, // Radio máximo permitido en un filtro
+			"query":         bson.M{"paused": false},
+			"spherical":     true,
+		}}},
+		{{Key: "$match", Value: bson.M{
+			"$expr": bson.M{"$lte": bson.A{"$distance", "$filter.radius"}},
+		}}},
+		{{Key: "$limit", Value: 1000}},
+	}
+	cursor, err := j.alertsCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var alerts []jobdomain.JobAlert
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
@@ -0,0 +1,259 @@
+package jobinfrastructure
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/pkg/outbox"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrActiveAppointment indica que el trabajo ya tiene una cita propuesta o aceptada.
+var ErrActiveAppointment = errors.New("el trabajo ya tiene una cita activa; reprogramala o cancelala")
+
+func (r *JobRepository) appointmentsColl() *mongo.Collection {
+	return r.mongoClient.Database("NEXO-VECINAL").Collection("JobAppointments")
+}
+
+func (r *JobRepository) calendarFeedsColl() *mongo.Collection {
+	return r.mongoClient.Database("NEXO-VECINAL").Collection("CalendarFeeds")
+}
+
+// EnsureAppointmentIndexes crea los índices de citas y de tokens de calendario.
+func (r *JobRepository) EnsureAppointmentIndexes(ctx context.Context) error {
+	_, err := r.appointmentsColl().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "jobId", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"active": true}).SetName("job_active_appointment"),
+		},
+		{Keys: bson.D{{Key: "employerId", Value: 1}, {Key: "slot.start", Value: 1}}},
+		{Keys: bson.D{{Key: "workerId", Value: 1}, {Key: "slot.start", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "slot.start", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.calendarFeedsColl().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "token", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func newAppointmentEvent(userID primitive.ObjectID, title, message string, appointment jobdomain.Appointment) (outbox.Event, error) {
+	return outbox.NewEvent(jobdomain.EventUserNotification, jobdomain.UserNotificationEvent{
+		UserID:  userID,
+		Type:    string(notificationdomain.TypeAppointment),
+		Title:   title,
+		Message: message,
+		Data:    map[string]string{"jobId": appointment.JobID.Hex(), "appointmentId": appointment.ID.Hex()},
+	})
+}
+
+// CreateAppointment guarda una cita propuesta y avisa a notifyUserID en la misma transacción.
+func (r *JobRepository) CreateAppointment(ctx context.Context, appointment jobdomain.Appointment, notifyUserID primitive.ObjectID, title, message string) (primitive.ObjectID, error) {
+	appointment.ID = primitive.NewObjectID()
+	event, err := newAppointmentEvent(notifyUserID, title, message, appointment)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	err = r.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		if _, err := r.appointmentsColl().InsertOne(sessCtx, appointment); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return ErrActiveAppointment
+			}
+			return err
+		}
+		return r.outbox.Record(sessCtx, event)
+	})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return appointment.ID, nil
+}
+
+// GetAppointment devuelve una cita por su ID.
+func (r *JobRepository) GetAppointment(ctx context.Context, appointmentID primitive.ObjectID) (*jobdomain.Appointment, error) {
+	var appointment jobdomain.Appointment
+	if err := r.appointmentsColl().FindOne(ctx, bson.M{"_id": appointmentID}).Decode(&appointment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("cita no encontrada")
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// UpdateAppointmentAndNotify aplica update a la cita si sigue en el estado leído (filter),
+// actualiza el horario acordado del trabajo (slot nil lo quita) y avisa a notifyUserID,
+// todo en una transacción.
+func (r *JobRepository) UpdateAppointmentAndNotify(ctx context.Context, appointment jobdomain.Appointment, filter, update bson.M, slot *jobdomain.TimeSlot, notifyUserID primitive.ObjectID, title, message string) error {
+	event, err := newAppointmentEvent(notifyUserID, title, message, appointment)
+	if err != nil {
+		return err
+	}
+	jobUpdate := bson.M{"$unset": bson.M{"scheduledSlot": ""}}
+	if slot != nil {
+		jobUpdate = bson.M{"$set": bson.M{"scheduledSlot": slot}}
+	}
+	jobColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	return r.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		filter["_id"] = appointment.ID
+		result, err := r.appointmentsColl().UpdateOne(sessCtx, filter, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("la cita cambió mientras tanto; volvé a intentarlo")
+		}
+		if _, err := jobColl.UpdateOne(sessCtx, bson.M{"_id": appointment.JobID}, jobUpdate); err != nil {
+			return err
+		}
+		return r.outbox.Record(sessCtx, event)
+	})
+}
+
+// cancelJobAppointments cancela las citas activas del trabajo, por ejemplo al reasignarlo o
+// completarlo. Debe llamarse con el sessCtx de la transacción que cambia el trabajo.
+func (r *JobRepository) cancelJobAppointments(ctx context.Context, jobID primitive.ObjectID) error {
+	_, err := r.appointmentsColl().UpdateMany(ctx,
+		bson.M{"jobId": jobID, "active": true},
+		bson.M{
+			"$set": bson.M{"status": jobdomain.AppointmentCancelled, "active": false, "updatedAt": time.Now()},
+			"$inc": bson.M{"sequence": 1},
+		},
+	)
+	return err
+}
+
+// ListJobAppointments devuelve las citas de un trabajo, la más reciente primero.
+func (r *JobRepository) ListJobAppointments(ctx context.Context, jobID primitive.ObjectID) ([]jobdomain.Appointment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	return r.findAppointments(ctx, bson.M{"jobId": jobID}, opts)
+}
+
+// ListUserAppointments devuelve las citas del usuario que terminan después de from,
+// ordenadas por fecha. Con includeCancelled se incluyen también las canceladas.
+func (r *JobRepository) ListUserAppointments(ctx context.Context, userID primitive.ObjectID, from time.Time, includeCancelled bool) ([]jobdomain.Appointment, error) {
+	filter := bson.M{
+		"$or":      []bson.M{{"employerId": userID}, {"workerId": userID}},
+		"slot.end": bson.M{"$gt": from},
+	}
+	if !includeCancelled {
+		filter["status"] = bson.M{"$ne": jobdomain.AppointmentCancelled}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "slot.start", Value: 1}}).SetLimit(200)
+	return r.findAppointments(ctx, filter, opts)
+}
+
+// FindAppointmentsForReminders devuelve las citas aceptadas que empiezan antes de until
+// y todavía no recibieron todos sus recordatorios.
+func (r *JobRepository) FindAppointmentsForReminders(ctx context.Context, now, until time.Time) ([]jobdomain.Appointment, error) {
+	filter := bson.M{
+		"status":        jobdomain.AppointmentAccepted,
+		"slot.start":    bson.M{"$gt": now, "$lte": until},
+		"remindersSent": bson.M{"$lt": len(jobdomain.AppointmentReminderLeads)},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "slot.start", Value: 1}}).SetLimit(200)
+	return r.findAppointments(ctx, filter, opts)
+}
+
+func (r *JobRepository) findAppointments(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]jobdomain.Appointment, error) {
+	cursor, err := r.appointmentsColl().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	appointments := []jobdomain.Appointment{}
+	if err := cursor.All(ctx, &appointments); err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// MarkAppointmentReminder registra el recordatorio y encola los avisos a ambas partes en una
+// transacción. Si otro proceso ya lo envió no hace nada.
+func (r *JobRepository) MarkAppointmentReminder(ctx context.Context, appointment jobdomain.Appointment, remindersSent int, title, message string) error {
+	var events []outbox.Event
+	for _, userID := range []primitive.ObjectID{appointment.EmployerID, appointment.WorkerID} {
+		event, err := newAppointmentEvent(userID, title, message, appointment)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+	return r.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := r.appointmentsColl().UpdateOne(sessCtx,
+			bson.M{"_id": appointment.ID, "remindersSent": appointment.RemindersSent, "sequence": appointment.Sequence},
+			bson.M{"$set": bson.M{"remindersSent": remindersSent}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return nil
+		}
+		for _, event := range events {
+			if err := r.outbox.Record(sessCtx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetCalendarToken devuelve el token del feed iCalendar del usuario, creándolo si no existe.
+func (r *JobRepository) GetCalendarToken(ctx context.Context, userID primitive.ObjectID) (string, error) {
+	var feed struct {
+		Token string `bson:"token"`
+	}
+	err := r.calendarFeedsColl().FindOne(ctx, bson.M{"_id": userID}).Decode(&feed)
+	if err == nil {
+		return feed.Token, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return "", err
+	}
+	return r.RotateCalendarToken(ctx, userID)
+}
+
+// RotateCalendarToken genera un token nuevo e invalida el anterior.
+func (r *JobRepository) RotateCalendarToken(ctx context.Context, userID primitive.ObjectID) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+	_, err := r.calendarFeedsColl().UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"token": token, "updatedAt": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// FindUserByCalendarToken devuelve el dueño del token del feed.
+func (r *JobRepository) FindUserByCalendarToken(ctx context.Context, token string) (primitive.ObjectID, error) {
+	var feed struct {
+		UserID primitive.ObjectID `bson:"_id"`
+	}
+	if err := r.calendarFeedsColl().FindOne(ctx, bson.M{"token": token}).Decode(&feed); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return primitive.NilObjectID, errors.New("calendario no encontrado")
+		}
+		return primitive.NilObjectID, err
+	}
+	return feed.UserID, nil
+}
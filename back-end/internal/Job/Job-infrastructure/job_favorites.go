@@ -0,0 +1,17 @@
+package jobinfrastructure
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FavoriteJobIDs indica cuáles de los trabajos dados son favoritos de userID.
+func (r *JobRepository) FavoriteJobIDs(ctx context.Context, userID primitive.ObjectID, jobIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	return r.favorites.FavoriteJobIDs(ctx, userID, jobIDs)
+}
+
+// IsFavoriteWorker indica si workerID está en los trabajadores favoritos de userID.
+func (r *JobRepository) IsFavoriteWorker(ctx context.Context, userID, workerID primitive.ObjectID) (bool, error) {
+	return r.favorites.IsFavoriteWorker(ctx, userID, workerID)
+}
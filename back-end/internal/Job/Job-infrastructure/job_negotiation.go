@@ -0,0 +1,103 @@
+package jobinfrastructure
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/pkg/outbox"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// negotiationFilter selecciona el trabajo abierto cuya postulación de applicantID todavía tiene
+// storedOffers ofertas guardadas, para no pisar una oferta hecha mientras tanto.
+func negotiationFilter(jobID, applicantID primitive.ObjectID, storedOffers int) bson.M {
+	return bson.M{
+		"_id":    jobID,
+		"status": jobdomain.JobStatusOpen,
+		"applicants": bson.M{"$elemMatch": bson.M{
+			"applicantId":                          applicantID,
+			"offers." + strconv.Itoa(storedOffers): bson.M{"$exists": false},
+		}},
+	}
+}
+
+// SaveOffer guarda la negociación de la postulación con la nueva oferta y avisa a notifyUserID
+// en la misma transacción. storedOffers es la cantidad de ofertas leídas antes de agregarla.
+func (j *JobRepository) SaveOffer(ctx context.Context, jobID, applicantID primitive.ObjectID, storedOffers int, thread []jobdomain.Offer, notifyUserID primitive.ObjectID, title, message string) error {
+	event, err := newUserNotificationEvent(notifyUserID, notificationdomain.TypeOffer, title, message, jobID)
+	if err != nil {
+		return err
+	}
+	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	latest := thread[len(thread)-1]
+	update := bson.M{"$set": bson.M{
+		"applicants.$.offers":            thread,
+		"applicants.$.estimatedMinutes":  latest.EstimatedMinutes,
+		"applicants.$.materialsIncluded": latest.MaterialsIncluded,
+		"updatedAt":                      time.Now(),
+	}}
+	return j.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := jobColl.UpdateOne(sessCtx, negotiationFilter(jobID, applicantID, storedOffers), update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("la negociación cambió o el trabajo ya no está abierto; volvé a intentarlo")
+		}
+		return j.outbox.Record(sessCtx, event)
+	})
+}
+
+// AcceptOffer asigna el trabajo con los términos de la oferta aceptada, que pasan a ser la
+// assignedApplication y el finalCost. Avisa al trabajador de la asignación y, si se indica
+// notifyUserID, le avisa también que se aceptó su oferta.
+func (j *JobRepository) AcceptOffer(ctx context.Context, job *jobdomain.Job, application jobdomain.Application, storedOffers int, notifyUserID primitive.ObjectID, title, message string) error {
+	events := make([]outbox.Event, 0, 2)
+	assigned, err := outbox.NewEvent(jobdomain.EventJobAssigned, jobdomain.JobAssignedEvent{WorkerID: application.ApplicantID, JobTitle: job.Title})
+	if err != nil {
+		return err
+	}
+	events = append(events, assigned)
+	if !notifyUserID.IsZero() {
+		accepted, err := newUserNotificationEvent(notifyUserID, notificationdomain.TypeOffer, title, message, job.ID)
+		if err != nil {
+			return err
+		}
+		events = append(events, accepted)
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"assignedApplication": application,
+			"finalCost":           application.Price,
+			"status":              jobdomain.JobStatusInProgress,
+			"updatedAt":           now,
+			"inProgressSince":     now,
+		},
+		"$unset": bson.M{"reminderSentAt": "", "closeNoticeAt": ""},
+		"$pull":  bson.M{"applicants": bson.M{"applicantId": application.ApplicantID}},
+	}
+	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	return j.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := jobColl.UpdateOne(sessCtx, negotiationFilter(job.ID, application.ApplicantID, storedOffers), update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("la negociación cambió o el trabajo ya no está abierto; volvé a intentarlo")
+		}
+		for _, event := range events {
+			if err := j.outbox.Record(sessCtx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,60 @@
+package jobinfrastructure
+
+import (
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/pkg/push"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SaveNotifications guarda notificaciones en el centro de notificaciones del usuario.
+func (j *JobRepository) SaveNotifications(ctx context.Context, notifications ...notificationdomain.Notification) error {
+	return j.notifications.Create(ctx, notifications...)
+}
+
+// PushedUsers devuelve cuáles de userIDs ya recibieron el push del evento sourceID.
+func (j *JobRepository) PushedUsers(ctx context.Context, sourceID string, userIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	return j.notifications.PushedUsers(ctx, sourceID, userIDs)
+}
+
+// MarkPushed registra que userIDs ya recibieron el push del evento sourceID.
+func (j *JobRepository) MarkPushed(ctx context.Context, sourceID string, userIDs ...primitive.ObjectID) error {
+	return j.notifications.MarkPushed(ctx, sourceID, userIDs...)
+}
+
+// QueueJobDigest agrega el trabajo al resumen de nuevos trabajos del usuario.
+func (j *JobRepository) QueueJobDigest(ctx context.Context, userID, jobID primitive.ObjectID, title string) error {
+	return j.notifications.QueueDigest(ctx, userID, notificationdomain.DigestItem{JobID: jobID, Title: title})
+}
+
+// QueueAlertDigest agrega el trabajo encontrado por una alerta diaria al resumen del usuario.
+func (j *JobRepository) QueueAlertDigest(ctx context.Context, userID, alertID, jobID primitive.ObjectID, title string) error {
+	return j.notifications.QueueDigest(ctx, userID, notificationdomain.DigestItem{JobID: jobID, Title: title, AlertID: alertID})
+}
+
+// GetNotificationPreferences devuelve las preferencias de notificaciones del usuario.
+func (j *JobRepository) GetNotificationPreferences(ctx context.Context, userID primitive.ObjectID) (notificationdomain.Preferences, error) {
+	return j.notifications.GetPreferences(ctx, userID)
+}
+
+// TakeDailyPush consume un push del límite diario del usuario, una sola vez por sourceID.
+func (j *JobRepository) TakeDailyPush(ctx context.Context, userID primitive.ObjectID, prefs notificationdomain.Preferences, sourceID string, now time.Time) (bool, error) {
+	return j.notifications.TakeDailyPush(ctx, userID, prefs, sourceID, now)
+}
+
+// DeferPush pospone el push hasta que termine el horario silencioso del usuario.
+func (j *JobRepository) DeferPush(ctx context.Context, userID primitive.ObjectID, category notificationdomain.Category, n push.Notification, prefs notificationdomain.Preferences, now time.Time) error {
+	return j.notifications.DeferPush(ctx, userID, category, n, prefs, now)
+}
+
+// pushAllowedForType aplica las preferencias del usuario a los tipos que se pueden silenciar;
+// en horario silencioso el push n queda pospuesto.
+func (j *JobRepository) pushAllowedForType(ctx context.Context, userID primitive.ObjectID, t notificationdomain.NotificationType, n push.Notification) (bool, error) {
+	category, ok := notificationdomain.CategoryForType(t)
+	if !ok {
+		return true, nil
+	}
+	return j.notifications.PushAllowed(ctx, userID, category, n, time.Now())
+}
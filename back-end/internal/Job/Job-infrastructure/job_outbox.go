@@ -0,0 +1,78 @@
+package jobinfrastructure
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// skipWithoutToken descarta el evento si el destinatario no tiene push token: reintentar no lo resolvería.
+func skipWithoutToken(err error) error {
+	if errors.Is(err, push.ErrNoTokens) {
+		return nil
+	}
+	return err
+}
+
+// RegisterOutboxHandlers registra los handlers de los eventos que se resuelven en el repositorio.
+func (j *JobRepository) RegisterOutboxHandlers(d *outbox.Dispatcher) {
+	d.Handle(jobdomain.EventJobAssigned, func(ctx context.Context, event outbox.Event) error {
+		var payload jobdomain.JobAssignedEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		err := j.SaveNotifications(ctx, notificationdomain.Notification{
+			UserID:   payload.WorkerID,
+			Type:     notificationdomain.TypeJobAssigned,
+			Title:    "Trabajo asignado",
+			Body:     fmt.Sprintf("Has sido asignado al trabajo '%s'", payload.JobTitle),
+			Data:     map[string]string{"jobTitle": payload.JobTitle},
+			SourceID: event.ID.Hex(),
+		})
+		if err != nil {
+			return err
+		}
+		return skipWithoutToken(j.notifyWorker(payload.WorkerID, payload.JobTitle))
+	})
+	d.Handle(jobdomain.EventUserNotification, func(ctx context.Context, event outbox.Event) error {
+		var payload jobdomain.UserNotificationEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		err := j.SaveNotifications(ctx, notificationdomain.Notification{
+			UserID:   payload.UserID,
+			Type:     notificationdomain.NotificationType(payload.Type),
+			Title:    payload.Title,
+			Body:     payload.Message,
+			Data:     payload.Data,
+			SourceID: event.ID.Hex(),
+		})
+		if err != nil {
+			return err
+		}
+		n := push.Notification{Title: payload.Title, Body: payload.Message, ChannelID: "jobs"}
+		allowed, err := j.pushAllowedForType(ctx, payload.UserID, notificationdomain.NotificationType(payload.Type), n)
+		if err != nil || !allowed {
+			return err
+		}
+		return skipWithoutToken(j.SendNotificationToWorker(payload.UserID, payload.Title, payload.Message))
+	})
+	d.Handle(jobdomain.EventJobPublishedMetrics, func(ctx context.Context, event outbox.Event) error {
+		var payload jobdomain.JobMetricsEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		return j.RegisterJobPublicationMetricts(payload.Gender, payload.BirthDate)
+	})
+	d.Handle(jobdomain.EventJobCompletedMetrics, func(ctx context.Context, event outbox.Event) error {
+		var payload jobdomain.JobMetricsEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		return j.RegisterJobCompletionMetrics(payload.Gender, payload.BirthDate)
+	})
+}
@@ -0,0 +1,164 @@
+package jobinfrastructure
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"context"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// historyLimit acota los trabajos del historial que se leen para armar el perfil.
+const historyLimit = 100
+
+// RecommendationUser son los datos del usuario que usa el motor de recomendaciones.
+type RecommendationUser struct {
+	Tags     []string           `bson:"tags"`
+	Location jobdomain.GeoPoint `bson:"location"`
+	Ratio    float64            `bson:"ratio"` // Radio de búsqueda en metros
+}
+
+// GetRecommendationUser devuelve los tags, la ubicación y el radio del usuario.
+func (r *JobRepository) GetRecommendationUser(ctx context.Context, userID primitive.ObjectID) (*RecommendationUser, error) {
+	userColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	opts := options.FindOne().SetProjection(bson.M{"tags": 1, "location": 1, "ratio": 1})
+
+	var user RecommendationUser
+	if err := userColl.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetRecommendationHistory devuelve los tags y presupuestos de los últimos trabajos
+// a los que el usuario se postuló o que completó.
+func (r *JobRepository) GetRecommendationHistory(ctx context.Context, userID primitive.ObjectID) (jobdomain.RecommendationHistory, error) {
+	jobColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	filter := bson.M{"$or": []bson.M{
+		{"applicants.applicantId": userID},
+		{"assignedApplication.applicantId": userID, "status": jobdomain.JobStatusCompleted},
+	}}
+	opts := options.Find().
+		SetProjection(bson.M{"tags": 1, "budget": 1, "status": 1, "assignedApplication.applicantId": 1}).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(historyLimit)
+
+	var history jobdomain.RecommendationHistory
+	cursor, err := jobColl.Find(ctx, filter, opts)
+	if err != nil {
+		return history, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []jobdomain.Job
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return history, err
+	}
+	for _, job := range jobs {
+		completed := job.Status == jobdomain.JobStatusCompleted &&
+			job.AssignedApplication != nil && job.AssignedApplication.ApplicantID == userID
+		if completed {
+			history.CompletedTags = append(history.CompletedTags, job.Tags)
+		} else {
+			history.AppliedTags = append(history.AppliedTags, job.Tags)
+		}
+		history.Budgets = append(history.Budgets, job.Budget)
+	}
+	return history, nil
+}
+
+// FindRecommendationCandidates devuelve hasta limit trabajos abiertos que el usuario puede tomar:
+// ni propios, ni solicitudes directas, ni trabajos a los que ya se postuló. Si hay tags, solo los
+// que comparten alguno (sin distinguir mayúsculas). Con ubicación se limitan a radiusMeters, se
+// toman los más cercanos y traen distanceMeters (sin redondear); sin ubicación, los más nuevos.
+// Cada candidato incluye la reputación de su empleador (promedio de sus últimos 10 trabajos completados).
+func (r *JobRepository) FindRecommendationCandidates(ctx context.Context, userID primitive.ObjectID, location jobdomain.GeoPoint, radiusMeters float64, tags []string, limit int) ([]jobdomain.RecommendationCandidate, error) {
+	jobColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	query := bson.M{
+		"status":                 jobdomain.JobStatusOpen,
+		"available":              true,
+		"jobType":                bson.M{"$ne": "solicitud"},
+		"userId":                 bson.M{"$ne": userID},
+		"applicants.applicantId": bson.M{"$ne": userID},
+		// Los jobs vencidos no se recomiendan aunque el proceso de políticas aún no los haya cerrado
+		"$or": []bson.M{
+			{"expiresAt": bson.M{"$gt": time.Now()}},
+			{"expiresAt": bson.M{"$exists": false}},
+		},
+	}
+	// El filtro por tags va antes del límite para que el ranking puntúe los trabajos afines
+	if len(tags) > 0 {
+		patterns := make(bson.A, len(tags))
+		for i, tag := range tags {
+			patterns[i] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(tag) + "$", Options: "i"}
+		}
+		query["tags"] = bson.M{"$in": patterns}
+	}
+
+	var pipeline mongo.Pipeline
+	if len(location.Coordinates) == 2 {
+		pipeline = mongo.Pipeline{
+			{{Key: "$geoNear", Value: bson.M{
+				"near":          bson.M{"type": "Point", "coordinates": location.Coordinates},
+				"distanceField": "distanceMeters",
+				"maxDistance":   radiusMeters,
+				"query":         query,
+				"spherical":     true,
+			}}},
+		}
+	} else {
+		pipeline = mongo.Pipeline{
+			{{Key: "$match", Value: query}},
+			{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		}
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	pipeline = append(pipeline, r.getBaseJobPipeline()...)
+	pipeline = append(pipeline,
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from": "Job",
+			"let":  bson.M{"employer": "$userDetails._id"},
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{
+					"$expr":                 bson.M{"$eq": bson.A{"$userId", "$$employer"}},
+					"status":                jobdomain.JobStatusCompleted,
+					"workerFeedback.rating": bson.M{"$gt": 0},
+				}}},
+				{{Key: "$sort", Value: bson.M{"createdAt": -1}}},
+				{{Key: "$limit", Value: 10}},
+				{{Key: "$group", Value: bson.M{"_id": nil, "rating": bson.M{"$avg": "$workerFeedback.rating"}}}},
+			},
+			"as": "employerRating",
+		}}},
+		bson.D{{Key: "$addFields", Value: bson.M{
+			"employerRating": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$employerRating.rating", 0}}, 0}},
+		}}},
+	)
+
+	cursor, err := jobColl.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		jobdomain.JobDetailsUsers `bson:",inline"`
+		EmployerRating            float64 `bson:"employerRating"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	candidates := make([]jobdomain.RecommendationCandidate, len(docs))
+	for i, doc := range docs {
+		candidates[i] = jobdomain.RecommendationCandidate{
+			Job:            doc.JobDetailsUsers,
+			DistanceMeters: doc.DistanceMeters,
+			EmployerRating: doc.EmployerRating,
+		}
+	}
+	return candidates, nil
+}
@@ -2,15 +2,20 @@ package jobinfrastructure
 
 import (
 	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/internal/favorites/favoritesinfrastructure"
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/internal/notifications/notificationinfrastructure"
 	userdomain "back-end/internal/user/user-domain"
 	"back-end/pkg/metrics"
-	"bytes"
+	"back-end/pkg/moderation"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"back-end/pkg/sanctions"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
-	"net/http"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -23,12 +28,27 @@ import (
 type JobRepository struct {
 	redisClient *redis.Client
 	mongoClient *mongo.Client
+	outbox      *outbox.Store
+	push        *push.Service
+	moderator   *moderation.Moderator
+	sanctions   *sanctions.Store
+	// notifications persiste las notificaciones del centro de notificaciones
+	notifications *notificationinfrastructure.NotificationRepository
+	// favorites resuelve los trabajos y trabajadores favoritos de cada usuario
+	favorites *favoritesinfrastructure.FavoriteRepository
 }
 
 func NewjobRepository(redisClient *redis.Client, mongoClient *mongo.Client) *JobRepository {
 	return &JobRepository{
 		redisClient: redisClient,
 		mongoClient: mongoClient,
+		outbox:      outbox.NewStore(mongoClient),
+		push:        push.NewDefaultService(mongoClient),
+		moderator:   moderation.NewDefaultModerator(mongoClient),
+		sanctions:   sanctions.NewStore(mongoClient),
+
+		notifications: notificationinfrastructure.NewNotificationRepository(mongoClient, redisClient),
+		favorites:     favoritesinfrastructure.NewFavoriteRepository(mongoClient),
 	}
 }
 
@@ -41,22 +61,98 @@ func (t *JobRepository) CreateJob(Tweet jobdomain.Job) (primitive.ObjectID, erro
 		return primitive.ObjectID{}, errors.New("without permission")
 
 	}
+	if err := t.sanctions.Check(context.Background(), Tweet.UserID, sanctions.ScopePosting); err != nil {
+		return primitive.ObjectID{}, err
+	}
+	if Tweet.ID.IsZero() {
+		Tweet.ID = primitive.NewObjectID()
+	}
+	decision, err := t.moderator.Review(context.Background(), moderation.ContentJob, &Tweet.Title, &Tweet.Description)
+	if err != nil {
+		return primitive.ObjectID{}, err
+	}
+	if err := decision.Err(); err != nil {
+		return primitive.ObjectID{}, err
+	}
+	// Un trabajo retenido no se publica ni avisa a nadie hasta que lo apruebe un administrador
+	if decision.Held() {
+		Tweet.Available = false
+		Tweet.Held = true
+	}
+
+	// Efectos secundarios: métricas y aviso al trabajador solicitado o a los usuarios cercanos
+	events := []outbox.Event{}
+	metricsEvent, err := outbox.NewEvent(jobdomain.EventJobPublishedMetrics, jobdomain.JobMetricsEvent{Gender: sex, BirthDate: birthDate})
+	if err != nil {
+		return primitive.ObjectID{}, err
+	}
+	events = append(events, metricsEvent)
+	notifyEvent, err := t.jobNotifyEvent(Tweet)
+	if err != nil {
+		return primitive.ObjectID{}, err
+	}
+	// El aviso de un trabajo retenido queda en su reporte y se registra si un admin lo aprueba
+	var deferred []outbox.Event
+	if Tweet.Held {
+		deferred = append(deferred, notifyEvent)
+	} else {
+		events = append(events, notifyEvent)
+	}
+
 	GoMongoDBCollUsers := t.mongoClient.Database("NEXO-VECINAL").Collection("Job")
-	result, errInsertOne := GoMongoDBCollUsers.InsertOne(context.Background(), Tweet)
-	if errInsertOne != nil {
-		return primitive.ObjectID{}, errInsertOne
+	err = t.outbox.WithTransaction(context.Background(), func(sessCtx mongo.SessionContext) error {
+		if _, err := GoMongoDBCollUsers.InsertOne(sessCtx, Tweet); err != nil {
+			return err
+		}
+		if Tweet.Held {
+			if err := t.moderator.Hold(sessCtx, moderation.Item{Type: moderation.ContentJob, ID: Tweet.ID, AuthorID: Tweet.UserID, Deferred: deferred}, decision); err != nil {
+				return err
+			}
+		}
+		return t.outbox.Record(sessCtx, events...)
+	})
+	if err != nil {
+		return primitive.ObjectID{}, err
+	}
+	if Tweet.Held {
+		return Tweet.ID, moderation.ErrHeld
 	}
-	insertedID := result.InsertedID.(primitive.ObjectID)
-	err = t.RegisterJobPublicationMetricts(sex, birthDate)
-	return insertedID, err
+	return Tweet.ID, nil
+}
+
+// jobNotifyEvent arma el aviso de un trabajo publicado: al trabajador solicitado o a los usuarios cercanos.
+func (t *JobRepository) jobNotifyEvent(job jobdomain.Job) (outbox.Event, error) {
+	if job.WorkerID != primitive.NilObjectID {
+		return outbox.NewEvent(jobdomain.EventUserNotification, jobdomain.UserNotificationEvent{
+			UserID:  job.WorkerID,
+			Type:    string(notificationdomain.TypeJobRequest),
+			Title:   fmt.Sprintf("Nuevo trabajo asignado: %s", job.Title),
+			Message: "Se te ha asignado un nuevo trabajo.",
+			Data:    map[string]string{"jobId": job.ID.Hex()},
+		})
+	}
+	return outbox.NewEvent(jobdomain.EventJobCreated, jobdomain.JobCreatedEvent{JobID: job.ID})
 }
 
 // ApplyToJob permite que un trabajador se postule a un job agregando su aplicación (con propuesta y precio).
-func (j *JobRepository) ApplyToJob(jobID, applicantID primitive.ObjectID, proposal string, price float64) error {
+// La postulación abre la negociación con la oferta inicial del postulante.
+func (j *JobRepository) ApplyToJob(jobID, applicantID primitive.ObjectID, application jobdomain.JobData) error {
+	proposal := application.Proposal
 	// Validar que la propuesta no exceda los 100 caracteres.
 	if len(proposal) > 100 {
 		return errors.New("la propuesta excede los 100 caracteres")
 	}
+	if err := j.sanctions.Check(context.Background(), applicantID, sanctions.ScopePosting); err != nil {
+		return err
+	}
+	// La propuesta se enmascara o se rechaza; no se retiene porque el empleador la espera
+	decision, err := j.moderator.Review(context.Background(), moderation.ContentApplication, &proposal)
+	if err != nil {
+		return err
+	}
+	if err := decision.Err(); err != nil {
+		return err
+	}
 
 	// Verificar si el usuario cumple con las condiciones para aplicar.
 	canApply, err := j.canUserApply(applicantID)
@@ -73,26 +169,46 @@ func (j *JobRepository) ApplyToJob(jobID, applicantID primitive.ObjectID, propos
 		"_id":                    jobID,
 		"applicants.applicantId": bson.M{"$ne": applicantID},
 	}
-	newApplication := bson.M{
-		"applicantId": applicantID,
-		"proposal":    proposal,
-		"price":       price,
-		"appliedAt":   time.Now(),
+	now := time.Now()
+	offer := jobdomain.NewOffer(applicantID, jobdomain.OfferRequest{
+		Price:             application.Price,
+		EstimatedMinutes:  application.EstimatedMinutes,
+		MaterialsIncluded: application.MaterialsIncluded,
+		Message:           proposal,
+	}, now)
+	newApplication := jobdomain.Application{
+		ApplicantID:       applicantID,
+		Proposal:          proposal,
+		Price:             application.Price,
+		AppliedAt:         now,
+		EstimatedMinutes:  application.EstimatedMinutes,
+		MaterialsIncluded: application.MaterialsIncluded,
+		Offers:            []jobdomain.Offer{offer},
 	}
 	update := bson.M{
 		"$push": bson.M{"applicants": newApplication},
 		"$set":  bson.M{"updatedAt": time.Now()},
 	}
 
-	result, err := jobColl.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return err
-	}
-	if result.MatchedCount == 0 {
-		return errors.New("job not encontrado o ya existe una postulación del usuario")
-	}
-
-	return nil
+	// La postulación y el aviso al empleador se registran juntos
+	return j.outbox.WithTransaction(context.Background(), func(sessCtx mongo.SessionContext) error {
+		var job struct {
+			UserID primitive.ObjectID `bson:"userId"`
+			Title  string             `bson:"title"`
+		}
+		err := jobColl.FindOneAndUpdate(sessCtx, filter, update).Decode(&job)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("job not encontrado o ya existe una postulación del usuario")
+		}
+		if err != nil {
+			return err
+		}
+		event, err := newUserNotificationEvent(job.UserID, notificationdomain.TypeNewApplicant, "Nueva postulación", fmt.Sprintf("Alguien se postuló a \"%s\".", job.Title), jobID)
+		if err != nil {
+			return err
+		}
+		return j.outbox.Record(sessCtx, event)
+	})
 }
 
 func (j *JobRepository) canUserApply(userID primitive.ObjectID) (bool, error) {
@@ -122,7 +238,8 @@ func (j *JobRepository) canUserApply(userID primitive.ObjectID) (bool, error) {
 
 // AssignJob permite que el empleador asigne un job a un trabajador
 // tomando la postulación del usuario (Application) y actualizando el estado a "in_progress".
-func (j *JobRepository) AssignJob(jobID, applicantID primitive.ObjectID) error {
+// Si slot no es nil queda como horario acordado del trabajo.
+func (j *JobRepository) AssignJob(jobID, applicantID primitive.ObjectID, slot *jobdomain.TimeSlot) error {
 	// Primero se obtiene el job para buscar la postulación del applicantID.
 	job, err := j.GetJobByID(jobID)
 	if err != nil {
@@ -141,6 +258,12 @@ func (j *JobRepository) AssignJob(jobID, applicantID primitive.ObjectID) error {
 			break
 		}
 	}
+	if found {
+		// Asignar directamente es aceptar la última oferta del postulante
+		if selectedApp, err = selectedApp.ForAssignment(); err != nil {
+			return err
+		}
+	}
 	// Si no se encontró la postulación, se crea una con valores por defecto.
 	if !found {
 		selectedApp = jobdomain.Application{
@@ -151,38 +274,32 @@ func (j *JobRepository) AssignJob(jobID, applicantID primitive.ObjectID) error {
 		}
 	}
 
-	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
 	filter := bson.M{"_id": jobID}
 	// Usamos $set para actualizar los campos y $pull para eliminar la postulación asignada
 	update := bson.M{
 		"$set": bson.M{
 			"assignedApplication": selectedApp,
+			"finalCost":           selectedApp.Price,
 			"status":              jobdomain.JobStatusInProgress,
 			"updatedAt":           time.Now(),
+			"inProgressSince":     time.Now(),
 		},
+		"$unset": bson.M{"reminderSentAt": "", "closeNoticeAt": ""},
 		"$pull": bson.M{
 			"applicants": bson.M{
 				"applicantId": applicantID,
 			},
 		},
 	}
-	result, err := jobColl.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return err
+	if slot != nil {
+		update["$set"].(bson.M)["scheduledSlot"] = slot
 	}
-	if result.MatchedCount == 0 {
-		return errors.New("job no encontrado")
-	}
-
-	// Enviar notificación push al trabajador asignado
-	if err := j.notifyWorker(selectedApp.ApplicantID, job.Title); err != nil {
-		return fmt.Errorf("error sending push notification: %v", err)
-	}
-	return nil
+	// La asignación y el aviso al trabajador se confirman juntos; el push lo entrega el outbox
+	return j.updateJobAndNotifyAssigned(jobID, filter, update, selectedApp.ApplicantID, job.Title, "job no encontrado")
 }
 
 // ReassignJob permite al empleador reasignar el job a un nuevo trabajador en caso de inconvenientes.
-func (j *JobRepository) ReassignJob(jobID, newWorkerID primitive.ObjectID) error {
+func (j *JobRepository) ReassignJob(jobID, newWorkerID primitive.ObjectID, slot *jobdomain.TimeSlot) error {
 	// Primero se obtiene el job para buscar la postulación del newWorkerID.
 	job, err := j.GetJobByID(jobID)
 	if err != nil {
@@ -201,36 +318,64 @@ func (j *JobRepository) ReassignJob(jobID, newWorkerID primitive.ObjectID) error
 	if !found {
 		return errors.New("no se encontró la postulación del usuario")
 	}
+	// Igual que al asignar: el precio es la última oferta del nuevo trabajador
+	if selectedApp, err = selectedApp.ForAssignment(); err != nil {
+		return err
+	}
 
-	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
 	filter := bson.M{"_id": jobID}
 	// Actualizamos y removemos la postulación asignada
 	update := bson.M{
 		"$set": bson.M{
 			"assignedApplication": selectedApp,
+			"finalCost":           selectedApp.Price,
 			"status":              jobdomain.JobStatusInProgress,
 			"updatedAt":           time.Now(),
+			"inProgressSince":     time.Now(),
 		},
+		"$unset": bson.M{"reminderSentAt": "", "closeNoticeAt": ""},
 		"$pull": bson.M{
 			"applicants": bson.M{
 				"applicantId": newWorkerID,
 			},
 		},
 	}
-	result, err := jobColl.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return err
-	}
-	if result.MatchedCount == 0 {
-		return errors.New("job not found")
+	// El horario ya fue verificado con el calendario del nuevo trabajador; sin horario se descarta
+	// el acordado con el anterior
+	if slot != nil {
+		update["$set"].(bson.M)["scheduledSlot"] = slot
+	} else {
+		update["$unset"].(bson.M)["scheduledSlot"] = ""
 	}
+	return j.updateJobAndNotifyAssigned(jobID, filter, update, selectedApp.ApplicantID, job.Title, "job not found")
+}
 
-	if err := j.notifyWorker(selectedApp.ApplicantID, job.Title); err != nil {
-		return fmt.Errorf("error sending push notification: %v", err)
+// updateJobAndNotifyAssigned aplica la asignación y registra EventJobAssigned en la misma transacción.
+// Las citas activas eran con el trabajador anterior y se cancelan.
+func (j *JobRepository) updateJobAndNotifyAssigned(jobID primitive.ObjectID, filter, update bson.M, workerID primitive.ObjectID, jobTitle, notFoundMsg string) error {
+	event, err := outbox.NewEvent(jobdomain.EventJobAssigned, jobdomain.JobAssignedEvent{WorkerID: workerID, JobTitle: jobTitle})
+	if err != nil {
+		return err
 	}
-	return nil
+	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	return j.outbox.WithTransaction(context.Background(), func(sessCtx mongo.SessionContext) error {
+		result, err := jobColl.UpdateOne(sessCtx, filter, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New(notFoundMsg)
+		}
+		if err := j.cancelJobAppointments(sessCtx, jobID); err != nil {
+			return err
+		}
+		return j.outbox.Record(sessCtx, event)
+	})
 }
 
+// errJobNotCompleted indica que el job ya no cumplía las condiciones para completarse.
+var errJobNotCompleted = errors.New("job not found or already completed")
+
 func (j *JobRepository) UpdateJobStatusToCompleted(jobID, idUser primitive.ObjectID) (*jobdomain.Job, error) {
 	job, err := j.GetJobByID(jobID)
 	if err != nil {
@@ -239,56 +384,85 @@ func (j *JobRepository) UpdateJobStatusToCompleted(jobID, idUser primitive.Objec
 	if job.Status == jobdomain.JobStatusCompleted {
 		return nil, errors.New("job already completed")
 	}
+	return j.completeJob(jobID, idUser, bson.M{"status": bson.M{"$ne": jobdomain.JobStatusCompleted}})
+}
+
+// AutoCompleteJob completa el job solo si todavía cumple cond (el filtro con el que la política lo
+// eligió); devuelve false si mientras tanto cambió de estado.
+func (j *JobRepository) AutoCompleteJob(jobID, idUser primitive.ObjectID, cond bson.M) (bool, error) {
+	_, err := j.completeJob(jobID, idUser, cond)
+	if errors.Is(err, errJobNotCompleted) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// completeJob marca como completado el job de idUser que cumple cond y actualiza contadores y métricas.
+func (j *JobRepository) completeJob(jobID, idUser primitive.ObjectID, cond bson.M) (*jobdomain.Job, error) {
 	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
-	filter := bson.M{
-		"_id":    jobID,
-		"userId": idUser,
-		"status": bson.M{"$ne": jobdomain.JobStatusCompleted},
+	filter := bson.M{"_id": jobID, "userId": idUser}
+	for key, value := range cond {
+		filter[key] = value
 	}
+	// Un trabajo completado ya no tiene citas pendientes
 	update := bson.M{
 		"$set": bson.M{
 			"status":    jobdomain.JobStatusCompleted,
 			"updatedAt": time.Now(),
 		},
+		"$unset": bson.M{"scheduledSlot": ""},
 	}
-	result, err := jobColl.UpdateOne(context.Background(), filter, update)
+	_, sex, birthDate, err := j.GetUserBanAndDemographics(idUser)
 	if err != nil {
 		return nil, err
 	}
-	if result.MatchedCount == 0 {
-		return nil, errors.New("job not found or already completed")
-	}
-	updatedJob, err := j.GetJobByID(jobID)
+	metricsEvent, err := outbox.NewEvent(jobdomain.EventJobCompletedMetrics, jobdomain.JobMetricsEvent{Gender: sex, BirthDate: birthDate})
 	if err != nil {
 		return nil, err
 	}
-	// Incrementar el contador para el empleador
-	if err := j.incrementUserJobCount(updatedJob.UserID); err != nil {
-		return nil, err
-	}
-	// Incrementar el contador para el trabajador asignado (si existe)
-	if updatedJob.AssignedApplication != nil {
-		if err := j.incrementUserJobCount(updatedJob.AssignedApplication.ApplicantID); err != nil {
-			return nil, err
+
+	var updatedJob jobdomain.Job
+	err = j.outbox.WithTransaction(context.Background(), func(sessCtx mongo.SessionContext) error {
+		result, err := jobColl.UpdateOne(sessCtx, filter, update)
+		if err != nil {
+			return err
 		}
-	}
-	_, sex, birthDate, err := j.GetUserBanAndDemographics(idUser)
+		if result.MatchedCount == 0 {
+			return errJobNotCompleted
+		}
+		if err := j.cancelJobAppointments(sessCtx, jobID); err != nil {
+			return err
+		}
+		if err := jobColl.FindOne(sessCtx, bson.M{"_id": jobID}).Decode(&updatedJob); err != nil {
+			return err
+		}
+		// Incrementar el contador para el empleador
+		if err := j.incrementUserJobCount(sessCtx, updatedJob.UserID); err != nil {
+			return err
+		}
+		// Incrementar el contador para el trabajador asignado (si existe)
+		if updatedJob.AssignedApplication != nil {
+			if err := j.incrementUserJobCount(sessCtx, updatedJob.AssignedApplication.ApplicantID); err != nil {
+				return err
+			}
+		}
+		return j.outbox.Record(sessCtx, metricsEvent)
+	})
 	if err != nil {
-		return updatedJob, err
+		return nil, err
 	}
-	err = j.RegisterJobCompletionMetrics(sex, birthDate)
-	return updatedJob, err
+	return &updatedJob, nil
 }
 
 // incrementUserJobCount incrementa en 1 el contador de trabajos completados de un usuario.
-func (j *JobRepository) incrementUserJobCount(userID primitive.ObjectID) error {
+func (j *JobRepository) incrementUserJobCount(ctx context.Context, userID primitive.ObjectID) error {
 	userColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Users")
 	filter := bson.M{"_id": userID}
 	update := bson.M{
 		"$inc": bson.M{"completedJobs": 1}, // Suma 1 al contador de trabajos completados
 	}
 
-	result, err := userColl.UpdateOne(context.Background(), filter, update)
+	result, err := userColl.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
@@ -298,6 +472,9 @@ func (j *JobRepository) incrementUserJobCount(userID primitive.ObjectID) error {
 	return nil
 }
 
+// ErrFeedbackNotAllowed indica que el job no existe, no está completado o no pertenece a quien califica.
+var ErrFeedbackNotAllowed = errors.New("job not found or conditions not met")
+
 // ProvideEmployerFeedback permite que el empleador deje feedback sobre el trabajador.
 // Se agrega el parámetro employerID y se verifica que el documento tenga paymentStatus "completed".
 func (j *JobRepository) ProvideEmployerFeedback(jobID, employerID primitive.ObjectID, feedback jobdomain.Feedback) error {
@@ -318,14 +495,33 @@ func (j *JobRepository) ProvideEmployerFeedback(jobID, employerID primitive.Obje
 
 	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 	var job struct {
+		Title               string                 `bson:"title"`
 		AssignedApplication *jobdomain.Application `bson:"assignedApplication"`
 		Categories          []string               `bson:"tags"`
 	}
 
-	// Esta única operación actualiza el documento y lo retorna
-	err := jobColl.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&job)
+	// El feedback y el aviso al trabajador se registran juntos
+	err := j.outbox.WithTransaction(context.Background(), func(sessCtx mongo.SessionContext) error {
+		if err := jobColl.FindOneAndUpdate(sessCtx, filter, update, opts).Decode(&job); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return ErrFeedbackNotAllowed
+			}
+			return err
+		}
+		if job.AssignedApplication == nil {
+			return nil
+		}
+		event, err := newUserNotificationEvent(job.AssignedApplication.ApplicantID, notificationdomain.TypeFeedbackReceived, "Recibiste una calificación", fmt.Sprintf("El empleador calificó tu trabajo en \"%s\".", job.Title), jobID)
+		if err != nil {
+			return err
+		}
+		return j.outbox.Record(sessCtx, event)
+	})
 	if err != nil {
-		return errors.New("job not found or conditions not met")
+		return err
+	}
+	if job.AssignedApplication == nil {
+		return nil
 	}
 
 	// Actualizar los usuarios recomendados usando la información obtenida
@@ -353,14 +549,38 @@ func (j *JobRepository) ProvideWorkerFeedback(jobID, workerID primitive.ObjectID
 			"updatedAt":      time.Now(),
 		},
 	}
+	// El feedback y el aviso al empleador se registran juntos
+	return j.outbox.WithTransaction(context.Background(), func(sessCtx mongo.SessionContext) error {
+		var job struct {
+			UserID primitive.ObjectID `bson:"userId"`
+			Title  string             `bson:"title"`
+		}
+		if err := jobColl.FindOneAndUpdate(sessCtx, filter, update).Decode(&job); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return ErrFeedbackNotAllowed
+			}
+			return err
+		}
+		event, err := newUserNotificationEvent(job.UserID, notificationdomain.TypeFeedbackReceived, "Recibiste una calificación", fmt.Sprintf("El trabajador calificó el trabajo \"%s\".", job.Title), jobID)
+		if err != nil {
+			return err
+		}
+		return j.outbox.Record(sessCtx, event)
+	})
+}
+
+// UpdateJobIf aplica update al job solo si todavía cumple cond; devuelve false si no lo cumplía.
+func (j *JobRepository) UpdateJobIf(jobID primitive.ObjectID, cond, update bson.M) (bool, error) {
+	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	filter := bson.M{"_id": jobID}
+	for key, value := range cond {
+		filter[key] = value
+	}
 	result, err := jobColl.UpdateOne(context.Background(), filter, update)
 	if err != nil {
-		return err
-	}
-	if result.MatchedCount == 0 {
-		return errors.New("job not found or conditions not met")
+		return false, err
 	}
-	return nil
+	return result.MatchedCount > 0, nil
 }
 
 func (j *JobRepository) UpdateJob(jobID primitive.ObjectID, update bson.M) error {
@@ -370,6 +590,58 @@ func (j *JobRepository) UpdateJob(jobID primitive.ObjectID, update bson.M) error
 	_, err := jobColl.UpdateOne(context.Background(), filter, update)
 	return err
 }
+
+// UpdateJobAndNotify actualiza el job y encola una notificación para userID en la misma transacción.
+func (j *JobRepository) UpdateJobAndNotify(jobID primitive.ObjectID, update bson.M, userID primitive.ObjectID, notifType notificationdomain.NotificationType, title, message string) error {
+	event, err := newUserNotificationEvent(userID, notifType, title, message, jobID)
+	if err != nil {
+		return err
+	}
+	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	return j.outbox.WithTransaction(context.Background(), func(sessCtx mongo.SessionContext) error {
+		if _, err := jobColl.UpdateOne(sessCtx, bson.M{"_id": jobID}, update); err != nil {
+			return err
+		}
+		return j.outbox.Record(sessCtx, event)
+	})
+}
+
+// QueueUserNotification encola una notificación sobre un job; el outbox se encarga de los reintentos.
+func (j *JobRepository) QueueUserNotification(userID primitive.ObjectID, notifType notificationdomain.NotificationType, title, message string, jobID primitive.ObjectID) error {
+	event, err := newUserNotificationEvent(userID, notifType, title, message, jobID)
+	if err != nil {
+		return err
+	}
+	return j.outbox.Record(context.Background(), event)
+}
+
+func newUserNotificationEvent(userID primitive.ObjectID, notifType notificationdomain.NotificationType, title, message string, jobID primitive.ObjectID) (outbox.Event, error) {
+	return outbox.NewEvent(jobdomain.EventUserNotification, jobdomain.UserNotificationEvent{
+		UserID:  userID,
+		Type:    string(notifType),
+		Title:   title,
+		Message: message,
+		Data:    map[string]string{"jobId": jobID.Hex()},
+	})
+}
+
+// FindJobsByFilter devuelve los jobs que cumplen el filtro, ordenados por antigüedad.
+func (j *JobRepository) FindJobsByFilter(filter bson.M, limit int64) ([]jobdomain.Job, error) {
+	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	opts := options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(limit)
+
+	cursor, err := jobColl.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var jobs []jobdomain.Job
+	if err := cursor.All(context.Background(), &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
 func (j *JobRepository) GetJobByID(jobID primitive.ObjectID) (*jobdomain.Job, error) {
 	ctx := context.Background()
 	var job jobdomain.Job
@@ -392,89 +664,19 @@ func (j *JobRepository) UpdateJobPaymentStatus(jobID primitive.ObjectID, status
 	return err
 }
 
-func (j *JobRepository) FindJobsByTagsAndLocation(jobFilter jobdomain.FindJobsByTagsAndLocation, page int) ([]jobdomain.JobDetailsUsers, error) {
-	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
-	// Convertir el radio de metros a radianes (radio terrestre ≈ 6,378,100 metros)
-	radiusInRadians := jobFilter.RadiusInMeters / 6378100.0
-
-	// Se inicia el filtro vacío
-	filter := bson.M{}
-	skip := int64((page - 1) * 10)
-	// Si se proporcionan etiquetas, se filtra que al menos una esté presente
-	if len(jobFilter.Tags) > 0 {
-		filter["tags"] = bson.M{
-			"$in": jobFilter.Tags,
-		}
-	}
-	// Filtro por ubicación: trabajos dentro del radio especificado
-	filter["location"] = bson.M{
-		"$geoWithin": bson.M{
-			"$centerSphere": []interface{}{
-				[]float64{jobFilter.Longitude, jobFilter.Latitude},
-				radiusInRadians,
-			},
-		},
-	}
-
-	// Si se proporcionó un título, se filtra por regex (búsqueda flexible)
-	if jobFilter.Title != "" {
-		filter["title"] = bson.M{
-			"$regex":   jobFilter.Title,
-			"$options": "i",
-		}
-	}
-	filter["status"] = bson.M{
-		"$nin": []jobdomain.JobStatus{jobdomain.JobStatusCompleted},
-	}
-	filter["available"] = true
-	filter["jobType"] = bson.M{"$ne": "solicitud"}
-
-	// Definir el pipeline de agregación
-	pipeline := mongo.Pipeline{
-		bson.D{{Key: "$match", Value: filter}},
-		bson.D{{Key: "$skip", Value: skip}},
-		bson.D{{Key: "$limit", Value: 10}},
-		bson.D{{Key: "$sort", Value: bson.M{"createdAt": -1}}},
-		// Lookup para obtener detalles del usuario creador
-		bson.D{{Key: "$lookup", Value: bson.M{
-			"from":         "Users",
-			"localField":   "userId",
-			"foreignField": "_id",
-			"as":           "userDetails",
-		}}},
-		// Unwind para extraer el objeto de usuario (si existe)
-		bson.D{{Key: "$unwind", Value: bson.M{
-			"path":                       "$userDetails",
-			"preserveNullAndEmptyArrays": true,
-		}}},
-	}
-
-	// Ejecutar la consulta agregada
-	cursor, err := jobColl.Aggregate(context.Background(), pipeline)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(context.Background())
-
-	var jobs []jobdomain.JobDetailsUsers
-	if err = cursor.All(context.Background(), &jobs); err != nil {
-		return nil, err
-	}
-	if len(jobs) == 0 {
-		return j.FindOldestJobs(10) // Puedes cambiar el límite de trabajos a traer
-	}
-
-	return jobs, nil
-}
 func (j *JobRepository) FindOldestJobs(limit int) ([]jobdomain.JobDetailsUsers, error) {
 	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
 
 	// Filtro para obtener solo los trabajos que no están completados
 	filter := bson.M{
 		"status": bson.M{
-			"$nin": []jobdomain.JobStatus{jobdomain.JobStatusCompleted},
+			"$nin": []jobdomain.JobStatus{jobdomain.JobStatusCompleted, jobdomain.JobStatusExpired},
 		},
 		"jobType": bson.M{"$ne": "solicitud"},
+		"$or": []bson.M{
+			{"expiresAt": bson.M{"$gt": time.Now()}},
+			{"expiresAt": bson.M{"$exists": false}},
+		},
 	}
 	filter["available"] = true
 	// Pipeline para traer los trabajos más antiguos
@@ -568,6 +770,9 @@ func (j *JobRepository) GetJobDetails(jobID, idUser primitive.ObjectID) (*jobdom
 							{Key: "proposal", Value: "$$app.proposal"},
 							{Key: "price", Value: "$$app.price"},
 							{Key: "appliedAt", Value: "$$app.appliedAt"},
+							{Key: "estimatedMinutes", Value: "$$app.estimatedMinutes"},
+							{Key: "materialsIncluded", Value: "$$app.materialsIncluded"},
+							{Key: "offers", Value: "$$app.offers"},
 							{Key: "userData", Value: bson.D{
 								{Key: "$arrayElemAt", Value: bson.A{
 									bson.D{
@@ -1228,48 +1433,12 @@ func (j *JobRepository) GetJobsAssignedNoCompleted(employerID primitive.ObjectID
 	return jobs, nil
 }
 func (j *JobRepository) notifyWorker(workerID primitive.ObjectID, jobTitle string) error {
-	// Supongamos que tienes una función que obtiene el push token del usuario
-	pushToken, err := j.getPushTokenUser(workerID)
-	if err != nil {
-		return err
-	}
-	// Construir payload para notificación push de Expo
-	payload := map[string]interface{}{
-		"to":        pushToken,
-		"title":     "Trabajo asignado",
-		"body":      fmt.Sprintf("Has sido asignado al trabajo '%s'", jobTitle),
-		"data":      map[string]string{"jobTitle": jobTitle},
-		"channelId": "jobs",
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-	// Enviar la notificación push
-	resp, err := http.Post("https://exp.host/--/api/v2/push/send", "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error enviando notificación, status: %d", resp.StatusCode)
-	}
-	return nil
-}
-func (j *JobRepository) getPushTokenUser(workerID primitive.ObjectID) (string, error) {
-	userColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Users")
-	var user struct {
-		PushToken string `bson:"pushToken"`
-	}
-	filter := bson.M{"_id": workerID}
-	err := userColl.FindOne(context.Background(), filter).Decode(&user)
-	if err != nil {
-		return "", err
-	}
-	if user.PushToken == "" {
-		return "", errors.New("el trabajador no tiene push token")
-	}
-	return user.PushToken, nil
+	return j.push.SendToUsers(context.Background(), []primitive.ObjectID{workerID}, push.Notification{
+		Title:     "Trabajo asignado",
+		Body:      fmt.Sprintf("Has sido asignado al trabajo '%s'", jobTitle),
+		Data:      map[string]string{"jobTitle": jobTitle},
+		ChannelID: "jobs",
+	})
 }
 func (j *JobRepository) GetJobDetailChat(jobID primitive.ObjectID) (*jobdomain.JobDetailsUsers, error) {
 	ctx := context.Background()
@@ -1499,10 +1668,16 @@ func (u *JobRepository) RegisterJobCompletionMetrics(Gender string, birthDate ti
 	return metricsService.RegisterJobCompletion(context.Background(), Gender, birthDate)
 }
 
-// FindUsersByTagsAndLocation busca usuarios por tags y ubicación pero devolve solo el array de pushtoken
+// JobNotifyPageSize es la cantidad de usuarios cercanos que se leen por página al avisar un nuevo trabajo.
+const JobNotifyPageSize = 500
+
+// FindUsersByTagsAndLocationPushToken busca los usuarios con alguno de los tags cuyo radio de trabajo
+// incluye la ubicación, tengan o no push token, ordenados por distancia. Devuelve la página indicada
+// (desde 1) de JobNotifyPageSize usuarios con sus tokens y preferencias.
 func (j *JobRepository) FindUsersByTagsAndLocationPushToken(
 	tags []string,
 	location jobdomain.GeoPoint, // espera Type="Point", Coordinates []float64{lng, lat}
+	page int,
 ) ([]jobdomain.UserPushTokenId, error) {
 	userColl := j.mongoClient.
 		Database("NEXO-VECINAL").
@@ -1522,22 +1697,25 @@ func (j *JobRepository) FindUsersByTagsAndLocationPushToken(
 			"distanceField": "dist.calculated",
 			"spherical":     true,
 		}}},
-		// 2) Filtrar por tags, pushToken existente y
-		//    solo aquellos donde dist.calculated <= ratio (su propio campo)
+		// 2) Filtrar por tags y solo aquellos donde dist.calculated <= ratio (su propio campo);
+		//    los usuarios sin push token igual reciben la notificación en el centro de notificaciones
 		{{Key: "$match", Value: bson.M{
-			"tags":      bson.M{"$in": tags},
-			"pushToken": bson.M{"$exists": true, "$ne": ""},
+			"tags": bson.M{"$in": tags},
 			"$expr": bson.M{
 				"$lte": []interface{}{"$dist.calculated", "$ratio"},
 			},
 		}}},
 		// 3) Proyección mínima
 		{{Key: "$project", Value: bson.M{
-			"_id":       1,
-			"pushToken": 1,
+			"_id":        1,
+			"pushToken":  1,
+			"pushTokens": 1,
+
+			"notificationPreferences": 1,
 		}}},
-		// 4) Límite por si acaso
-		{{Key: "$limit", Value: 100}},
+		// 4) Página
+		{{Key: "$skip", Value: (page - 1) * JobNotifyPageSize}},
+		{{Key: "$limit", Value: JobNotifyPageSize}},
 	}
 
 	cur, err := userColl.Aggregate(context.Background(), pipeline)
@@ -1555,72 +1733,13 @@ func (j *JobRepository) FindUsersByTagsAndLocationPushToken(
 
 // SendBatchNotification
 func (j *JobRepository) SendBatchNotification(pushTokens []string, title string, body string) error {
-	// Construir un array de payloads para cada token
-	var payloads []map[string]interface{}
-	for _, token := range pushTokens {
-		payloads = append(payloads, map[string]interface{}{
-			"to":        token,
-			"title":     title,
-			"body":      body,
-			"channelId": "jobs",
-		})
-	}
-
-	// Serializar el array de payloads a JSON
-	payloadBytes, err := json.Marshal(payloads)
-	if err != nil {
-		return fmt.Errorf("error serializando payloads: %v", err)
-	}
-
-	// Enviar la solicitud al endpoint de batch de Expo
-	resp, err := http.Post("https://exp.host/--/api/v2/push/send", "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return fmt.Errorf("error enviando notificaciones: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error enviando notificaciones, status: %d", resp.StatusCode)
-	}
-	return nil
+	return j.push.SendToTokens(context.Background(), pushTokens, push.Notification{
+		Title:     title,
+		Body:      body,
+		ChannelID: "jobs",
+	})
 }
 
-// AddJobToUserRecommendations
-func (j *JobRepository) AddJobToUsersRecommendations(userIDs []primitive.ObjectID, jobID primitive.ObjectID) error {
-	userColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Users")
-
-	// Paso 1: Agregar el trabajo al array usando $addToSet
-	filter := bson.M{
-		"_id": bson.M{"$in": userIDs},
-	}
-	addToSetUpdate := bson.M{
-		"$addToSet": bson.M{
-			"recommendedJobs": jobID,
-		},
-	}
-
-	_, err := userColl.UpdateMany(context.Background(), filter, addToSetUpdate)
-	if err != nil {
-		return fmt.Errorf("error agregando trabajo a recomendaciones: %v", err)
-	}
-
-	// Paso 2: Limitar el tamaño del array a 100 elementos usando $push y $slice
-	pushUpdate := bson.M{
-		"$push": bson.M{
-			"recommendedJobs": bson.M{
-				"$each":  []primitive.ObjectID{},
-				"$slice": -100,
-			},
-		},
-	}
-
-	_, err = userColl.UpdateMany(context.Background(), filter, pushUpdate)
-	if err != nil {
-		return fmt.Errorf("error limitando tamaño de recomendaciones: %v", err)
-	}
-
-	return nil
-}
 func (j *JobRepository) getBaseJobPipeline() mongo.Pipeline {
 	return mongo.Pipeline{
 		// Lookup para unir la información del usuario creador
@@ -1652,6 +1771,8 @@ func (j *JobRepository) getBaseJobPipeline() mongo.Pipeline {
 				{Key: "status", Value: 1},
 				{Key: "createdAt", Value: 1},
 				{Key: "updatedAt", Value: 1},
+				{Key: "distanceMeters", Value: 1},
+				{Key: "scheduledSlot", Value: 1},
 				{Key: "userDetails._id", Value: 1},
 				{Key: "userDetails.NameUser", Value: 1},
 				{Key: "userDetails.Avatar", Value: 1},
@@ -1659,80 +1780,14 @@ func (j *JobRepository) getBaseJobPipeline() mongo.Pipeline {
 		}},
 	}
 }
-func (j *JobRepository) GetRecommendedJobsForUser(userID primitive.ObjectID, page int) ([]jobdomain.JobDetailsUsers, error) {
-	userColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Users")
-	limit := 10
-	skip := (page - 1) * limit
-
-	// Pipeline específico para trabajos recomendados
-	pipeline := mongo.Pipeline{
-		// Paso 1: Match por usuario
-		{{
-			Key: "$match", Value: bson.D{
-				{Key: "_id", Value: userID},
-			},
-		}},
-		// Paso 2: Lookup a los trabajos recomendados
-		{{
-			Key: "$lookup", Value: bson.D{
-				{Key: "from", Value: "Job"},
-				{Key: "localField", Value: "recommendedJobs"},
-				{Key: "foreignField", Value: "_id"},
-				{Key: "as", Value: "recommendedJobsArr"},
-			},
-		}},
-		// Paso 3: Unwind para trabajar uno por uno
-		{{
-			Key: "$unwind", Value: bson.M{
-				"path": "$recommendedJobsArr",
-			},
-		}},
-		// 🚀 Paso 4: Reemplazar el root por cada trabajo
-		{{
-			Key: "$replaceRoot", Value: bson.M{
-				"newRoot": "$recommendedJobsArr",
-			},
-		}},
-	}
-
-	// Agregar el pipeline base
-	pipeline = append(pipeline, j.getBaseJobPipeline()...)
 
-	// Agregar paginación
-	pipeline = append(pipeline,
-		bson.D{{Key: "$skip", Value: skip}},
-		bson.D{{Key: "$limit", Value: limit}},
-	)
-
-	cursor, err := userColl.Aggregate(context.Background(), pipeline)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(context.Background())
-
-	var jobs []jobdomain.JobDetailsUsers
-	if err := cursor.All(context.Background(), &jobs); err != nil {
-		return nil, err
-	}
-
-	return jobs, nil
-}
 func (repo *JobRepository) SendNotificationToWorker(workerID primitive.ObjectID, title, message string) error {
-	// 1. Buscar el push token del trabajador
-	worker, err := repo.GetUserByID(workerID)
-	if err != nil {
-		return err
-	}
-	if worker.PushToken == "" {
-		return fmt.Errorf("el trabajador no tiene push token registrado")
-	}
-
-	// 2. Enviar la notificación (aquí puedes usar tu servicio de notificaciones)
-	err = repo.SendPushNotification(worker.PushToken, title, message)
-	if err != nil {
-		return err
-	}
-	return nil
+	// Se envía a todos los dispositivos registrados del usuario
+	return repo.push.SendToUsers(context.Background(), []primitive.ObjectID{workerID}, push.Notification{
+		Title:     title,
+		Body:      message,
+		ChannelID: "jobs",
+	})
 }
 
 // getUserByID obtiene un usuario por su ID y solo devuelve el PushToken
@@ -1761,31 +1816,11 @@ func (repo *JobRepository) GetUserByID(userID primitive.ObjectID) (*userdomain.U
 
 // SendPushNotification envía una notificación push al token especificado
 func (repo *JobRepository) SendPushNotification(pushToken, title, message string) error {
-	// Construir el payload de la notificación
-	payload := map[string]interface{}{
-		"to":        pushToken,
-		"title":     title,
-		"body":      message,
-		"channelId": "jobs",
-	}
-
-	// Serializar el payload a JSON
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("error serializando el payload: %v", err)
-	}
-
-	// Enviar la solicitud al endpoint de Expo
-	resp, err := http.Post("https://exp.host/--/api/v2/push/send", "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return fmt.Errorf("error enviando la notificación: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error enviando la notificación, status: %d", resp.StatusCode)
-	}
-	return nil
+	return repo.push.SendToTokens(context.Background(), []string{pushToken}, push.Notification{
+		Title:     title,
+		Body:      message,
+		ChannelID: "jobs",
+	})
 }
 func (repo *JobRepository) GetJobRequestsReceived(userID primitive.ObjectID, page int) ([]jobdomain.Job, error) {
 	jobColl := repo.mongoClient.Database("NEXO-VECINAL").Collection("Job")
@@ -0,0 +1,39 @@
+package jobinfrastructure
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"back-end/pkg/availability"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IsWorkerAvailable indica si el calendario publicado por el trabajador cubre el horario.
+// Los trabajadores sin calendario se consideran disponibles.
+func (r *JobRepository) IsWorkerAvailable(ctx context.Context, workerID primitive.ObjectID, slot jobdomain.TimeSlot) (bool, error) {
+	userColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	filter := availability.Filter("availability", slot.Start, slot.End)
+	filter["_id"] = workerID
+	count, err := userColl.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// HasScheduleConflict indica si el trabajador tiene otro trabajo en curso cuyo horario se superpone.
+func (r *JobRepository) HasScheduleConflict(ctx context.Context, workerID, jobID primitive.ObjectID, slot jobdomain.TimeSlot) (bool, error) {
+	jobColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	count, err := jobColl.CountDocuments(ctx, bson.M{
+		"_id":                             bson.M{"$ne": jobID},
+		"status":                          jobdomain.JobStatusInProgress,
+		"assignedApplication.applicantId": workerID,
+		"scheduledSlot.start":             bson.M{"$lt": slot.End},
+		"scheduledSlot.end":               bson.M{"$gt": slot.Start},
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,222 @@
+package jobinfrastructure
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"context"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const jobSearchPageSize = 10
+
+// earthRadiusMeters es el radio medio de la Tierra usado en el cálculo de distancias.
+const earthRadiusMeters = 6371008.8
+
+// EnsureSearchIndexes crea el índice de texto y el índice geoespacial usados por la búsqueda de trabajos.
+func (j *JobRepository) EnsureSearchIndexes(ctx context.Context) error {
+	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	_, err := jobColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "description", Value: "text"},
+				{Key: "tags", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("job_text_search").
+				SetWeights(bson.M{"title": 10, "tags": 5, "description": 1}).
+				SetDefaultLanguage("spanish"),
+		},
+		{Keys: bson.D{{Key: "location", Value: "2dsphere"}}},
+	})
+	return err
+}
+
+// FindJobsByTagsAndLocation busca trabajos visibles dentro del radio, con filtros, orden y total de resultados.
+// Si no hay coincidencias devuelve una página vacía con Total 0.
+func (j *JobRepository) FindJobsByTagsAndLocation(jobFilter jobdomain.FindJobsByTagsAndLocation, page int) (*jobdomain.JobSearchResult, error) {
+	jobColl := j.mongoClient.Database("NEXO-VECINAL").Collection("Job")
+	filter := jobSearchFilter(jobFilter)
+
+	var pipeline mongo.Pipeline
+	if jobFilter.Title == "" {
+		// $geoNear calcula la distancia y filtra por radio en la misma etapa
+		pipeline = mongo.Pipeline{
+			{{Key: "$geoNear", Value: bson.M{
+				"near":          bson.M{"type": "Point", "coordinates": []float64{jobFilter.Longitude, jobFilter.Latitude}},
+				"distanceField": "distanceMeters",
+				"maxDistance":   jobFilter.RadiusInMeters,
+				"query":         filter,
+				"spherical":     true,
+			}}},
+		}
+	} else {
+		// $text debe ir en el primer $match y no se puede combinar con $geoNear:
+		// se filtra con $geoWithin y la distancia se calcula aparte
+		filter["location"] = bson.M{
+			"$geoWithin": bson.M{
+				"$centerSphere": []interface{}{
+					[]float64{jobFilter.Longitude, jobFilter.Latitude},
+					jobFilter.RadiusInMeters / 6378100.0,
+				},
+			},
+		}
+		filter["$text"] = bson.M{"$search": jobFilter.Title}
+		pipeline = mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$addFields", Value: bson.M{
+				"score":          bson.M{"$meta": "textScore"},
+				"distanceMeters": distanceMetersExpr(jobFilter.Longitude, jobFilter.Latitude),
+			}}},
+		}
+	}
+	sort := jobSearchSort(jobFilter.SortBy)
+
+	// El orden se aplica antes de paginar; el total sale de la misma consulta
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"jobs": bson.A{
+			bson.M{"$sort": sort},
+			bson.M{"$skip": int64((page - 1) * jobSearchPageSize)},
+			bson.M{"$limit": jobSearchPageSize},
+			bson.M{"$lookup": bson.M{
+				"from":         "Users",
+				"localField":   "userId",
+				"foreignField": "_id",
+				"as":           "userDetails",
+			}},
+			bson.M{"$unwind": bson.M{
+				"path":                       "$userDetails",
+				"preserveNullAndEmptyArrays": true,
+			}},
+		},
+		"total": bson.A{bson.M{"$count": "count"}},
+	}}})
+
+	cursor, err := jobColl.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var facets []struct {
+		Jobs  []jobdomain.JobDetailsUsers `bson:"jobs"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err = cursor.All(context.Background(), &facets); err != nil {
+		return nil, err
+	}
+
+	result := &jobdomain.JobSearchResult{
+		Jobs:     []jobdomain.JobDetailsUsers{},
+		Page:     page,
+		PageSize: jobSearchPageSize,
+	}
+	if len(facets) > 0 {
+		if facets[0].Jobs != nil {
+			result.Jobs = facets[0].Jobs
+		}
+		if len(facets[0].Total) > 0 {
+			result.Total = facets[0].Total[0].Count
+		}
+	}
+	fuzzDistances(result.Jobs)
+	return result, nil
+}
+
+// distanceMetersExpr calcula con la fórmula de haversine la distancia en metros
+// desde el punto dado hasta location, para las consultas que no pueden usar $geoNear.
+func distanceMetersExpr(lng, lat float64) bson.M {
+	jobLng := bson.M{"$degreesToRadians": bson.M{"$arrayElemAt": bson.A{"$location.coordinates", 0}}}
+	jobLat := bson.M{"$degreesToRadians": bson.M{"$arrayElemAt": bson.A{"$location.coordinates", 1}}}
+	originLng := lng * math.Pi / 180
+	originLat := lat * math.Pi / 180
+
+	sinHalf := func(a, b interface{}) bson.M {
+		return bson.M{"$pow": bson.A{
+			bson.M{"$sin": bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{a, b}}, 2}}},
+			2,
+		}}
+	}
+	haversine := bson.M{"$add": bson.A{
+		sinHalf(jobLat, originLat),
+		bson.M{"$multiply": bson.A{math.Cos(originLat), bson.M{"$cos": jobLat}, sinHalf(jobLng, originLng)}},
+	}}
+	return bson.M{"$multiply": bson.A{2 * earthRadiusMeters, bson.M{"$asin": bson.M{"$sqrt": haversine}}}}
+}
+
+// fuzzDistances redondea las distancias antes de exponerlas para no revelar ubicaciones exactas.
+func fuzzDistances(jobs []jobdomain.JobDetailsUsers) {
+	for i := range jobs {
+		if jobs[i].DistanceMeters != nil {
+			fuzzed := jobdomain.FuzzDistance(*jobs[i].DistanceMeters)
+			jobs[i].DistanceMeters = &fuzzed
+		}
+	}
+}
+
+// jobSearchFilter arma los filtros comunes de la búsqueda (sin ubicación ni texto).
+func jobSearchFilter(jobFilter jobdomain.FindJobsByTagsAndLocation) bson.M {
+	filter := bson.M{
+		"available": true,
+		"jobType":   bson.M{"$ne": "solicitud"},
+		// Los jobs vencidos no se muestran aunque el proceso de políticas aún no los haya cerrado
+		"$or": []bson.M{
+			{"expiresAt": bson.M{"$gt": time.Now()}},
+			{"expiresAt": bson.M{"$exists": false}},
+		},
+	}
+	if len(jobFilter.Tags) > 0 {
+		filter["tags"] = bson.M{"$in": jobFilter.Tags}
+	}
+	if jobFilter.Status != "" {
+		filter["status"] = jobFilter.Status
+	} else {
+		filter["status"] = bson.M{
+			"$nin": []jobdomain.JobStatus{jobdomain.JobStatusCompleted, jobdomain.JobStatusExpired},
+		}
+	}
+
+	budget := bson.M{}
+	if jobFilter.BudgetMin > 0 {
+		budget["$gte"] = jobFilter.BudgetMin
+	}
+	if jobFilter.BudgetMax > 0 {
+		budget["$lte"] = jobFilter.BudgetMax
+	}
+	if len(budget) > 0 {
+		filter["budget"] = budget
+	}
+
+	created := bson.M{}
+	if jobFilter.CreatedFrom != nil {
+		created["$gte"] = *jobFilter.CreatedFrom
+	}
+	if jobFilter.CreatedTo != nil {
+		created["$lte"] = *jobFilter.CreatedTo
+	}
+	if len(created) > 0 {
+		filter["createdAt"] = created
+	}
+	return filter
+}
+
+// jobSearchSort devuelve el orden de la búsqueda; _id desempata para que la paginación sea estable.
+func jobSearchSort(sortBy string) bson.D {
+	switch sortBy {
+	case jobdomain.JobSortDistance:
+		return bson.D{{Key: "distanceMeters", Value: 1}, {Key: "_id", Value: -1}}
+	case jobdomain.JobSortRelevance:
+		return bson.D{{Key: "score", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+	case jobdomain.JobSortBudgetAsc:
+		return bson.D{{Key: "budget", Value: 1}, {Key: "_id", Value: -1}}
+	case jobdomain.JobSortBudgetDesc:
+		return bson.D{{Key: "budget", Value: -1}, {Key: "_id", Value: -1}}
+	}
+	return bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+}
@@ -3,8 +3,12 @@ package Jobinterfaces
 import (
 	Jobapplication "back-end/internal/Job/Job-application"
 	jobdomain "back-end/internal/Job/Job-domain"
+	jobinfrastructure "back-end/internal/Job/Job-infrastructure"
 	"back-end/pkg/helpers"
+	"back-end/pkg/moderation"
+	"back-end/pkg/sanctions"
 	"encoding/json"
+	"errors"
 	"strconv"
 	"time"
 
@@ -62,6 +66,17 @@ func (j *JobHandler) CreateJob(c *fiber.Ctx) error {
 			"message": "Bad Request",
 		})
 	}
+	// Horario propuesto en solicitudes directas (opcional), con el mismo formato JSON que la ubicación
+	if slotStr := c.FormValue("slotStr"); slotStr != "" {
+		var slot jobdomain.TimeSlot
+		if err := json.Unmarshal([]byte(slotStr), &slot); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid slot format",
+				"error":   err.Error(),
+			})
+		}
+		createReq.Slot = &slot
+	}
 
 	// Se obtiene el ID del usuario desde el token
 	idValue := c.Context().UserValue("_id").(string)
@@ -90,6 +105,9 @@ func (j *JobHandler) CreateJob(c *fiber.Ctx) error {
 		}
 	}
 	jobID, err := j.JobService.CreateJob(createReq, userID)
+	if handled, resErr := moderationResponse(c, err, jobID); handled {
+		return resErr
+	}
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Could not create job",
@@ -124,7 +142,11 @@ func (j *JobHandler) ApplyToJob(c *fiber.Ctx) error {
 			"message": "Invalid applicant ID",
 		})
 	}
-	if err = j.JobService.ApplyToJob(job.JobId, applicantID, job.Proposal, job.Price); err != nil {
+	err = j.JobService.ApplyToJob(applicantID, job)
+	if handled, resErr := moderationResponse(c, err, primitive.NilObjectID); handled {
+		return resErr
+	}
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Could not apply to job",
 			"error":   err.Error(),
@@ -146,7 +168,8 @@ func (j *JobHandler) AssignJob(c *fiber.Ctx) error {
 		})
 	}
 	var body struct {
-		WorkerID string `json:"workerId"`
+		WorkerID string              `json:"workerId"`
+		Slot     *jobdomain.TimeSlot `json:"slot,omitempty"` // Horario propuesto (opcional)
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -159,7 +182,7 @@ func (j *JobHandler) AssignJob(c *fiber.Ctx) error {
 			"message": "Invalid worker ID",
 		})
 	}
-	if err = j.JobService.AssignJob(jobID, workerID); err != nil {
+	if err = j.JobService.AssignJob(jobID, workerID, body.Slot); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Could not assign job",
 			"error":   err.Error(),
@@ -171,7 +194,7 @@ func (j *JobHandler) AssignJob(c *fiber.Ctx) error {
 }
 
 // ReassignJob permite al empleador reasignar un job a un nuevo trabajador.
-// Se espera que la ruta tenga un parámetro "jobId" y en el body se envíe "newWorkerId".
+// Se espera que la ruta tenga un parámetro "jobId" y en el body se envíe "newWorkerId" y opcionalmente "slot".
 func (j *JobHandler) ReassignJob(c *fiber.Ctx) error {
 	jobIDParam := c.Params("jobId")
 	jobID, err := primitive.ObjectIDFromHex(jobIDParam)
@@ -181,7 +204,8 @@ func (j *JobHandler) ReassignJob(c *fiber.Ctx) error {
 		})
 	}
 	var body struct {
-		NewWorkerID string `json:"newWorkerId"`
+		NewWorkerID string              `json:"newWorkerId"`
+		Slot        *jobdomain.TimeSlot `json:"slot,omitempty"` // Nuevo horario (opcional)
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -194,7 +218,7 @@ func (j *JobHandler) ReassignJob(c *fiber.Ctx) error {
 			"message": "Invalid new worker ID",
 		})
 	}
-	if err = j.JobService.ReassignJob(jobID, newWorkerID); err != nil {
+	if err = j.JobService.ReassignJob(jobID, newWorkerID, body.Slot); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Could not reassign job",
 			"error":   err.Error(),
@@ -230,7 +254,7 @@ func (j *JobHandler) ProvideEmployerFeedback(c *fiber.Ctx) error {
 		})
 	}
 	if err = j.JobService.ProvideEmployerFeedback(jobID, userID, feedback); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return c.Status(feedbackStatus(err)).JSON(fiber.Map{
 			"message": "Could not provide employer feedback",
 			"error":   err.Error(),
 		})
@@ -240,6 +264,14 @@ func (j *JobHandler) ProvideEmployerFeedback(c *fiber.Ctx) error {
 	})
 }
 
+// feedbackStatus devuelve 400 cuando el job no admite el feedback y 500 ante cualquier otro error.
+func feedbackStatus(err error) int {
+	if errors.Is(err, jobinfrastructure.ErrFeedbackNotAllowed) {
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
+
 // ProvideWorkerFeedback permite que el trabajador deje feedback sobre el empleador.
 // Se espera que la ruta tenga un parámetro "jobId" y que en el body se envíe el feedback.
 func (j *JobHandler) ProvideWorkerFeedback(c *fiber.Ctx) error {
@@ -265,7 +297,7 @@ func (j *JobHandler) ProvideWorkerFeedback(c *fiber.Ctx) error {
 		})
 	}
 	if err = j.JobService.ProvideWorkerFeedback(jobID, userID, feedback); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return c.Status(feedbackStatus(err)).JSON(fiber.Map{
 			"message": "Could not provide worker feedback",
 			"error":   err.Error(),
 		})
@@ -274,14 +306,42 @@ func (j *JobHandler) ProvideWorkerFeedback(c *fiber.Ctx) error {
 		"message": "Worker feedback provided successfully",
 	})
 }
+
+// GetJobsByFilters devuelve la página de trabajos encontrados como un array, el formato que usa la app.
+// SearchJobs devuelve la misma búsqueda con el total y la paginación.
 func (j *JobHandler) GetJobsByFilters(c *fiber.Ctx) error {
+	result, err := j.searchJobs(c)
+	if result == nil {
+		return err
+	}
+	jobs := result.Jobs
+	if jobs == nil {
+		jobs = []jobdomain.JobDetailsUsers{}
+	}
+	return c.JSON(jobs)
+}
+
+// SearchJobs devuelve una página de la búsqueda de trabajos con el total de resultados.
+func (j *JobHandler) SearchJobs(c *fiber.Ctx) error {
+	result, err := j.searchJobs(c)
+	if result == nil {
+		return err
+	}
+	if result.Total == 0 {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "No se encontraron trabajos", "data": result})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "StatusOK", "data": result})
+}
+
+// searchJobs lee los filtros y la página y ejecuta la búsqueda. Si la solicitud es inválida o la
+// búsqueda falla responde el error y devuelve un resultado nil.
+func (j *JobHandler) searchJobs(c *fiber.Ctx) (*jobdomain.JobSearchResult, error) {
 	var reqFilyer jobdomain.FindJobsByTagsAndLocation
 	if err := c.BodyParser(&reqFilyer); err != nil {
-
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Solicitud inválida"})
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Solicitud inválida"})
 	}
 	if err := reqFilyer.Validate(); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Bad Request",
 			"error":   err.Error(),
 		})
@@ -291,12 +351,13 @@ func (j *JobHandler) GetJobsByFilters(c *fiber.Ctx) error {
 	if err != nil || page < 1 {
 		page = 1
 	}
-	jobs, err := j.JobService.FindJobsByTagsAndLocation(reqFilyer, page)
+	// Si el id no es válido el visitante recibe ubicaciones aproximadas
+	viewerID, _ := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	result, err := j.JobService.FindJobsByTagsAndLocation(reqFilyer, page, viewerID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Error al obtener trabajos", "error": err.Error()})
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Error al obtener trabajos", "error": err.Error()})
 	}
-
-	return c.JSON(jobs)
+	return result, nil
 }
 
 // CreateJob maneja la creación de un nuevo job.
@@ -351,7 +412,8 @@ func (j *JobHandler) GetJobByIDForEmployee(c *fiber.Ctx) error {
 			"message": "Invalid user ID",
 		})
 	}
-	job, err := j.JobService.GetJobByIDForEmployee(jobIDPr)
+	viewerID, _ := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	job, err := j.JobService.GetJobByIDForEmployee(jobIDPr, viewerID)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Error al obtener el job",
@@ -408,7 +470,7 @@ func (j *JobHandler) GetJobsProfile(c *fiber.Ctx) error {
 	if err != nil || page < 1 {
 		page = 1
 	}
-	jobs, err := j.JobService.GetJobsProfile(userID, page)
+	jobs, err := j.JobService.GetJobsProfile(userID, page, userID)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "StatusBadRequest",
@@ -435,7 +497,7 @@ func (j *JobHandler) GetJobsUserIDForEmployeProfile(c *fiber.Ctx) error {
 	if err != nil || page < 1 {
 		page = 1
 	}
-	jobs, err := j.JobService.GetJobsByUserIDForEmploye(userID, page)
+	jobs, err := j.JobService.GetJobsByUserIDForEmploye(userID, page, userID)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "StatusBadRequest",
@@ -473,7 +535,7 @@ func (j *JobHandler) GetJobsUserIDForEmployeProfilevist(c *fiber.Ctx) error {
 		page = 1
 	}
 
-	jobs, err := j.JobService.GetJobsProfile(userID, page)
+	jobs, err := j.JobService.GetJobsProfile(userID, page, primitive.NilObjectID)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "StatusBadRequest",
@@ -508,7 +570,7 @@ func (j *JobHandler) GetJobsProfilevist(c *fiber.Ctx) error {
 		page = 1
 	}
 
-	jobs, err := j.JobService.GetJobsProfile(userID, page)
+	jobs, err := j.JobService.GetJobsProfile(userID, page, primitive.NilObjectID)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "StatusBadRequest",
@@ -670,7 +732,7 @@ func (j *JobHandler) GetJobsAssignedCompleted(c *fiber.Ctx) error {
 		page = 1
 	}
 
-	jobs, err := j.JobService.GetJobsAssignedCompleted(userid, page)
+	jobs, err := j.JobService.GetJobsAssignedCompleted(userid, userid, page)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "Error retrieving jobs",
@@ -700,7 +762,7 @@ func (j *JobHandler) GetJobsUserCompletedVisited(c *fiber.Ctx) error {
 		page = 1
 	}
 
-	jobs, err := j.JobService.GetJobsAssignedCompleted(userid, page)
+	jobs, err := j.JobService.GetJobsAssignedCompleted(userid, primitive.NilObjectID, page)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "Error retrieving jobs",
@@ -749,7 +811,8 @@ func (j *JobHandler) GetRecommendedJobsForUser(c *fiber.Ctx) error {
 	if err != nil || page < 1 {
 		page = 1
 	}
-	jobs, err := j.JobService.GetRecommendedJobsForUser(userID, page)
+	// ?sort=nearest ordena por cercanía
+	jobs, err := j.JobService.GetRecommendedJobsForUser(userID, page, c.Query("sort") == "nearest")
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "StatusBadRequest",
@@ -842,3 +905,28 @@ func (j *JobHandler) RejectJobRequest(c *fiber.Ctx) error {
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Trabajo rechazado correctamente"})
 }
+
+// moderationResponse responde 403 si una sanción del usuario impide publicar, 422 si la moderación
+// rechazó el texto y 202 si el trabajo quedó retenido para revisión. Devuelve false si err no viene
+// de las sanciones ni de la moderación.
+func moderationResponse(c *fiber.Ctx, err error, jobID primitive.ObjectID) (bool, error) {
+	if sanction, ok := sanctions.As(err); ok {
+		return true, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message":  "Tu cuenta tiene una sanción que no te permite publicar",
+			"sanction": sanction,
+		})
+	}
+	if errors.Is(err, moderation.ErrRejected) {
+		return true, c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"message": "El texto no cumple las normas de la comunidad",
+			"error":   err.Error(),
+		})
+	}
+	if errors.Is(err, moderation.ErrHeld) {
+		return true, c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message": "El trabajo quedó pendiente de revisión",
+			"job":     jobID,
+		})
+	}
+	return false, nil
+}
@@ -0,0 +1,146 @@
+package Jobinterfaces
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateJobAlert guarda una búsqueda como alerta (POST /job/alerts).
+func (j *JobHandler) CreateJobAlert(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	var req jobdomain.JobAlertRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Solicitud inválida"})
+	}
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Bad Request",
+			"error":   err.Error(),
+		})
+	}
+	alertID, err := j.JobService.CreateJobAlert(userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Error al crear la alerta",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Alerta creada",
+		"alertId": alertID,
+	})
+}
+
+// GetJobAlerts lista las alertas del usuario (GET /job/alerts).
+func (j *JobHandler) GetJobAlerts(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	alerts, err := j.JobService.GetJobAlerts(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Error al obtener las alertas",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"data":    alerts,
+	})
+}
+
+// UpdateJobAlert edita una alerta (PUT /job/alerts/:alertId).
+func (j *JobHandler) UpdateJobAlert(c *fiber.Ctx) error {
+	userID, alertID, err := alertParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid ID",
+		})
+	}
+	var req jobdomain.JobAlertRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Solicitud inválida"})
+	}
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Bad Request",
+			"error":   err.Error(),
+		})
+	}
+	if err := j.JobService.UpdateJobAlert(userID, alertID, req); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "Error al editar la alerta",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Alerta actualizada",
+	})
+}
+
+// PauseJobAlert pausa una alerta (POST /job/alerts/:alertId/pause).
+func (j *JobHandler) PauseJobAlert(c *fiber.Ctx) error {
+	return j.setJobAlertPaused(c, true)
+}
+
+// ResumeJobAlert reanuda una alerta pausada (POST /job/alerts/:alertId/resume).
+func (j *JobHandler) ResumeJobAlert(c *fiber.Ctx) error {
+	return j.setJobAlertPaused(c, false)
+}
+
+func (j *JobHandler) setJobAlertPaused(c *fiber.Ctx, paused bool) error {
+	userID, alertID, err := alertParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid ID",
+		})
+	}
+	if err := j.JobService.SetJobAlertPaused(userID, alertID, paused); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "Error al actualizar la alerta",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"paused":  paused,
+	})
+}
+
+// DeleteJobAlert elimina una alerta (DELETE /job/alerts/:alertId).
+func (j *JobHandler) DeleteJobAlert(c *fiber.Ctx) error {
+	userID, alertID, err := alertParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid ID",
+		})
+	}
+	if err := j.JobService.DeleteJobAlert(userID, alertID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "Error al eliminar la alerta",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Alerta eliminada",
+	})
+}
+
+func alertParams(c *fiber.Ctx) (primitive.ObjectID, primitive.ObjectID, error) {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, err
+	}
+	alertID, err := primitive.ObjectIDFromHex(c.Params("alertId"))
+	return userID, alertID, err
+}
@@ -0,0 +1,275 @@
+package Jobinterfaces
+
+import (
+	"back-end/config"
+	jobapplication "back-end/internal/Job/Job-application"
+	jobdomain "back-end/internal/Job/Job-domain"
+	jobinfrastructure "back-end/internal/Job/Job-infrastructure"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// appointmentIDs lee el usuario autenticado y el appointmentId de la ruta.
+func appointmentIDs(c *fiber.Ctx) (primitive.ObjectID, primitive.ObjectID, error) {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("Invalid user ID")
+	}
+	appointmentID, err := primitive.ObjectIDFromHex(c.Params("appointmentId"))
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("Invalid appointment ID")
+	}
+	return userID, appointmentID, nil
+}
+
+// appointmentStatus traduce los errores de agenda a códigos HTTP.
+func appointmentStatus(err error) int {
+	switch {
+	case errors.Is(err, jobapplication.ErrWorkerUnavailable),
+		errors.Is(err, jobapplication.ErrScheduleConflict),
+		errors.Is(err, jobinfrastructure.ErrActiveAppointment):
+		return fiber.StatusConflict
+	case strings.HasPrefix(err.Error(), "no autorizado"):
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusBadRequest
+}
+
+func parseAppointmentRequest(c *fiber.Ctx) (jobdomain.AppointmentRequest, error) {
+	var req jobdomain.AppointmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, err
+	}
+	return req, req.Validate(time.Now())
+}
+
+// ProposeAppointment propone una fecha para un trabajo en curso (POST /job/:jobId/appointments).
+func (j *JobHandler) ProposeAppointment(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	jobID, err := primitive.ObjectIDFromHex(c.Params("jobId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid job ID",
+		})
+	}
+	req, err := parseAppointmentRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Bad Request",
+			"error":   err.Error(),
+		})
+	}
+	appointmentID, err := j.JobService.ProposeAppointment(userID, jobID, req)
+	if err != nil {
+		return c.Status(appointmentStatus(err)).JSON(fiber.Map{
+			"message": "Could not propose appointment",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":     "Appointment proposed successfully",
+		"appointment": appointmentID,
+	})
+}
+
+// GetJobAppointments lista las citas de un trabajo (GET /job/:jobId/appointments).
+func (j *JobHandler) GetJobAppointments(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	jobID, err := primitive.ObjectIDFromHex(c.Params("jobId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid job ID",
+		})
+	}
+	appointments, err := j.JobService.GetJobAppointments(userID, jobID)
+	if err != nil {
+		return c.Status(appointmentStatus(err)).JSON(fiber.Map{
+			"message": "Could not get appointments",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"appointments": appointments,
+	})
+}
+
+// GetUpcomingAppointments lista las próximas citas del usuario (GET /job/appointments).
+func (j *JobHandler) GetUpcomingAppointments(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	appointments, err := j.JobService.GetUpcomingAppointments(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Could not get appointments",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"appointments": appointments,
+	})
+}
+
+// AcceptAppointment acepta la fecha propuesta (POST /job/appointments/:appointmentId/accept).
+func (j *JobHandler) AcceptAppointment(c *fiber.Ctx) error {
+	userID, appointmentID, err := appointmentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	if err := j.JobService.AcceptAppointment(userID, appointmentID); err != nil {
+		return c.Status(appointmentStatus(err)).JSON(fiber.Map{
+			"message": "Could not accept appointment",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Appointment accepted successfully",
+	})
+}
+
+// RescheduleAppointment propone una nueva fecha (POST /job/appointments/:appointmentId/reschedule).
+func (j *JobHandler) RescheduleAppointment(c *fiber.Ctx) error {
+	userID, appointmentID, err := appointmentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	req, err := parseAppointmentRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Bad Request",
+			"error":   err.Error(),
+		})
+	}
+	if err := j.JobService.RescheduleAppointment(userID, appointmentID, req); err != nil {
+		return c.Status(appointmentStatus(err)).JSON(fiber.Map{
+			"message": "Could not reschedule appointment",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Appointment rescheduled successfully",
+	})
+}
+
+// CancelAppointment cancela una cita (POST /job/appointments/:appointmentId/cancel).
+func (j *JobHandler) CancelAppointment(c *fiber.Ctx) error {
+	userID, appointmentID, err := appointmentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	if err := j.JobService.CancelAppointment(userID, appointmentID); err != nil {
+		return c.Status(appointmentStatus(err)).JSON(fiber.Map{
+			"message": "Could not cancel appointment",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Appointment cancelled successfully",
+	})
+}
+
+// AppointmentICS descarga una cita como .ics (GET /job/appointments/:appointmentId/ics).
+func (j *JobHandler) AppointmentICS(c *fiber.Ctx) error {
+	userID, appointmentID, err := appointmentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	ics, err := j.JobService.AppointmentICS(userID, appointmentID)
+	if err != nil {
+		return c.Status(appointmentStatus(err)).JSON(fiber.Map{
+			"message": "Could not export appointment",
+			"error":   err.Error(),
+		})
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="cita-`+appointmentID.Hex()+`.ics"`)
+	return c.Send(ics)
+}
+
+// feedURL arma la URL pública del feed iCalendar para el token.
+func feedURL(c *fiber.Ctx, token string) string {
+	base := strings.TrimRight(config.PublicAPIURL(), "/")
+	if base == "" {
+		base = c.BaseURL()
+	}
+	return base + "/calendar/feed/" + token + ".ics"
+}
+
+// GetCalendarFeedURL devuelve la URL del feed iCalendar del usuario (GET /job/calendar/feed-url).
+func (j *JobHandler) GetCalendarFeedURL(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	token, err := j.JobService.CalendarFeedToken(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Could not get calendar feed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"url": feedURL(c, token),
+	})
+}
+
+// RotateCalendarFeedURL invalida la URL anterior y devuelve una nueva (POST /job/calendar/feed-url/rotate).
+func (j *JobHandler) RotateCalendarFeedURL(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	token, err := j.JobService.RotateCalendarFeedToken(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Could not rotate calendar feed",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"url": feedURL(c, token),
+	})
+}
+
+// CalendarFeed sirve el feed iCalendar del dueño del token (GET /calendar/feed/:token).
+// Es público: el token secreto hace de credencial para los clientes de calendario.
+func (j *JobHandler) CalendarFeed(c *fiber.Ctx) error {
+	token := strings.TrimSuffix(c.Params("token"), ".ics")
+	ics, err := j.JobService.CalendarFeed(token)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "Calendar not found",
+		})
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.Send(ics)
+}
@@ -0,0 +1,57 @@
+package Jobinterfaces
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RequestFavoriteWorker envía una solicitud directa a un trabajador favorito
+// (POST /job/request-favorite-worker/:workerId). El cuerpo es un CreateJobRequest en JSON.
+func (j *JobHandler) RequestFavoriteWorker(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	workerID, err := primitive.ObjectIDFromHex(c.Params("workerId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid worker ID",
+		})
+	}
+	var createReq jobdomain.CreateJobRequest
+	if err := c.BodyParser(&createReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Bad Request",
+		})
+	}
+	if err := createReq.ValidateCreateJobRequest(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Bad Request",
+			"error":   err.Error(),
+		})
+	}
+	if len(createReq.Location.Coordinates) != 2 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Location is required",
+		})
+	}
+	createReq.Location.Type = "Point"
+	jobID, err := j.JobService.RequestFavoriteWorker(createReq, userID, workerID)
+	if handled, resErr := moderationResponse(c, err, jobID); handled {
+		return resErr
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Could not send job request",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Job request sent successfully",
+		"job":     jobID,
+	})
+}
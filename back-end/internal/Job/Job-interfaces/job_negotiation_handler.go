@@ -0,0 +1,110 @@
+package Jobinterfaces
+
+import (
+	jobdomain "back-end/internal/Job/Job-domain"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// negotiationIDs lee el usuario autenticado, el jobId y el applicantId de la ruta.
+func negotiationIDs(c *fiber.Ctx) (userID, jobID, applicantID primitive.ObjectID, err error) {
+	if userID, err = primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string)); err != nil {
+		return userID, jobID, applicantID, errors.New("Invalid user ID")
+	}
+	if jobID, err = primitive.ObjectIDFromHex(c.Params("jobId")); err != nil {
+		return userID, jobID, applicantID, errors.New("Invalid job ID")
+	}
+	if applicantID, err = primitive.ObjectIDFromHex(c.Params("applicantId")); err != nil {
+		return userID, jobID, applicantID, errors.New("Invalid applicant ID")
+	}
+	return userID, jobID, applicantID, nil
+}
+
+func negotiationStatus(err error) int {
+	if strings.HasPrefix(err.Error(), "no autorizado") {
+		return fiber.StatusForbidden
+	}
+	return fiber.StatusBadRequest
+}
+
+// GetOffers devuelve la negociación de una postulación (GET /job/:jobId/applications/:applicantId/offers).
+func (j *JobHandler) GetOffers(c *fiber.Ctx) error {
+	userID, jobID, applicantID, err := negotiationIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	offers, err := j.JobService.GetOffers(userID, jobID, applicantID)
+	if err != nil {
+		return c.Status(negotiationStatus(err)).JSON(fiber.Map{
+			"message": "Could not get offers",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"offers": offers,
+	})
+}
+
+// CounterOffer envía una contraoferta (POST /job/:jobId/applications/:applicantId/offers).
+func (j *JobHandler) CounterOffer(c *fiber.Ctx) error {
+	userID, jobID, applicantID, err := negotiationIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	var req jobdomain.OfferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Bad Request",
+		})
+	}
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Bad Request",
+			"error":   err.Error(),
+		})
+	}
+	offer, err := j.JobService.CounterOffer(userID, jobID, applicantID, req)
+	if err != nil {
+		return c.Status(negotiationStatus(err)).JSON(fiber.Map{
+			"message": "Could not send offer",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Offer sent successfully",
+		"offer":   offer,
+	})
+}
+
+// AcceptOffer acepta la última oferta de la otra parte y asigna el trabajo
+// (POST /job/:jobId/applications/:applicantId/offers/accept).
+func (j *JobHandler) AcceptOffer(c *fiber.Ctx) error {
+	userID, jobID, applicantID, err := negotiationIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	var req jobdomain.AcceptOfferRequest
+	if err := c.BodyParser(&req); err != nil || req.OfferID.IsZero() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "offerId is required",
+		})
+	}
+	if err := j.JobService.AcceptOffer(userID, jobID, applicantID, req.OfferID); err != nil {
+		return c.Status(negotiationStatus(err)).JSON(fiber.Map{
+			"message": "Could not accept offer",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Offer accepted, job assigned successfully",
+	})
+}
@@ -5,6 +5,10 @@ import (
 	jobinfrastructure "back-end/internal/Job/Job-infrastructure"
 	Jobinterfaces "back-end/internal/Job/Job-interfaces"
 	"back-end/pkg/middleware"
+	"back-end/pkg/outbox"
+	"context"
+	"fmt"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
@@ -16,6 +20,27 @@ func JobRoutes(App *fiber.App, redisClient *redis.Client, newMongoDB *mongo.Clie
 	JobRepository := jobinfrastructure.NewjobRepository(redisClient, newMongoDB)
 	JobService := jobapplication.NewJobService(JobRepository)
 	JobHandler := Jobinterfaces.NewJobHandler(JobService)
+	// índices de texto y geoespacial de la búsqueda de trabajos
+	if err := JobRepository.EnsureSearchIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de búsqueda de trabajos:", err)
+	}
+	if err := JobRepository.EnsureAlertIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de alertas de trabajos:", err)
+	}
+	if err := JobRepository.SyncAlertCounters(context.Background()); err != nil {
+		fmt.Println("Error sincronizando contadores de alertas:", err)
+	}
+	if err := JobRepository.EnsureAppointmentIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de citas:", err)
+	}
+	// vencimiento, recordatorios y completado automático de trabajos
+	JobService.StartJobPolicyScheduler(time.Hour)
+	// recordatorios de citas aceptadas
+	JobService.StartAppointmentReminders(5 * time.Minute)
+	// notificaciones, recomendaciones y métricas pendientes del outbox
+	dispatcher := outbox.NewDispatcher(outbox.NewStore(newMongoDB))
+	JobService.RegisterOutboxHandlers(dispatcher)
+	dispatcher.Start(10 * time.Second)
 
 	App.Post("/job/create", middleware.UseExtractor(), JobHandler.CreateJob)
 	// Crear un nuevo trabajo
@@ -24,8 +49,20 @@ func JobRoutes(App *fiber.App, redisClient *redis.Client, newMongoDB *mongo.Clie
 	App.Put("/job/:jobId/reassign", middleware.UseExtractor(), JobHandler.ReassignJob)                       // Reasignar un trabajador a un trabajo
 	App.Post("/job/:jobId/worker-feedback", middleware.UseExtractor(), JobHandler.ProvideWorkerFeedback)     // Feedback del empleado
 	App.Post("/job/:jobId/employer-feedback", middleware.UseExtractor(), JobHandler.ProvideEmployerFeedback) // Feedback del empleador
+	// negociación de una postulación: contraofertas y aceptación
+	App.Get("/job/:jobId/applications/:applicantId/offers", middleware.UseExtractor(), JobHandler.GetOffers)
+	App.Post("/job/:jobId/applications/:applicantId/offers", middleware.UseExtractor(), JobHandler.CounterOffer)
+	App.Post("/job/:jobId/applications/:applicantId/offers/accept", middleware.UseExtractor(), JobHandler.AcceptOffer)
 
-	App.Post("/job/get-jobsBy-filters", middleware.UseExtractor(), JobHandler.GetJobsByFilters)                      // GetJobsByFilters
+	App.Post("/job/get-jobsBy-filters", middleware.UseExtractor(), JobHandler.GetJobsByFilters) // GetJobsByFilters
+	App.Post("/job/search", middleware.UseExtractor(), JobHandler.SearchJobs)                   // Búsqueda con total y paginación
+	// búsquedas guardadas / alertas de trabajos
+	App.Post("/job/alerts", middleware.UseExtractor(), JobHandler.CreateJobAlert)
+	App.Get("/job/alerts", middleware.UseExtractor(), JobHandler.GetJobAlerts)
+	App.Put("/job/alerts/:alertId", middleware.UseExtractor(), JobHandler.UpdateJobAlert)
+	App.Post("/job/alerts/:alertId/pause", middleware.UseExtractor(), JobHandler.PauseJobAlert)
+	App.Post("/job/alerts/:alertId/resume", middleware.UseExtractor(), JobHandler.ResumeJobAlert)
+	App.Delete("/job/alerts/:alertId", middleware.UseExtractor(), JobHandler.DeleteJobAlert)
 	App.Post("/job/update-job-statusTo-completed", middleware.UseExtractor(), JobHandler.UpdateJobStatusToCompleted) // CreateJob maneja la creación de un nuevo job.
 
 	App.Post("/job/get-job-token-admin", middleware.UseExtractor(), JobHandler.GetJobTokenAdmin)  // obtiene detalles de un trabajo (admin)
@@ -58,4 +95,18 @@ func JobRoutes(App *fiber.App, redisClient *redis.Client, newMongoDB *mongo.Clie
 	// solicitudes de trabajos
 	App.Post("/job/accept-job-request", middleware.UseExtractor(), JobHandler.AcceptJobRequest)
 	App.Post("/job/reject-job-request", middleware.UseExtractor(), JobHandler.RejectJobRequest)
+	// solicitud directa a un trabajador favorito
+	App.Post("/job/request-favorite-worker/:workerId", middleware.UseExtractor(), JobHandler.RequestFavoriteWorker)
+	// citas de trabajos en curso
+	App.Post("/job/:jobId/appointments", middleware.UseExtractor(), JobHandler.ProposeAppointment)
+	App.Get("/job/:jobId/appointments", middleware.UseExtractor(), JobHandler.GetJobAppointments)
+	App.Get("/job/appointments", middleware.UseExtractor(), JobHandler.GetUpcomingAppointments)
+	App.Post("/job/appointments/:appointmentId/accept", middleware.UseExtractor(), JobHandler.AcceptAppointment)
+	App.Post("/job/appointments/:appointmentId/reschedule", middleware.UseExtractor(), JobHandler.RescheduleAppointment)
+	App.Post("/job/appointments/:appointmentId/cancel", middleware.UseExtractor(), JobHandler.CancelAppointment)
+	App.Get("/job/appointments/:appointmentId/ics", middleware.UseExtractor(), JobHandler.AppointmentICS)
+	// feed iCalendar personal (la URL lleva un token secreto)
+	App.Get("/job/calendar/feed-url", middleware.UseExtractor(), JobHandler.GetCalendarFeedURL)
+	App.Post("/job/calendar/feed-url/rotate", middleware.UseExtractor(), JobHandler.RotateCalendarFeedURL)
+	App.Get("/calendar/feed/:token", JobHandler.CalendarFeed)
 }
@@ -7,16 +7,26 @@ import (
 )
 
 type RecommendedWorkersService struct {
-	Repo *recommendedworkersinfrastructure.RecommendedWorkersRepository
+	Repo    *recommendedworkersinfrastructure.RecommendedWorkersRepository
+	Weights recommendedworkersdomain.WorkerRankingWeights
 }
 
 func NewRecommendedWorkersService(repo *recommendedworkersinfrastructure.RecommendedWorkersRepository) *RecommendedWorkersService {
 	return &RecommendedWorkersService{
-		Repo: repo,
+		Repo:    repo,
+		Weights: recommendedworkersdomain.DefaultWorkerRankingWeights(),
 	}
 }
 
-// GetRecommendedUsers obtiene la lista de usuarios recomendados con filtros y paginación.
-func (rw *RecommendedWorkersService) GetRecommendedWorkers(req recommendedworkersdomain.GetWorkers) ([]jobdomain.User, error) {
-	return rw.Repo.GetRecommendedWorkers(req)
+// GetRecommendedWorkers obtiene los trabajadores recomendados ordenados por puntaje, con el total para paginar.
+func (rw *RecommendedWorkersService) GetRecommendedWorkers(req recommendedworkersdomain.GetWorkers) (*recommendedworkersdomain.WorkerSearchResult, error) {
+	result, err := rw.Repo.GetRecommendedWorkers(req, rw.Weights)
+	if err != nil {
+		return nil, err
+	}
+	// La ubicación del trabajador no se expone: la distancia se muestra redondeada
+	for i := range result.Workers {
+		result.Workers[i].DistanceMeters = jobdomain.FuzzDistance(result.Workers[i].DistanceMeters)
+	}
+	return result, nil
 }
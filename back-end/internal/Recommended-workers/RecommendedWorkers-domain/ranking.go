@@ -0,0 +1,50 @@
+package recommendedworkersdomain
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WorkerRankingWeights pondera las señales del ranking de trabajadores recomendados.
+// El puntaje base es la suma ponderada de señales en [0, 1].
+type WorkerRankingWeights struct {
+	Rating   float64 // Promedio de opiniones (de 1 a 5)
+	Jobs     float64 // Cantidad de trabajos completados, con rendimiento decreciente
+	Distance float64 // Cercanía dentro de MaxDistance
+	// PremiumBoost multiplica el puntaje base de los suscriptores premium (0.2 = +20%),
+	// pero la suma nunca supera PremiumBoostCap para que no tapen a trabajadores mucho mejores.
+	PremiumBoost    float64
+	PremiumBoostCap float64
+}
+
+// DefaultWorkerRankingWeights son los pesos usados por el ranking.
+func DefaultWorkerRankingWeights() WorkerRankingWeights {
+	return WorkerRankingWeights{
+		Rating:          0.5,
+		Jobs:            0.2,
+		Distance:        0.3,
+		PremiumBoost:    0.2,
+		PremiumBoostCap: 0.1,
+	}
+}
+
+// JobsHalfSaturation es la cantidad de trabajos con la que la señal de experiencia vale 0.5.
+const JobsHalfSaturation = 10
+
+// WorkerCard es un trabajador recomendado tal como se muestra en la lista.
+type WorkerCard struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	NameUser       string             `json:"NameUser" bson:"NameUser"`
+	Avatar         string             `json:"Avatar" bson:"Avatar"`
+	Tags           []string           `json:"tags" bson:"tags"`
+	AverageRating  float64            `json:"averageRating" bson:"averageRating"`
+	TotalJobs      int                `json:"totalJobs" bson:"totalJobs"`
+	Premium        bool               `json:"premium" bson:"isPremium"`
+	DistanceMeters float64            `json:"distanceMeters" bson:"distanceMeters"` // Redondeada, ver jobdomain.FuzzDistance
+	Score          float64            `json:"score" bson:"score"`
+}
+
+// WorkerSearchResult es una página del ranking con el total de resultados.
+type WorkerSearchResult struct {
+	Workers []WorkerCard `json:"workers"`
+	Total   int64        `json:"total"`
+}
@@ -19,9 +19,10 @@ type RecommendedWorker struct {
 }
 
 type GetWorkers struct {
-	Categories  []string            `json:"categories" query:"categories"` // No requiere validación estricta
-	Page        int                 `json:"page" query:"page" validate:"required,min=1"`
-	Limit       int                 `json:"limit" query:"limit" validate:"required,min=1"`
-	GeoPoint    userdomain.GeoPoint `json:"geoPoint" validate:"required,dive"`       // dive para validar campos internos
-	MaxDistance int                 `json:"maxDistance" validate:"required,min=100"` // por ejemplo, mínimo 100 metros
+	Categories  []string             `json:"categories" query:"categories"` // No requiere validación estricta
+	Page        int                  `json:"page" query:"page" validate:"required,min=1"`
+	Limit       int                  `json:"limit" query:"limit" validate:"required,min=1"`
+	GeoPoint    userdomain.GeoPoint  `json:"geoPoint" validate:"required,dive"`       // dive para validar campos internos
+	MaxDistance int                  `json:"maxDistance" validate:"required,min=100"` // por ejemplo, mínimo 100 metros
+	AvailableAt *userdomain.TimeSlot `json:"availableAt"`                             // Opcional: solo trabajadores disponibles en ese horario
 }
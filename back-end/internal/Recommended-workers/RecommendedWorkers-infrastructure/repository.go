@@ -1,8 +1,8 @@
 package recommendedworkersinfrastructure
 
 import (
-	jobdomain "back-end/internal/Job/Job-domain"
 	recommendedworkersdomain "back-end/internal/Recommended-workers/RecommendedWorkers-domain"
+	"back-end/pkg/availability"
 	"context"
 	"fmt"
 	"time"
@@ -23,19 +23,24 @@ func NewRecommendedWorkersRepository(redisClient *redis.Client, mongoClient *mon
 		mongoClient: mongoClient,
 	}
 }
-func (r *RecommendedWorkersRepository) GetRecommendedWorkers(req recommendedworkersdomain.GetWorkers) ([]jobdomain.User, error) {
+
+// EnsureIndexes crea el índice geoespacial que necesita $geoNear.
+func (r *RecommendedWorkersRepository) EnsureIndexes(ctx context.Context) error {
+	recommendedColl := r.mongoClient.Database("NEXO-VECINAL").Collection("RecommendedWorkers")
+	_, err := recommendedColl.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "geoPoint", Value: "2dsphere"}},
+	})
+	return err
+}
+
+// GetRecommendedWorkers ordena los trabajadores cercanos por puntaje y luego pagina.
+// La distancia se devuelve sin redondear; la redondea el servicio.
+func (r *RecommendedWorkersRepository) GetRecommendedWorkers(req recommendedworkersdomain.GetWorkers, weights recommendedworkersdomain.WorkerRankingWeights) (*recommendedworkersdomain.WorkerSearchResult, error) {
 	recommendedColl := r.mongoClient.Database("NEXO-VECINAL").Collection("RecommendedWorkers")
 
 	now := time.Now()
 	oneMonthAgo := now.AddDate(0, -1, 0)
 
-	// Parámetros del request
-	categories := req.Categories
-	GeoPoint := req.GeoPoint
-	page := req.Page
-	limit := req.Limit
-	maxDistance := req.MaxDistance
-
 	// Filtro base
 	filter := bson.M{
 		"$or": []bson.M{
@@ -45,54 +50,112 @@ func (r *RecommendedWorkersRepository) GetRecommendedWorkers(req recommendedwork
 	}
 
 	// Filtro por tags si hay
-	if len(categories) > 0 {
-		filter["tags"] = bson.M{"$in": categories}
+	if len(req.Categories) > 0 {
+		filter["tags"] = bson.M{"$in": req.Categories}
 	}
 
-	// Agregar filtro geográfico (usando centerSphere en lugar de $geoNear)
-	radiusInRadians := float64(maxDistance) / 6371000.0 // Radio de la Tierra en metros
-	filter["geoPoint"] = bson.M{
-		"$geoWithin": bson.M{
-			"$centerSphere": []interface{}{
-				GeoPoint.Coordinates,
-				radiusInRadians,
-			},
-		},
-	}
+	isPremium := bson.M{"$gt": bson.A{"$premium.SubscriptionEnd", now}}
+	baseScore := bson.M{"$add": bson.A{
+		bson.M{"$multiply": bson.A{weights.Rating, bson.M{"$divide": bson.A{bson.M{"$ifNull": bson.A{"$averageRating", 0}}, 5}}}},
+		// totalJobs / (totalJobs + JobsHalfSaturation): crece rápido al principio y se satura
+		bson.M{"$multiply": bson.A{weights.Jobs, bson.M{"$divide": bson.A{
+			bson.M{"$ifNull": bson.A{"$totalJobs", 0}},
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$totalJobs", 0}}, recommendedworkersdomain.JobsHalfSaturation}},
+		}}}},
+		bson.M{"$multiply": bson.A{weights.Distance, bson.M{"$max": bson.A{0,
+			bson.M{"$subtract": bson.A{1, bson.M{"$divide": bson.A{"$distanceMeters", req.MaxDistance}}}},
+		}}}},
+	}}
 
-	// Construcción del pipeline
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: filter}},
-		{{Key: "$skip", Value: (page - 1) * limit}},
-		{{Key: "$limit", Value: limit}},
-		{{
-			Key: "$lookup", Value: bson.M{
+		{{Key: "$geoNear", Value: bson.M{
+			"near":          bson.M{"type": "Point", "coordinates": req.GeoPoint.Coordinates},
+			"distanceField": "distanceMeters",
+			"maxDistance":   req.MaxDistance,
+			"query":         filter,
+			"spherical":     true,
+		}}},
+	}
+	// Disponibilidad según el calendario publicado en el perfil del trabajador
+	if req.AvailableAt != nil {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
 				"from":         "Users",
 				"localField":   "workerId",
 				"foreignField": "_id",
-				"as":           "userInfo",
-			},
-		}},
-		{{Key: "$unwind", Value: "$userInfo"}},
-		{{
-			Key: "$project", Value: bson.M{
-				"_id":      "$userInfo._id",
-				"NameUser": "$userInfo.NameUser",
-				"Avatar":   "$userInfo.Avatar",
-			},
-		}},
+				"as":           "calendar",
+			}}},
+			bson.D{{Key: "$unwind", Value: "$calendar"}},
+			bson.D{{Key: "$match", Value: availability.Filter("calendar.availability", req.AvailableAt.Start, req.AvailableAt.End)}},
+		)
 	}
+	pipeline = append(pipeline, mongo.Pipeline{
+		{{Key: "$addFields", Value: bson.M{"isPremium": isPremium, "baseScore": baseScore}}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$cond": bson.A{
+			"$isPremium",
+			bson.M{"$add": bson.A{"$baseScore", bson.M{"$min": bson.A{
+				bson.M{"$multiply": bson.A{"$baseScore", weights.PremiumBoost}},
+				weights.PremiumBoostCap,
+			}}}},
+			"$baseScore",
+		}}}}},
+		{{Key: "$facet", Value: bson.M{
+			"workers": mongo.Pipeline{
+				{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "distanceMeters", Value: 1}, {Key: "workerId", Value: 1}}}},
+				{{Key: "$skip", Value: (req.Page - 1) * req.Limit}},
+				{{Key: "$limit", Value: req.Limit}},
+				{{
+					Key: "$lookup", Value: bson.M{
+						"from":         "Users",
+						"localField":   "workerId",
+						"foreignField": "_id",
+						"as":           "userInfo",
+					},
+				}},
+				{{Key: "$unwind", Value: "$userInfo"}},
+				{{
+					Key: "$project", Value: bson.M{
+						"_id":            "$userInfo._id",
+						"NameUser":       "$userInfo.NameUser",
+						"Avatar":         "$userInfo.Avatar",
+						"tags":           1,
+						"averageRating":  1,
+						"totalJobs":      1,
+						"isPremium":      1,
+						"distanceMeters": 1,
+						"score":          1,
+					},
+				}},
+			},
+			"total": mongo.Pipeline{{{Key: "$count", Value: "count"}}},
+		}}},
+	}...)
 
 	ctx := context.Background()
 	cursor, err := recommendedColl.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("error executing aggregation: %v", err)
 	}
+	defer cursor.Close(ctx)
 
-	var users []jobdomain.User
-	if err := cursor.All(ctx, &users); err != nil {
+	var facets []struct {
+		Workers []recommendedworkersdomain.WorkerCard `bson:"workers"`
+		Total   []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
 		return nil, fmt.Errorf("error decoding recommended workers: %v", err)
 	}
 
-	return users, nil
+	result := &recommendedworkersdomain.WorkerSearchResult{Workers: []recommendedworkersdomain.WorkerCard{}}
+	if len(facets) > 0 {
+		if facets[0].Workers != nil {
+			result.Workers = facets[0].Workers
+		}
+		if len(facets[0].Total) > 0 {
+			result.Total = facets[0].Total[0].Count
+		}
+	}
+	return result, nil
 }
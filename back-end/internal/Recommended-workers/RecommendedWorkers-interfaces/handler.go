@@ -37,7 +37,16 @@ func (rw *RecommendedWorkersHandler) GetRecommendedUsersHandler(c *fiber.Ctx) er
 		})
 	}
 
-	users, err := rw.Service.GetRecommendedWorkers(req)
+	if req.AvailableAt != nil {
+		if err := req.AvailableAt.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid parameters",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	result, err := rw.Service.GetRecommendedWorkers(req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "Error retrieving recommended users",
@@ -46,7 +55,8 @@ func (rw *RecommendedWorkersHandler) GetRecommendedUsersHandler(c *fiber.Ctx) er
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"recommendedUsers": users,
+		"recommendedUsers": result.Workers,
+		"total":            result.Total,
 		"page":             req.Page,
 		"limit":            req.Limit,
 	})
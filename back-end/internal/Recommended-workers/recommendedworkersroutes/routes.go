@@ -5,6 +5,8 @@ import (
 	recommendedworkersinfrastructure "back-end/internal/Recommended-workers/RecommendedWorkers-infrastructure"
 	recommendedworkersinterfaces "back-end/internal/Recommended-workers/RecommendedWorkers-interfaces"
 	"back-end/pkg/middleware"
+	"context"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
@@ -15,6 +17,9 @@ func RecommendedWorkersRoutes(app *fiber.App, redisClient *redis.Client, mongoCl
 	repo := recommendedworkersinfrastructure.NewRecommendedWorkersRepository(redisClient, mongoClient)
 	service := recommendedworkersapplication.NewRecommendedWorkersService(repo)
 	handler := recommendedworkersinterfaces.NewRecommendedWorkersHandler(service)
+	if err := repo.EnsureIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de trabajadores recomendados:", err)
+	}
 
 	app.Post("/workers/recommended", middleware.UseExtractor(), handler.GetRecommendedUsersHandler)
 
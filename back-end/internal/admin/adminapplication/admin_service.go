@@ -6,19 +6,24 @@ import (
 	"back-end/internal/admin/admindomain"
 	"back-end/internal/admin/admininfrastructure"
 	userdomain "back-end/internal/user/user-domain"
+	userinfrastructure "back-end/internal/user/user-infrastructure"
+	"back-end/pkg/outbox"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// ReportService contiene la lógica de negocio para reportes y bloqueo.
+// ReportService contiene la lógica de negocio para reportes y bloqueo. Las acciones privilegiadas
+// reciben el AuditActor y quedan registradas en el log de auditoría.
 type ReportService struct {
 	ReportRepository *admininfrastructure.ReportRepository
+	UserRepository   *userinfrastructure.UserRepository
 }
 
 // NewReportService crea una nueva instancia de ReportService.
-func NewReportService(repo *admininfrastructure.ReportRepository) *ReportService {
+func NewReportService(repo *admininfrastructure.ReportRepository, userRepo *userinfrastructure.UserRepository) *ReportService {
 	return &ReportService{
 		ReportRepository: repo,
+		UserRepository:   userRepo,
 	}
 }
 
@@ -48,13 +53,17 @@ func (s *ReportService) MarkReportAsRead(ctx context.Context, reportID string) e
 }
 
 // BlockUser bloquea a un usuario.
-func (s *ReportService) BlockUser(ctx context.Context, userID string) error {
-	return s.ReportRepository.BlockUser(ctx, userID)
+func (s *ReportService) BlockUser(ctx context.Context, actor admindomain.AuditActor, userID string) error {
+	return s.audited(ctx, actor, admindomain.AuditUserBlock, admindomain.AuditTargetUser, userID, "", func(ctx context.Context) error {
+		return s.ReportRepository.BlockUser(ctx, userID, actor.ID)
+	})
 }
 
-// UnblockUser bloquea a un usuario.
-func (s *ReportService) UnblockUser(ctx context.Context, userID string) error {
-	return s.ReportRepository.UnblockUser(ctx, userID)
+// UnblockUser desbloquea a un usuario.
+func (s *ReportService) UnblockUser(ctx context.Context, actor admindomain.AuditActor, userID string) error {
+	return s.audited(ctx, actor, admindomain.AuditUserUnblock, admindomain.AuditTargetUser, userID, "", func(ctx context.Context) error {
+		return s.ReportRepository.UnblockUser(ctx, userID, actor.ID)
+	})
 }
 
 // CheckAdminAuthorization valida que el solicitante sea administrador.
@@ -65,22 +74,30 @@ func (s *ReportService) GetAllTags(ctx context.Context) ([]string, error) {
 	return s.ReportRepository.GetAllTags(ctx)
 }
 
-func (s *ReportService) AddTag(ctx context.Context, tag string) error {
-	return s.ReportRepository.AddTag(ctx, tag)
+func (s *ReportService) AddTag(ctx context.Context, actor admindomain.AuditActor, tag string) error {
+	return s.audited(ctx, actor, admindomain.AuditTagAdd, admindomain.AuditTargetTag, tag, "", func(ctx context.Context) error {
+		return s.ReportRepository.AddTag(ctx, tag)
+	})
 }
 
-func (s *ReportService) RemoveTag(ctx context.Context, tag string) error {
-	return s.ReportRepository.RemoveTag(ctx, tag)
+func (s *ReportService) RemoveTag(ctx context.Context, actor admindomain.AuditActor, tag string) error {
+	return s.audited(ctx, actor, admindomain.AuditTagRemove, admindomain.AuditTargetTag, tag, "", func(ctx context.Context) error {
+		return s.ReportRepository.RemoveTag(ctx, tag)
+	})
 }
 
-// BlockUser bloquea a un usuario.
-func (s *ReportService) DeleteJob(ctx context.Context, userID string) error {
-	return s.ReportRepository.DeleteJob(ctx, userID)
+// DeleteJob da de baja un trabajo y cierra sus reportes a nombre del admin.
+func (s *ReportService) DeleteJob(ctx context.Context, actor admindomain.AuditActor, jobID string, note string) error {
+	return s.audited(ctx, actor, admindomain.AuditJobDelete, admindomain.AuditTargetJob, jobID, note, func(ctx context.Context) error {
+		return s.ReportRepository.DeleteJob(ctx, jobID, actor.ID, note)
+	})
 }
 
-// BlockUser bloquea a un usuario.
-func (s *ReportService) DeletePost(ctx context.Context, userID primitive.ObjectID) error {
-	return s.ReportRepository.DeletePost(ctx, userID)
+// DeletePost da de baja un post y cierra sus reportes a nombre del admin.
+func (s *ReportService) DeletePost(ctx context.Context, actor admindomain.AuditActor, postID primitive.ObjectID, note string) error {
+	return s.audited(ctx, actor, admindomain.AuditPostDelete, admindomain.AuditTargetPost, postID.Hex(), note, func(ctx context.Context) error {
+		return s.ReportRepository.DeletePost(ctx, postID, actor.ID, note)
+	})
 }
 
 // BlockUser bloquea a un usuario.
@@ -89,20 +106,77 @@ func (s *ReportService) CreateOrUpdateContentReport(req admindomain.ReportDetail
 	return s.ReportRepository.CreateOrUpdateContentReport(ctx, req, userId)
 }
 
-func (s *ReportService) GetContentReports(ctx context.Context, page int) ([]admindomain.ContentReport, error) {
-	return s.ReportRepository.GetContentReports(ctx, page)
+func (s *ReportService) GetContentReports(ctx context.Context, filter admindomain.ContentReportFilter) ([]admindomain.ContentReport, error) {
+	return s.ReportRepository.GetContentReports(ctx, filter)
 }
 
-func (s *ReportService) DeleteContentReport(ctx context.Context, report primitive.ObjectID) error {
-	return s.ReportRepository.DeleteContentReport(ctx, report)
+// GetContentReport devuelve un reporte de contenido con sus notas y resolución.
+func (s *ReportService) GetContentReport(ctx context.Context, reportID primitive.ObjectID) (*admindomain.ContentReport, error) {
+	return s.ReportRepository.GetContentReport(ctx, reportID)
+}
+
+// DismissContentReport cierra un reporte sin medidas sobre el contenido.
+func (s *ReportService) DismissContentReport(ctx context.Context, actor admindomain.AuditActor, reportID primitive.ObjectID, note string) error {
+	return s.audited(ctx, actor, admindomain.AuditReportDismiss, admindomain.AuditTargetContentReport, reportID.Hex(), note, func(ctx context.Context) error {
+		return s.ReportRepository.DismissContentReport(ctx, reportID, actor.ID, note)
+	})
+}
+
+// AssignContentReport asigna un reporte a un moderador.
+func (s *ReportService) AssignContentReport(ctx context.Context, actor admindomain.AuditActor, reportID, assigneeID primitive.ObjectID) error {
+	return s.audited(ctx, actor, admindomain.AuditReportAssign, admindomain.AuditTargetContentReport, reportID.Hex(), "", func(ctx context.Context) error {
+		return s.ReportRepository.AssignContentReport(ctx, reportID, assigneeID)
+	})
+}
+
+// AddContentReportNote agrega una nota interna a un reporte.
+func (s *ReportService) AddContentReportNote(ctx context.Context, actor admindomain.AuditActor, reportID primitive.ObjectID, text string) error {
+	return s.audited(ctx, actor, admindomain.AuditReportNote, admindomain.AuditTargetContentReport, reportID.Hex(), text, func(ctx context.Context) error {
+		return s.ReportRepository.AddContentReportNote(ctx, reportID, actor.ID, text)
+	})
+}
+
+// ResolveHeldContent libera o descarta un contenido retenido por la moderación automática.
+func (s *ReportService) ResolveHeldContent(ctx context.Context, actor admindomain.AuditActor, reportID primitive.ObjectID, approve bool, note string) error {
+	action := admindomain.AuditHeldDiscard
+	if approve {
+		action = admindomain.AuditHeldRelease
+	}
+	return s.audited(ctx, actor, action, admindomain.AuditTargetContentReport, reportID.Hex(), note, func(ctx context.Context) error {
+		return s.ReportRepository.ResolveHeldContent(ctx, reportID, actor.ID, approve, note)
+	})
 }
 
 func (s *ReportService) GetUsersNameUser(nameUser string) ([]*userdomain.GetUser, error) {
 	return s.ReportRepository.GetUserByNameUserIndex(nameUser)
 }
-func (s *ReportService) DisableUserForWork(ctx context.Context, report primitive.ObjectID) error {
-	return s.ReportRepository.DisableUserForWork(ctx, report)
+func (s *ReportService) DisableUserForWork(ctx context.Context, actor admindomain.AuditActor, userID primitive.ObjectID) error {
+	return s.audited(ctx, actor, admindomain.AuditUserDisableWork, admindomain.AuditTargetUser, userID.Hex(), "", func(ctx context.Context) error {
+		return s.ReportRepository.DisableUserForWork(ctx, userID)
+	})
+}
+func (s *ReportService) EnableUserForWork(ctx context.Context, actor admindomain.AuditActor, userID primitive.ObjectID) error {
+	return s.audited(ctx, actor, admindomain.AuditUserEnableWork, admindomain.AuditTargetUser, userID.Hex(), "", func(ctx context.Context) error {
+		return s.ReportRepository.EnableUserForWork(ctx, userID)
+	})
+}
+
+// ChangeNameUser cambia el NameUser de un usuario. Requiere el código de un admin de nivel 1.
+func (s *ReportService) ChangeNameUser(ctx context.Context, actor admindomain.AuditActor, req userdomain.ChangeNameUser) error {
+	userID, err := s.ReportRepository.FindUserIDByNameUser(ctx, req.NameUserRemove)
+	if err != nil {
+		return err
+	}
+	return s.audited(ctx, actor, admindomain.AuditUserChangeName, admindomain.AuditTargetUser, userID.Hex(), "", func(ctx context.Context) error {
+		return s.UserRepository.ChangeNameUserCodeAdmin(ctx, req, actor.ID)
+	})
+}
+
+func (s *ReportService) GetDeadLetterEvents(ctx context.Context, page int) ([]outbox.Event, error) {
+	return s.ReportRepository.GetDeadLetterEvents(ctx, page)
 }
-func (s *ReportService) EnableUserForWork(ctx context.Context, report primitive.ObjectID) error {
-	return s.ReportRepository.EnableUserForWork(ctx, report)
+func (s *ReportService) RetryDeadLetterEvent(ctx context.Context, actor admindomain.AuditActor, eventID primitive.ObjectID) error {
+	return s.audited(ctx, actor, admindomain.AuditOutboxRetryEvent, admindomain.AuditTargetOutboxEvent, eventID.Hex(), "", func(ctx context.Context) error {
+		return s.ReportRepository.RetryDeadLetterEvent(ctx, eventID)
+	})
 }
@@ -0,0 +1,77 @@
+package adminapplication
+
+import (
+	"context"
+	"fmt"
+
+	"back-end/internal/admin/admindomain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Límite por defecto y máximo de entradas por página del log de auditoría.
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 200
+)
+
+// audited ejecuta run y registra la acción en el log de auditoría con el estado del objetivo antes
+// y después, todo en una misma transacción: si run o el registro fallan, la acción no ocurrió.
+// run debe usar el ctx que recibe para que sus escrituras formen parte de la transacción.
+func (s *ReportService) audited(ctx context.Context, actor admindomain.AuditActor, action admindomain.AuditAction, targetType, targetID, note string, run func(ctx context.Context) error) error {
+	return s.ReportRepository.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		before, err := s.snapshot(sessCtx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		if err := run(sessCtx); err != nil {
+			return err
+		}
+		after, err := s.snapshot(sessCtx, targetType, targetID)
+		if err != nil {
+			return fmt.Errorf("no se pudo registrar la auditoría: %v", err)
+		}
+		err = s.ReportRepository.RecordAudit(sessCtx, admindomain.AuditEntry{
+			ActorID:    actor.ID,
+			Action:     action,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Before:     before,
+			After:      after,
+			Note:       note,
+			IP:         actor.IP,
+		})
+		if err != nil {
+			return fmt.Errorf("no se pudo registrar la auditoría: %v", err)
+		}
+		return nil
+	})
+}
+
+// snapshot devuelve el estado auditado del objetivo; los objetivos sin ObjectID (tags) no tienen estado.
+func (s *ReportService) snapshot(ctx context.Context, targetType, targetID string) (bson.M, error) {
+	oid, err := primitive.ObjectIDFromHex(targetID)
+	if err != nil {
+		return nil, nil
+	}
+	return s.ReportRepository.Snapshot(ctx, targetType, oid)
+}
+
+// GetAuditLog consulta el log de auditoría.
+func (s *ReportService) GetAuditLog(ctx context.Context, filter admindomain.AuditFilter) ([]admindomain.AuditEntry, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("el rango de fechas es inválido")
+	}
+	return s.ReportRepository.GetAuditLog(ctx, filter)
+}
@@ -0,0 +1,42 @@
+package adminapplication
+
+import (
+	"context"
+	"time"
+
+	"back-end/internal/admin/admindomain"
+	"back-end/pkg/sanctions"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IssueSanction sanciona a userID. La duración se ignora en los baneos y es opcional en las advertencias.
+func (s *ReportService) IssueSanction(ctx context.Context, actor admindomain.AuditActor, userID primitive.ObjectID, sanctionType sanctions.Type, reason string, duration time.Duration) (sanctions.Sanction, error) {
+	sanction, err := sanctions.New(userID, actor.ID, sanctionType, reason, duration)
+	if err != nil {
+		return sanctions.Sanction{}, err
+	}
+	err = s.audited(ctx, actor, admindomain.AuditSanctionIssue, admindomain.AuditTargetSanction, sanction.ID.Hex(), sanction.Reason, func(ctx context.Context) error {
+		return s.ReportRepository.IssueSanction(ctx, sanction)
+	})
+	return sanction, err
+}
+
+// LiftSanction levanta una sanción vigente antes de que venza.
+func (s *ReportService) LiftSanction(ctx context.Context, actor admindomain.AuditActor, sanctionID primitive.ObjectID, note string) error {
+	return s.audited(ctx, actor, admindomain.AuditSanctionLift, admindomain.AuditTargetSanction, sanctionID.Hex(), note, func(ctx context.Context) error {
+		return s.ReportRepository.LiftSanction(ctx, sanctionID, actor.ID, note)
+	})
+}
+
+// RejectSanctionAppeal rechaza la apelación pendiente de una sanción, que sigue vigente.
+func (s *ReportService) RejectSanctionAppeal(ctx context.Context, actor admindomain.AuditActor, sanctionID primitive.ObjectID, note string) error {
+	return s.audited(ctx, actor, admindomain.AuditAppealReject, admindomain.AuditTargetSanction, sanctionID.Hex(), note, func(ctx context.Context) error {
+		return s.ReportRepository.RejectSanctionAppeal(ctx, sanctionID)
+	})
+}
+
+// GetUserSanctions devuelve el historial de sanciones de un usuario, para graduar la siguiente.
+func (s *ReportService) GetUserSanctions(ctx context.Context, userID primitive.ObjectID) ([]sanctions.Sanction, error) {
+	return s.ReportRepository.GetUserSanctions(ctx, userID)
+}
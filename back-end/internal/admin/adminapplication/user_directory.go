@@ -0,0 +1,151 @@
+package adminapplication
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"back-end/internal/admin/admindomain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Límites de página del directorio de usuarios y de filas de la exportación CSV.
+const (
+	defaultUserDirectoryLimit = 50
+	maxUserDirectoryLimit     = 200
+	maxUserExportRows         = 10000
+)
+
+// normalizeUserDirectoryFilter aplica los valores por defecto y valida el rango de fechas.
+func normalizeUserDirectoryFilter(filter *admindomain.UserDirectoryFilter) error {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultUserDirectoryLimit
+	}
+	if filter.Limit > maxUserDirectoryLimit {
+		filter.Limit = maxUserDirectoryLimit
+	}
+	if filter.Sort == "" {
+		filter.Sort = admindomain.UserSortRegistered
+	}
+	if filter.RegisteredFrom != nil && filter.RegisteredTo != nil && !filter.RegisteredFrom.Before(*filter.RegisteredTo) {
+		return fmt.Errorf("el rango de fechas es inválido")
+	}
+	return nil
+}
+
+// SearchUsers devuelve una página del directorio de usuarios.
+func (s *ReportService) SearchUsers(ctx context.Context, filter admindomain.UserDirectoryFilter) (admindomain.UserDirectoryPage, error) {
+	if err := normalizeUserDirectoryFilter(&filter); err != nil {
+		return admindomain.UserDirectoryPage{}, err
+	}
+	return s.ReportRepository.SearchUsers(ctx, filter)
+}
+
+var userExportHeader = []string{
+	"id", "NameUser", "FullName", "Email", "Pais", "Ciudad", "Intentions", "Banned", "Premium",
+	"PremiumEnd", "Soporte", "availableToWork", "completedJobs", "Ratio", "registeredAt", "LastConnection",
+}
+
+// csvCell evita que una planilla interprete como fórmula un texto cargado por el usuario.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// describeUserFilter resume el filtro de una exportación para el log de auditoría.
+func describeUserFilter(filter admindomain.UserDirectoryFilter) string {
+	parts := []string{"sort=" + string(filter.Sort)}
+	add := func(name, value string) {
+		if value != "" {
+			parts = append(parts, name+"="+value)
+		}
+	}
+	addBool := func(name string, value *bool) {
+		if value != nil {
+			add(name, strconv.FormatBool(*value))
+		}
+	}
+	addTime := func(name string, value *time.Time) {
+		if value != nil {
+			add(name, csvTime(*value))
+		}
+	}
+	add("q", filter.Query)
+	addBool("banned", filter.Banned)
+	addBool("premium", filter.Premium)
+	addBool("support", filter.Support)
+	addBool("availableToWork", filter.AvailableToWork)
+	add("intentions", filter.Intentions)
+	add("country", filter.Country)
+	add("city", filter.City)
+	addTime("registeredFrom", filter.RegisteredFrom)
+	addTime("registeredTo", filter.RegisteredTo)
+	return strings.Join(parts, " ")
+}
+
+// ExportUsersCSV escribe en w, como CSV, hasta 10000 usuarios que cumplen el filtro en su orden
+// (se ignora la paginación). La exportación incluye datos personales y queda auditada.
+func (s *ReportService) ExportUsersCSV(ctx context.Context, actor admindomain.AuditActor, filter admindomain.UserDirectoryFilter, w io.Writer) error {
+	if err := normalizeUserDirectoryFilter(&filter); err != nil {
+		return err
+	}
+	// La auditoría se registra antes de leer los datos; las filas se leen fuera de la transacción
+	// con un cursor común, así una exportación grande no choca con el límite de tiempo de la transacción
+	err := s.audited(ctx, actor, admindomain.AuditUserExport, admindomain.AuditTargetUser, "", describeUserFilter(filter), func(ctx context.Context) error {
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(userExportHeader); err != nil {
+		return err
+	}
+	err = s.ReportRepository.EachUser(ctx, filter, maxUserExportRows, func(user admindomain.UserDirectoryEntry) error {
+		return writer.Write([]string{
+			user.ID.Hex(),
+			csvCell(user.NameUser),
+			csvCell(user.FullName),
+			csvCell(user.Email),
+			csvCell(user.Pais),
+			csvCell(user.Ciudad),
+			csvCell(user.Intentions),
+			strconv.FormatBool(user.Banned),
+			strconv.FormatBool(user.IsPremium),
+			csvTime(user.Premium.SubscriptionEnd),
+			csvCell(user.Soporte),
+			strconv.FormatBool(user.Available),
+			strconv.Itoa(user.CompletedJobs),
+			strconv.FormatFloat(user.Ratio, 'f', 1, 64),
+			csvTime(user.RegisteredAt),
+			csvTime(user.LastConnection),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// GetUserDetail devuelve la vista de un usuario con su actividad, sanciones y premium.
+func (s *ReportService) GetUserDetail(ctx context.Context, userID primitive.ObjectID) (*admindomain.UserDetail, error) {
+	return s.ReportRepository.GetUserDetail(ctx, userID)
+}
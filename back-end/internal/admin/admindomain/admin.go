@@ -38,20 +38,33 @@ type Tag struct {
 type ContentReport struct {
 	ID                primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	ReportedContentID primitive.ObjectID `json:"reportedContentId" bson:"reportedContentId"`
-	ContentType       string             `json:"contentType" bson:"contentType"` // "post" o "job"
+	ContentType       string             `json:"contentType" bson:"contentType"` // "post", "job" o "comment"
 	Reports           []ReportDetail     `json:"reports" bson:"reports"`
 	CreatedAt         time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt         time.Time          `json:"updatedAt" bson:"updatedAt"`
+	// Contenido retenido por la moderación automática: está oculto hasta que un admin lo libere o descarte
+	Held     bool                `json:"held" bson:"held,omitempty"`
+	AuthorID *primitive.ObjectID `json:"authorId,omitempty" bson:"authorId,omitempty"`
+	Reasons  []string            `json:"reasons,omitempty" bson:"reasons,omitempty"`
+	// Flujo de moderación: estado, moderador asignado, notas internas y resolución
+	Status        ReportStatus        `json:"status" bson:"status"`
+	AssignedTo    *primitive.ObjectID `json:"assignedTo,omitempty" bson:"assignedTo,omitempty"`
+	AssignedAt    *time.Time          `json:"assignedAt,omitempty" bson:"assignedAt,omitempty"`
+	Notes         []ReportNote        `json:"notes,omitempty" bson:"notes,omitempty"`
+	Resolution    *ReportResolution   `json:"resolution,omitempty" bson:"resolution,omitempty"`
+	ReporterCount int                 `json:"reporterCount" bson:"reporterCount,omitempty"` // Usuarios distintos que lo reportaron
 }
 
 type ReportDetail struct {
 	ReporterUserID primitive.ObjectID `json:"reporterUserId" bson:"reporterUserId"`
+	Reason         ReportReason       `json:"reason,omitempty" bson:"reason,omitempty"`
 	Description    string             `json:"description" bson:"description"`
 	ReportedAt     time.Time          `json:"reportedAt" bson:"reportedAt"`
 }
 
 type ReportDetailReq struct {
-	ContentType       string             `bson:"contentType"` // "post" o "job"
+	ContentType       string             `bson:"contentType"` // "post", "job" o "comment"
+	Reason            string             `bson:"reason"`      // Ver ReportReason; vacío equivale a "other"
 	Description       string             `bson:"description"`
 	ReportedAt        time.Time          `bson:"reportedAt"`
 	ReportedContentID primitive.ObjectID `bson:"reportedContentId"`
@@ -0,0 +1,77 @@
+package admindomain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditAction identifica una acción privilegiada registrada en el log de auditoría.
+type AuditAction string
+
+const (
+	AuditUserBlock        AuditAction = "user.block"
+	AuditUserUnblock      AuditAction = "user.unblock"
+	AuditUserDisableWork  AuditAction = "user.disable_work"
+	AuditUserEnableWork   AuditAction = "user.enable_work"
+	AuditUserChangeName   AuditAction = "user.change_name"
+	AuditUserExport       AuditAction = "user.export"
+	AuditJobDelete        AuditAction = "job.delete"
+	AuditPostDelete       AuditAction = "post.delete"
+	AuditTagAdd           AuditAction = "tag.add"
+	AuditTagRemove        AuditAction = "tag.remove"
+	AuditReportDismiss    AuditAction = "content_report.dismiss"
+	AuditReportAssign     AuditAction = "content_report.assign"
+	AuditReportNote       AuditAction = "content_report.note"
+	AuditHeldRelease      AuditAction = "held_content.release"
+	AuditHeldDiscard      AuditAction = "held_content.discard"
+	AuditOutboxRetryEvent AuditAction = "outbox.retry"
+	AuditSanctionIssue    AuditAction = "sanction.issue"
+	AuditSanctionLift     AuditAction = "sanction.lift"
+	AuditAppealReject     AuditAction = "sanction.appeal_reject"
+)
+
+// Tipos de objetivo de una acción auditada.
+const (
+	AuditTargetUser          = "user"
+	AuditTargetJob           = "job"
+	AuditTargetPost          = "post"
+	AuditTargetTag           = "tag"
+	AuditTargetContentReport = "content_report"
+	AuditTargetOutboxEvent   = "outbox_event"
+	AuditTargetSanction      = "sanction"
+)
+
+// AuditActor es el admin que realiza la acción y desde dónde.
+type AuditActor struct {
+	ID primitive.ObjectID
+	IP string
+}
+
+// AuditEntry es una entrada del log de auditoría. Las entradas no se modifican ni se borran.
+// Before y After son instantáneas de los campos relevantes del objetivo; quedan vacías cuando
+// el objetivo no tiene estado (por ejemplo, un tag).
+type AuditEntry struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ActorID    primitive.ObjectID `json:"actorId" bson:"actorId"`
+	Action     AuditAction        `json:"action" bson:"action"`
+	TargetType string             `json:"targetType" bson:"targetType"`
+	TargetID   string             `json:"targetId" bson:"targetId"` // ObjectID en hex, o el nombre en el caso de los tags
+	Before     bson.M             `json:"before,omitempty" bson:"before,omitempty"`
+	After      bson.M             `json:"after,omitempty" bson:"after,omitempty"`
+	Note       string             `json:"note,omitempty" bson:"note,omitempty"`
+	IP         string             `json:"ip" bson:"ip"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// AuditFilter filtra el log de auditoría; los campos vacíos no filtran.
+type AuditFilter struct {
+	ActorID  *primitive.ObjectID
+	TargetID string
+	Action   AuditAction
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	Limit    int
+}
@@ -0,0 +1,104 @@
+package admindomain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReportReason es la categoría que elige el usuario al reportar un contenido.
+type ReportReason string
+
+const (
+	ReasonSpam          ReportReason = "spam"
+	ReasonScam          ReportReason = "scam" // Estafa o fraude
+	ReasonHarassment    ReportReason = "harassment"
+	ReasonHate          ReportReason = "hate"
+	ReasonViolence      ReportReason = "violence"
+	ReasonInappropriate ReportReason = "inappropriate" // Contenido sexual u ofensivo
+	ReasonAutomated     ReportReason = "automated"     // Retenido por la moderación automática
+	ReasonOther         ReportReason = "other"
+)
+
+// ParseReportReason valida el motivo de un reporte; vacío equivale a "other". Los usuarios no
+// pueden usar el motivo de la moderación automática.
+func ParseReportReason(value string) (ReportReason, error) {
+	reason := ReportReason(strings.ToLower(strings.TrimSpace(value)))
+	switch reason {
+	case "":
+		return ReasonOther, nil
+	case ReasonSpam, ReasonScam, ReasonHarassment, ReasonHate, ReasonViolence, ReasonInappropriate, ReasonOther:
+		return reason, nil
+	}
+	return "", errors.New("motivo de reporte inválido")
+}
+
+// ReportStatus es el estado de un reporte de contenido en la cola de moderación.
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"      // Sin revisar
+	ReportInReview  ReportStatus = "in_review" // Asignado a un moderador
+	ReportActioned  ReportStatus = "actioned"  // Se tomó una medida sobre el contenido
+	ReportDismissed ReportStatus = "dismissed" // Se descartó sin medidas
+)
+
+// Resolved indica si el reporte ya se cerró.
+func (s ReportStatus) Resolved() bool {
+	return s == ReportActioned || s == ReportDismissed
+}
+
+// ParseReportStatus valida un estado recibido por query.
+func ParseReportStatus(value string) (ReportStatus, error) {
+	status := ReportStatus(value)
+	switch status {
+	case ReportOpen, ReportInReview, ReportActioned, ReportDismissed:
+		return status, nil
+	}
+	return "", errors.New("estado de reporte inválido")
+}
+
+// Acciones con las que se cierra un reporte.
+const (
+	ResolutionContentRemoved   = "content_removed"   // El admin dio de baja el post o trabajo
+	ResolutionContentReleased  = "content_released"  // Se publicó el contenido retenido
+	ResolutionContentDiscarded = "content_discarded" // Se descartó el contenido retenido
+	ResolutionDismissed        = "dismissed"         // El reporte no procedía
+)
+
+// ReportNote es una nota interna de un moderador sobre un reporte.
+type ReportNote struct {
+	AuthorID  primitive.ObjectID `json:"authorId" bson:"authorId"`
+	Text      string             `json:"text" bson:"text"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// ReportResolution registra quién cerró un reporte, cuándo y con qué acción.
+type ReportResolution struct {
+	Status     ReportStatus       `json:"status" bson:"status"`
+	Action     string             `json:"action" bson:"action"`
+	ResolvedBy primitive.ObjectID `json:"resolvedBy" bson:"resolvedBy"`
+	Note       string             `json:"note,omitempty" bson:"note,omitempty"`
+	ResolvedAt time.Time          `json:"resolvedAt" bson:"resolvedAt"`
+}
+
+// NewReportResolution arma la resolución de un reporte cerrado por adminID.
+func NewReportResolution(status ReportStatus, action string, adminID primitive.ObjectID, note string) ReportResolution {
+	return ReportResolution{
+		Status:     status,
+		Action:     action,
+		ResolvedBy: adminID,
+		Note:       strings.TrimSpace(note),
+		ResolvedAt: time.Now(),
+	}
+}
+
+// ContentReportFilter filtra la cola de reportes de contenido. Sin Status se listan los
+// reportes sin cerrar (abiertos y en revisión).
+type ContentReportFilter struct {
+	Status      ReportStatus
+	ContentType string
+	Page        int
+}
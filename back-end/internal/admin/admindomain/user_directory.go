@@ -0,0 +1,138 @@
+package admindomain
+
+import (
+	"errors"
+	"time"
+
+	userdomain "back-end/internal/user/user-domain"
+	"back-end/pkg/sanctions"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserSort es el orden del directorio de usuarios; cada valor corresponde a un campo de Users.
+type UserSort string
+
+const (
+	UserSortRegistered     UserSort = "registered"
+	UserSortNameUser       UserSort = "nameUser"
+	UserSortLastConnection UserSort = "lastConnection"
+	UserSortCompletedJobs  UserSort = "completedJobs"
+	UserSortRating         UserSort = "rating"
+)
+
+// Field devuelve el campo de Users por el que se ordena.
+func (s UserSort) Field() string {
+	switch s {
+	case UserSortNameUser:
+		return "NameUser"
+	case UserSortLastConnection:
+		return "LastConnection"
+	case UserSortCompletedJobs:
+		return "completedJobs"
+	case UserSortRating:
+		return "ratio"
+	}
+	return "Timestamp"
+}
+
+// ParseUserSort valida el orden recibido por query; vacío equivale a la fecha de registro.
+func ParseUserSort(value string) (UserSort, error) {
+	sort := UserSort(value)
+	switch sort {
+	case "":
+		return UserSortRegistered, nil
+	case UserSortRegistered, UserSortNameUser, UserSortLastConnection, UserSortCompletedJobs, UserSortRating:
+		return sort, nil
+	}
+	return "", errors.New("orden inválido")
+}
+
+// UserDirectoryFilter filtra el directorio de usuarios; los campos nil o vacíos no filtran.
+type UserDirectoryFilter struct {
+	Query           string // Texto contenido en NameUser, FullName o Email
+	Banned          *bool
+	Premium         *bool // Con una suscripción vigente
+	Support         *bool // Agente de soporte activo
+	AvailableToWork *bool
+	Intentions      string
+	Country         string
+	City            string
+	RegisteredFrom  *time.Time
+	RegisteredTo    *time.Time // Exclusivo
+	Sort            UserSort
+	Ascending       bool
+	Page            int
+	Limit           int
+}
+
+// UserDirectoryEntry es la fila de un usuario en el directorio y en la exportación CSV.
+type UserDirectoryEntry struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	NameUser       string             `json:"NameUser" bson:"NameUser"`
+	FullName       string             `json:"FullName" bson:"FullName"`
+	Email          string             `json:"Email" bson:"Email"`
+	Avatar         string             `json:"Avatar" bson:"Avatar"`
+	Pais           string             `json:"Pais" bson:"Pais"`
+	Ciudad         string             `json:"Ciudad" bson:"Ciudad"`
+	Intentions     string             `json:"Intentions" bson:"Intentions"`
+	Banned         bool               `json:"Banned" bson:"Banned"`
+	Premium        userdomain.Premium `json:"Premium" bson:"Premium"`
+	IsPremium      bool               `json:"isPremium" bson:"-"`
+	Soporte        string             `json:"Soporte" bson:"Soporte"`
+	Available      bool               `json:"availableToWork" bson:"availableToWork"`
+	CompletedJobs  int                `json:"completedJobs" bson:"completedJobs"`
+	Ratio          float64            `json:"Ratio" bson:"ratio"`
+	RegisteredAt   time.Time          `json:"registeredAt" bson:"Timestamp"`
+	LastConnection time.Time          `json:"LastConnection" bson:"LastConnection"`
+}
+
+// UserDirectoryPage es una página del directorio con el total de usuarios que cumplen el filtro.
+type UserDirectoryPage struct {
+	Users []UserDirectoryEntry `json:"users"`
+	Total int64                `json:"total"`
+	Page  int                  `json:"page"`
+	Limit int                  `json:"limit"`
+}
+
+// UserJobSummary es un trabajo reciente en el que participó el usuario.
+type UserJobSummary struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Title     string             `json:"title" bson:"title"`
+	Status    string             `json:"status" bson:"status"`
+	Role      string             `json:"role" bson:"role"` // "employer" o "worker"
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// UserJobsActivity resume los trabajos publicados y realizados por el usuario, por estado.
+type UserJobsActivity struct {
+	PostedByStatus map[string]int   `json:"postedByStatus"`
+	WorkedByStatus map[string]int   `json:"workedByStatus"`
+	Recent         []UserJobSummary `json:"recent"`
+}
+
+// RatingSummary es el promedio de las calificaciones recibidas en un rol.
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// UserReportsActivity reúne los reportes recibidos y hechos por el usuario.
+type UserReportsActivity struct {
+	ReceivedCount     int64        `json:"receivedCount"`
+	Received          []UserReport `json:"received"`
+	FiledCount        int64        `json:"filedCount"`
+	Filed             []UserReport `json:"filed"`
+	ContentFiledCount int64        `json:"contentFiledCount"` // Reportes de contenido que hizo
+}
+
+// UserDetail es la vista de un usuario para los admins.
+type UserDetail struct {
+	User           UserDirectoryEntry               `json:"user"`
+	Jobs           UserJobsActivity                 `json:"jobs"`
+	Reports        UserReportsActivity              `json:"reports"`
+	Sanctions      []sanctions.Sanction             `json:"sanctions"`
+	WorkerRating   RatingSummary                    `json:"workerRating"`
+	EmployerRating RatingSummary                    `json:"employerRating"`
+	PremiumHistory []userdomain.PremiumHistoryEntry `json:"premiumHistory"`
+}
@@ -2,12 +2,15 @@ package admininfrastructure
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"time"
 
 	"back-end/internal/admin/admindomain"
 	userdomain "back-end/internal/user/user-domain"
+	"back-end/pkg/outbox"
+	"back-end/pkg/sanctions"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,12 +21,14 @@ import (
 // ReportRepository se encarga del acceso a datos para reportes y bloqueo de usuarios.
 type ReportRepository struct {
 	mongoClient *mongo.Client
+	sanctions   *sanctions.Store
 }
 
 // NewReportRepository crea una nueva instancia de ReportRepository.
 func NewReportRepository(mongoClient *mongo.Client) *ReportRepository {
 	return &ReportRepository{
 		mongoClient: mongoClient,
+		sanctions:   sanctions.NewStore(mongoClient),
 	}
 }
 
@@ -141,44 +146,42 @@ func (r *ReportRepository) MarkReportAsRead(ctx context.Context, reportID string
 	return nil
 }
 
-// BlockUser actualiza el campo "Banned" del usuario a true para bloquearlo.
-func (r *ReportRepository) BlockUser(ctx context.Context, userID string) error {
+// BlockUser banea al usuario con una sanción de tipo ban, que también marca Users.Banned.
+func (r *ReportRepository) BlockUser(ctx context.Context, userID string, adminID primitive.ObjectID) error {
 	oid, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return fmt.Errorf("invalid user id: %v", err)
 	}
-	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
-	update := bson.M{"$set": bson.M{"Banned": true}}
-	res, err := collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	ban, err := sanctions.New(oid, adminID, sanctions.TypeBan, "Bloqueo desde el panel de admin", 0)
 	if err != nil {
-		return fmt.Errorf("failed to block user: %v", err)
-	}
-	if res.MatchedCount == 0 {
-		return errors.New("user not found")
+		return err
 	}
-	return nil
+	return r.IssueSanction(ctx, ban)
 }
 
-// UnblockUser actualiza el campo "Banned" del usuario a true para bloquearlo.
-func (r *ReportRepository) UnblockUser(ctx context.Context, userID string) error {
+// UnblockUser levanta los baneos vigentes del usuario, lo que también desmarca Users.Banned.
+func (r *ReportRepository) UnblockUser(ctx context.Context, userID string, adminID primitive.ObjectID) error {
 	oid, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return fmt.Errorf("invalid user id: %v", err)
 	}
-	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
-	update := bson.M{"$set": bson.M{"Banned": false}}
-	res, err := collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	count, err := r.mongoClient.Database("NEXO-VECINAL").Collection("Users").CountDocuments(ctx, bson.M{"_id": oid})
 	if err != nil {
-		return fmt.Errorf("failed to block user: %v", err)
+		return fmt.Errorf("failed to check user: %v", err)
 	}
-	if res.MatchedCount == 0 {
+	if count == 0 {
 		return errors.New("user not found")
 	}
-	return nil
+	_, err = r.sanctions.LiftBans(ctx, oid, adminID, "Desbloqueo desde el panel de admin")
+	return err
 }
 
 // CheckAdminAuthorization valida que el usuario sea administrador mediante PanelAdminNexoVecinal.
+// Los usuarios comunes tienen Level 0 y Code vacío, por eso se exige la marca de admin y un código no vacío.
 func (r *ReportRepository) CheckAdminAuthorization(ctx context.Context, adminID string, code string) error {
+	if code == "" {
+		return fmt.Errorf("usuario no autorizado")
+	}
 	oid, err := primitive.ObjectIDFromHex(adminID)
 	if err != nil {
 		return fmt.Errorf("invalid admin id: %v", err)
@@ -189,8 +192,10 @@ func (r *ReportRepository) CheckAdminAuthorization(ctx context.Context, adminID
 	if err != nil {
 		return fmt.Errorf("admin not found: %v", err)
 	}
-	if admin.PanelAdminNexoVecinal.Level > 1 || admin.PanelAdminNexoVecinal.Code != code {
-
+	panel := admin.PanelAdminNexoVecinal
+	isAdmin := panel.Asset || panel.Level >= 1
+	if !isAdmin || panel.Level > 1 || panel.Code == "" ||
+		subtle.ConstantTimeCompare([]byte(panel.Code), []byte(code)) != 1 {
 		return fmt.Errorf("usuario no autorizado")
 	}
 	return nil
@@ -238,8 +243,8 @@ func (r *ReportRepository) RemoveTag(ctx context.Context, tag string) error {
 	return err
 }
 
-// "Elimina" un Job marcándolo como no disponible
-func (r *ReportRepository) DeleteJob(ctx context.Context, jobId string) error {
+// "Elimina" un Job marcándolo como no disponible y cierra sus reportes abiertos a nombre de adminID
+func (r *ReportRepository) DeleteJob(ctx context.Context, jobId string, adminID primitive.ObjectID, note string) error {
 	oid, err := primitive.ObjectIDFromHex(jobId)
 	if err != nil {
 		return fmt.Errorf("invalid job id: %v", err)
@@ -254,14 +259,15 @@ func (r *ReportRepository) DeleteJob(ctx context.Context, jobId string) error {
 	if res.MatchedCount == 0 {
 		return errors.New("job not found")
 	}
-	if err := r.DeleteContentReportForPost_Job(ctx, oid); err != nil {
-		return fmt.Errorf("job updated but failed to delete report: %v", err)
+	resolution := admindomain.NewReportResolution(admindomain.ReportActioned, admindomain.ResolutionContentRemoved, adminID, note)
+	if err := r.resolveContentReportsFor(ctx, oid, resolution); err != nil {
+		return fmt.Errorf("job updated but failed to resolve report: %v", err)
 	}
 	return nil
 }
 
-// "Elimina" un Post marcándolo como no disponible
-func (r *ReportRepository) DeletePost(ctx context.Context, PostId primitive.ObjectID) error {
+// "Elimina" un Post marcándolo como no disponible y cierra sus reportes abiertos a nombre de adminID
+func (r *ReportRepository) DeletePost(ctx context.Context, PostId primitive.ObjectID, adminID primitive.ObjectID, note string) error {
 	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("Posts")
 
 	update := bson.M{"$set": bson.M{"available": false}}
@@ -272,25 +278,33 @@ func (r *ReportRepository) DeletePost(ctx context.Context, PostId primitive.Obje
 	if res.MatchedCount == 0 {
 		return errors.New("post not found")
 	}
-	if err := r.DeleteContentReportForPost_Job(ctx, PostId); err != nil {
-		return fmt.Errorf("post updated but failed to delete report: %v", err)
+	resolution := admindomain.NewReportResolution(admindomain.ReportActioned, admindomain.ResolutionContentRemoved, adminID, note)
+	if err := r.resolveContentReportsFor(ctx, PostId, resolution); err != nil {
+		return fmt.Errorf("post updated but failed to resolve report: %v", err)
 	}
 
 	return nil
 }
 func (r *ReportRepository) CreateOrUpdateContentReport(ctx context.Context, req admindomain.ReportDetailReq, userId primitive.ObjectID) error {
+	reason, err := admindomain.ParseReportReason(req.Reason)
+	if err != nil {
+		return err
+	}
 
 	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports")
 
-	// Construimos el filtro para identificar el documento del contenido reportado.
+	// Construimos el filtro para identificar el documento del contenido reportado. Los reportes
+	// cerrados quedan como historial: un reporte nuevo abre otro documento.
 	filter := bson.M{
 		"reportedContentId": req.ReportedContentID,
 		"contentType":       req.ContentType,
+		"status":            unresolvedReports(),
 	}
 
 	// Creamos el objeto reporte individual (detalle del reporte).
 	newReport := bson.M{
 		"reporterUserId": userId,
+		"reason":         reason,
 		"description":    req.Description,
 		"reportedAt":     time.Now(),
 	}
@@ -305,59 +319,242 @@ func (r *ReportRepository) CreateOrUpdateContentReport(ctx context.Context, req
 		"$setOnInsert": bson.M{
 			"reportedContentId": req.ReportedContentID,
 			"contentType":       req.ContentType,
+			"status":            admindomain.ReportOpen,
+			"open":              true,
 			"createdAt":         time.Now(),
 		},
 	}
 
 	opts := options.Update().SetUpsert(true)
 
-	_, err := collection.UpdateOne(ctx, filter, update, opts)
+	_, err = collection.UpdateOne(ctx, filter, update, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		// Otro reporte concurrente abrió el documento: se suma a ese
+		_, err = collection.UpdateOne(ctx, filter, update, opts)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create or update content report: %v", err)
 	}
 	return nil
 }
-func (r *ReportRepository) DeleteContentReportForPost_Job(ctx context.Context, contentId primitive.ObjectID) error {
+
+// resolveContentReportsFor cierra los reportes sin cerrar de un contenido con la resolución indicada.
+func (r *ReportRepository) resolveContentReportsFor(ctx context.Context, contentId primitive.ObjectID, resolution admindomain.ReportResolution) error {
 	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports")
-	_, err := collection.DeleteOne(ctx, bson.M{"reportedContentId": contentId})
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"reportedContentId": contentId, "status": unresolvedReports()},
+		resolveUpdate(resolution),
+	)
 	if err != nil {
 		fmt.Println("error", err)
-		return fmt.Errorf("failed to delete content report: %v", err)
+		return fmt.Errorf("failed to resolve content report: %v", err)
 	}
 	return nil
 }
-func (r *ReportRepository) DeleteContentReport(ctx context.Context, contentId primitive.ObjectID) error {
+
+// DismissContentReport cierra el reporte sin medidas sobre el contenido y deja registro de quién lo decidió.
+// Si el contenido está retenido por la moderación automática, descartar el reporte lo publica.
+func (r *ReportRepository) DismissContentReport(ctx context.Context, reportID primitive.ObjectID, adminID primitive.ObjectID, note string) error {
+	held, err := r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports").
+		CountDocuments(ctx, bson.M{"_id": reportID, "held": true, "status": unresolvedReports()})
+	if err != nil {
+		return fmt.Errorf("failed to get content report: %v", err)
+	}
+	if held > 0 {
+		return r.ResolveHeldContent(ctx, reportID, adminID, true, note)
+	}
+	resolution := admindomain.NewReportResolution(admindomain.ReportDismissed, admindomain.ResolutionDismissed, adminID, note)
+	return r.resolveContentReport(ctx, reportID, resolution)
+}
+
+// resolveContentReport cierra un reporte sin cerrar con la resolución indicada.
+func (r *ReportRepository) resolveContentReport(ctx context.Context, reportID primitive.ObjectID, resolution admindomain.ReportResolution) error {
 	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports")
-	_, err := collection.DeleteOne(ctx, bson.M{"_id": contentId})
+	res, err := collection.UpdateOne(ctx,
+		bson.M{"_id": reportID, "status": unresolvedReports()},
+		resolveUpdate(resolution),
+	)
 	if err != nil {
 		fmt.Println("error", err)
-		return fmt.Errorf("failed to delete content report: %v", err)
+		return fmt.Errorf("failed to resolve content report: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("reporte no encontrado o ya cerrado")
+	}
+	return nil
+}
+
+func resolveUpdate(resolution admindomain.ReportResolution) bson.M {
+	return bson.M{
+		"$set": bson.M{
+			"status":     resolution.Status,
+			"resolution": resolution,
+			"updatedAt":  resolution.ResolvedAt,
+		},
+		"$unset": bson.M{"open": ""},
+	}
+}
+
+// unresolvedReports filtra los reportes abiertos o en revisión; los anteriores al flujo de
+// moderación no tienen status y cuentan como abiertos.
+func unresolvedReports() bson.M {
+	return bson.M{"$nin": bson.A{admindomain.ReportActioned, admindomain.ReportDismissed}}
+}
+
+// ResolveHeldContent libera (approve) o descarta el contenido retenido por la moderación automática
+// del reporte reportID y cierra el reporte a nombre de adminID. Al aprobar se registran los avisos
+// que se omitieron al crear el contenido (trabajo publicado, menciones), en la misma transacción.
+func (r *ReportRepository) ResolveHeldContent(ctx context.Context, reportID primitive.ObjectID, adminID primitive.ObjectID, approve bool, note string) error {
+	db := r.mongoClient.Database("NEXO-VECINAL")
+	var report struct {
+		admindomain.ContentReport `bson:",inline"`
+		DeferredEvents            []outbox.Event `bson:"deferredEvents"`
+	}
+	err := db.Collection("content_reports").FindOne(ctx, bson.M{"_id": reportID, "held": true, "status": unresolvedReports()}).Decode(&report)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.New("no hay contenido retenido para este reporte")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get content report: %v", err)
+	}
+
+	// Publicar lo retenido equivale a descartar el reporte automático
+	resolution := admindomain.NewReportResolution(admindomain.ReportDismissed, admindomain.ResolutionContentReleased, adminID, note)
+	if !approve {
+		resolution = admindomain.NewReportResolution(admindomain.ReportActioned, admindomain.ResolutionContentDiscarded, adminID, note)
+	}
+	store := outbox.NewStore(r.mongoClient)
+	return store.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		switch report.ContentType {
+		case "post", "job":
+			collection := db.Collection("Posts")
+			if report.ContentType == "job" {
+				collection = db.Collection("Job")
+			}
+			// Descartado queda no disponible, como un contenido borrado por un admin
+			update := bson.M{"$unset": bson.M{"held": ""}}
+			if approve {
+				update["$set"] = bson.M{"available": true}
+			}
+			if _, err := collection.UpdateOne(sessCtx, bson.M{"_id": report.ReportedContentID}, update); err != nil {
+				return fmt.Errorf("failed to update %s: %v", report.ContentType, err)
+			}
+		case "comment":
+			if err := r.resolveHeldComment(sessCtx, report.ReportedContentID, approve); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("tipo de contenido desconocido: %s", report.ContentType)
+		}
+		if err := r.resolveContentReport(sessCtx, reportID, resolution); err != nil {
+			return err
+		}
+		if _, err := db.Collection("content_reports").UpdateByID(sessCtx, reportID, bson.M{"$unset": bson.M{"deferredEvents": ""}}); err != nil {
+			return fmt.Errorf("failed to update content report: %v", err)
+		}
+		if !approve {
+			return nil
+		}
+		// Los avisos programados (posts con fecha de publicación) conservan su fecha
+		now := time.Now()
+		for i := range report.DeferredEvents {
+			if report.DeferredEvents[i].NextAttemptAt.Before(now) {
+				report.DeferredEvents[i].NextAttemptAt = now
+			}
+			report.DeferredEvents[i].UpdatedAt = now
+		}
+		return store.Record(sessCtx, report.DeferredEvents...)
+	})
+}
+
+// resolveHeldComment publica el comentario retenido (lo agrega al post y a las respuestas de su
+// padre) o lo marca como borrado.
+func (r *ReportRepository) resolveHeldComment(ctx context.Context, commentID primitive.ObjectID, approve bool) error {
+	db := r.mongoClient.Database("NEXO-VECINAL")
+	comments := db.Collection("Comments")
+	now := time.Now()
+	if !approve {
+		_, err := comments.UpdateOne(ctx, bson.M{"_id": commentID}, bson.M{
+			"$set":   bson.M{"deleted": true, "deletedAt": now, "text": ""},
+			"$unset": bson.M{"mentions": "", "hashtags": ""},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to discard comment: %v", err)
+		}
+		return nil
+	}
+
+	var comment struct {
+		PostID   primitive.ObjectID  `bson:"postId"`
+		ParentID *primitive.ObjectID `bson:"parentId"`
+	}
+	err := comments.FindOneAndUpdate(ctx,
+		bson.M{"_id": commentID, "held": true},
+		bson.M{"$unset": bson.M{"held": ""}},
+	).Decode(&comment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.New("comment not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release comment: %v", err)
+	}
+	if comment.ParentID != nil {
+		if _, err := comments.UpdateByID(ctx, *comment.ParentID, bson.M{"$inc": bson.M{"replyCount": 1}}); err != nil {
+			return fmt.Errorf("failed to update parent comment: %v", err)
+		}
+	}
+	_, err = db.Collection("Posts").UpdateByID(ctx, comment.PostID, bson.M{
+		"$push": bson.M{"comments": commentID},
+		"$set":  bson.M{"updatedAt": now},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update post: %v", err)
 	}
 	return nil
 }
 
-// GetContentReports devuelve los documentos de reportes de contenido
-// Paginados según los parámetros: page (número de página) y pageSize (tamaño de página).
-func (r *ReportRepository) GetContentReports(ctx context.Context, page int) ([]admindomain.ContentReport, error) {
+// GetContentReports devuelve la cola de reportes de contenido filtrada por estado y tipo, con los
+// contenidos reportados por más usuarios distintos primero. Páginas de 10.
+func (r *ReportRepository) GetContentReports(ctx context.Context, filter admindomain.ContentReportFilter) ([]admindomain.ContentReport, error) {
 	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports")
 	pageSize := 10
-	// Calculamos cuántos documentos omitirs
-	skip := (page - 1) * pageSize
+	// Calculamos cuántos documentos omitir
+	skip := (filter.Page - 1) * pageSize
 
-	// Creamos las opciones de búsqueda: skip, limit y opcionalmente un orden (por ejemplo, los más recientes primero)
-	opts := options.Find().
-		SetSkip(int64(skip)).
-		SetLimit(int64(pageSize)).
-		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	match := bson.M{"status": unresolvedReports()}
+	switch filter.Status {
+	case "":
+	case admindomain.ReportOpen:
+		// Los reportes anteriores al flujo de moderación no tienen status
+		match["status"] = bson.M{"$in": bson.A{admindomain.ReportOpen, nil}}
+	default:
+		match["status"] = filter.Status
+	}
+	if filter.ContentType != "" {
+		match["contentType"] = filter.ContentType
+	}
 
-	// Buscamos los documentos sin filtro (podés agregar algún filtro si lo necesitas)
-	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{
+			"status": bson.M{"$ifNull": bson.A{"$status", admindomain.ReportOpen}},
+			// Usuarios distintos que reportaron; la moderación automática reporta con NilObjectID y no cuenta
+			"reporterCount": bson.M{"$size": bson.M{"$setDifference": bson.A{
+				bson.M{"$ifNull": bson.A{"$reports.reporterUserId", bson.A{}}},
+				bson.A{primitive.NilObjectID},
+			}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "reporterCount", Value: -1}, {Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$skip", Value: skip}},
+		{{Key: "$limit", Value: pageSize}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get content reports: %v", err)
 	}
 	defer cursor.Close(ctx)
 
-	var reports []admindomain.ContentReport
+	reports := []admindomain.ContentReport{}
 	if err = cursor.All(ctx, &reports); err != nil {
 		return nil, fmt.Errorf("failed to decode content reports: %v", err)
 	}
@@ -450,3 +647,13 @@ func (r *ReportRepository) DisableUserForWork(ctx context.Context, userId primit
 
 	return nil
 }
+
+// GetDeadLetterEvents devuelve los eventos del outbox que agotaron sus reintentos.
+func (r *ReportRepository) GetDeadLetterEvents(ctx context.Context, page int) ([]outbox.Event, error) {
+	return outbox.NewStore(r.mongoClient).DeadLetters(ctx, page)
+}
+
+// RetryDeadLetterEvent vuelve a encolar un evento muerto del outbox.
+func (r *ReportRepository) RetryDeadLetterEvent(ctx context.Context, eventID primitive.ObjectID) error {
+	return outbox.NewStore(r.mongoClient).Retry(ctx, eventID)
+}
@@ -0,0 +1,141 @@
+package admininfrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"back-end/internal/admin/admindomain"
+	"back-end/pkg/outbox"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *ReportRepository) auditCollection() *mongo.Collection {
+	return r.mongoClient.Database("NEXO-VECINAL").Collection("admin_audit_log")
+}
+
+// EnsureAuditIndexes crea los índices de las consultas del log de auditoría.
+func (r *ReportRepository) EnsureAuditIndexes(ctx context.Context) error {
+	_, err := r.auditCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "actorId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "targetId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+	})
+	return err
+}
+
+// WithTransaction ejecuta fn en una transacción; las escrituras hechas con sessCtx se confirman juntas.
+func (r *ReportRepository) WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	return outbox.NewStore(r.mongoClient).WithTransaction(ctx, fn)
+}
+
+// RecordAudit agrega una entrada al log de auditoría. El log es de solo escritura: el repositorio
+// no expone operaciones para modificar o borrar entradas.
+func (r *ReportRepository) RecordAudit(ctx context.Context, entry admindomain.AuditEntry) error {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+	if _, err := r.auditCollection().InsertOne(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %v", err)
+	}
+	return nil
+}
+
+// GetAuditLog devuelve las entradas que cumplen el filtro, las más recientes primero.
+func (r *ReportRepository) GetAuditLog(ctx context.Context, filter admindomain.AuditFilter) ([]admindomain.AuditEntry, error) {
+	query := bson.M{}
+	if filter.ActorID != nil {
+		query["actorId"] = *filter.ActorID
+	}
+	if filter.TargetID != "" {
+		query["targetId"] = filter.TargetID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	createdAt := bson.M{}
+	if filter.From != nil {
+		createdAt["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		createdAt["$lt"] = *filter.To
+	}
+	if len(createdAt) > 0 {
+		query["createdAt"] = createdAt
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+	cursor, err := r.auditCollection().Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []admindomain.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit log: %v", err)
+	}
+	return entries, nil
+}
+
+// Campos que se guardan en las instantáneas before/after de cada tipo de objetivo.
+var (
+	userSnapshotFields          = bson.M{"NameUser": 1, "Banned": 1, "availableToWork": 1}
+	jobSnapshotFields           = bson.M{"title": 1, "status": 1, "available": 1, "held": 1}
+	postSnapshotFields          = bson.M{"title": 1, "available": 1, "held": 1}
+	contentReportSnapshotFields = bson.M{"status": 1, "assignedTo": 1, "resolution": 1, "held": 1}
+	sanctionSnapshotFields      = bson.M{"userId": 1, "type": 1, "expiresAt": 1, "liftedAt": 1, "appeal.status": 1}
+)
+
+// Snapshot devuelve los campos auditados del objetivo, o nil si no existe.
+func (r *ReportRepository) Snapshot(ctx context.Context, targetType string, targetID primitive.ObjectID) (bson.M, error) {
+	var collection string
+	var fields bson.M
+	switch targetType {
+	case admindomain.AuditTargetUser:
+		collection, fields = "Users", userSnapshotFields
+	case admindomain.AuditTargetJob:
+		collection, fields = "Job", jobSnapshotFields
+	case admindomain.AuditTargetPost:
+		collection, fields = "Posts", postSnapshotFields
+	case admindomain.AuditTargetContentReport:
+		collection, fields = "content_reports", contentReportSnapshotFields
+	case admindomain.AuditTargetSanction:
+		collection, fields = "user_sanctions", sanctionSnapshotFields
+	default:
+		return nil, nil
+	}
+	var snapshot bson.M
+	err := r.mongoClient.Database("NEXO-VECINAL").Collection(collection).
+		FindOne(ctx, bson.M{"_id": targetID}, options.FindOne().SetProjection(fields)).
+		Decode(&snapshot)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot %s: %v", targetType, err)
+	}
+	delete(snapshot, "_id")
+	return snapshot, nil
+}
+
+// FindUserIDByNameUser resuelve el _id de un usuario por su NameUser exacto.
+func (r *ReportRepository) FindUserIDByNameUser(ctx context.Context, nameUser string) (primitive.ObjectID, error) {
+	var user struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err := r.mongoClient.Database("NEXO-VECINAL").Collection("Users").
+		FindOne(ctx, bson.M{"NameUser": nameUser}, options.FindOne().SetProjection(bson.M{"_id": 1})).
+		Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return primitive.NilObjectID, errors.New("user not found")
+	}
+	return user.ID, err
+}
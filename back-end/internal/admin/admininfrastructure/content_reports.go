@@ -0,0 +1,122 @@
+package admininfrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"back-end/internal/admin/admindomain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureContentReportIndexes crea los índices de la cola de reportes de contenido. El índice único sobre
+// open impide que dos reportes concurrentes abran dos documentos para el mismo contenido; antes marca
+// como abiertos los reportes sin cerrar anteriores al campo.
+func (r *ReportRepository) EnsureContentReportIndexes(ctx context.Context) error {
+	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports")
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"status": unresolvedReports(), "open": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"open": true}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark open content reports: %v", err)
+	}
+	_, err = collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "reportedContentId", Value: 1}, {Key: "contentType", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "contentType", Value: 1}, {Key: "updatedAt", Value: -1}}},
+		{
+			Keys: bson.D{{Key: "contentType", Value: 1}, {Key: "reportedContentId", Value: 1}},
+			Options: options.Index().
+				SetName("content_report_open_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"open": true}),
+		},
+	})
+	return err
+}
+
+// GetContentReport devuelve un reporte de contenido con sus notas y resolución.
+func (r *ReportRepository) GetContentReport(ctx context.Context, reportID primitive.ObjectID) (*admindomain.ContentReport, error) {
+	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports")
+	var report admindomain.ContentReport
+	err := collection.FindOne(ctx, bson.M{"_id": reportID}).Decode(&report)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.New("report not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content report: %v", err)
+	}
+	if report.Status == "" {
+		report.Status = admindomain.ReportOpen
+	}
+	reporters := map[primitive.ObjectID]bool{}
+	for _, detail := range report.Reports {
+		reporters[detail.ReporterUserID] = true
+	}
+	report.ReporterCount = len(reporters)
+	return &report, nil
+}
+
+// AssignContentReport asigna un reporte sin cerrar a un moderador y lo pasa a "in_review".
+func (r *ReportRepository) AssignContentReport(ctx context.Context, reportID, assigneeID primitive.ObjectID) error {
+	db := r.mongoClient.Database("NEXO-VECINAL")
+	count, err := db.Collection("Users").CountDocuments(ctx, bson.M{"_id": assigneeID, "PanelAdminNexoVecinal.Asset": true})
+	if err != nil {
+		return fmt.Errorf("failed to check moderator: %v", err)
+	}
+	if count == 0 {
+		return errors.New("el usuario asignado no es moderador")
+	}
+
+	now := time.Now()
+	res, err := db.Collection("content_reports").UpdateOne(ctx,
+		bson.M{"_id": reportID, "status": unresolvedReports()},
+		bson.M{"$set": bson.M{
+			"status":     admindomain.ReportInReview,
+			"assignedTo": assigneeID,
+			"assignedAt": now,
+			"updatedAt":  now,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to assign content report: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("reporte no encontrado o ya cerrado")
+	}
+	return nil
+}
+
+// AddContentReportNote agrega una nota interna de adminID al reporte. Se puede anotar también un
+// reporte cerrado, por ejemplo para dejar contexto de una apelación.
+func (r *ReportRepository) AddContentReportNote(ctx context.Context, reportID, adminID primitive.ObjectID, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("la nota está vacía")
+	}
+	if len(text) > 1000 {
+		return errors.New("la nota excede los 1000 caracteres")
+	}
+	now := time.Now()
+	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports")
+	res, err := collection.UpdateOne(ctx,
+		bson.M{"_id": reportID},
+		bson.M{
+			"$push": bson.M{"notes": admindomain.ReportNote{AuthorID: adminID, Text: text, CreatedAt: now}},
+			"$set":  bson.M{"updatedAt": now},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add note: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("report not found")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package admininfrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"back-end/pkg/sanctions"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EnsureSanctionIndexes crea los índices de la colección de sanciones.
+func (r *ReportRepository) EnsureSanctionIndexes(ctx context.Context) error {
+	return r.sanctions.EnsureIndexes(ctx)
+}
+
+// MigrateLegacyBans convierte los Users.Banned anteriores a las sanciones en baneos.
+func (r *ReportRepository) MigrateLegacyBans(ctx context.Context) error {
+	return r.sanctions.MigrateLegacyBans(ctx)
+}
+
+// IssueSanction guarda una sanción armada con sanctions.New, si el usuario sancionado existe.
+func (r *ReportRepository) IssueSanction(ctx context.Context, sanction sanctions.Sanction) error {
+	count, err := r.mongoClient.Database("NEXO-VECINAL").Collection("Users").CountDocuments(ctx, bson.M{"_id": sanction.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to check user: %v", err)
+	}
+	if count == 0 {
+		return errors.New("user not found")
+	}
+	return r.sanctions.Issue(ctx, sanction)
+}
+
+// LiftSanction levanta una sanción vigente; si tenía una apelación pendiente, queda aceptada.
+func (r *ReportRepository) LiftSanction(ctx context.Context, sanctionID, adminID primitive.ObjectID, note string) error {
+	return r.sanctions.Lift(ctx, sanctionID, adminID, note)
+}
+
+// RejectSanctionAppeal rechaza la apelación pendiente de una sanción.
+func (r *ReportRepository) RejectSanctionAppeal(ctx context.Context, sanctionID primitive.ObjectID) error {
+	return r.sanctions.RejectAppeal(ctx, sanctionID)
+}
+
+// GetUserSanctions devuelve el historial de sanciones de un usuario, las más recientes primero.
+func (r *ReportRepository) GetUserSanctions(ctx context.Context, userID primitive.ObjectID) ([]sanctions.Sanction, error) {
+	return r.sanctions.History(ctx, userID)
+}
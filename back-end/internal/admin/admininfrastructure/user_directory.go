@@ -0,0 +1,425 @@
+package admininfrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"back-end/internal/admin/admindomain"
+	userdomain "back-end/internal/user/user-domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Campos de Users que se leen para el directorio; nunca se leen contraseñas ni secretos.
+var userDirectoryFields = bson.M{
+	"NameUser": 1, "FullName": 1, "Email": 1, "Avatar": 1, "Pais": 1, "Ciudad": 1, "Intentions": 1,
+	"Banned": 1, "Premium": 1, "Soporte": 1, "availableToWork": 1, "completedJobs": 1, "ratio": 1,
+	"Timestamp": 1, "LastConnection": 1,
+}
+
+// Cantidad de trabajos y reportes recientes que se muestran en el detalle de un usuario.
+const userDetailRecentLimit = 10
+
+// EnsureUserDirectoryIndexes crea los índices de los filtros y órdenes más usados del directorio y
+// del historial de premium del detalle.
+func (r *ReportRepository) EnsureUserDirectoryIndexes(ctx context.Context) error {
+	db := r.mongoClient.Database("NEXO-VECINAL")
+	_, err := db.Collection("Users").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "Timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "Pais", Value: 1}, {Key: "Ciudad", Value: 1}}},
+		{Keys: bson.D{{Key: "Banned", Value: 1}, {Key: "Timestamp", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection("premium_history").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+	})
+	return err
+}
+
+// BackfillPremiumHistory crea una entrada de historial con el período guardado en Premium para los
+// usuarios premium que todavía no tienen historial. Es idempotente: solo mira usuarios sin entradas.
+func (r *ReportRepository) BackfillPremiumHistory(ctx context.Context) (int, error) {
+	db := r.mongoClient.Database("NEXO-VECINAL")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"Premium.SubscriptionEnd": bson.M{"$gt": time.Time{}}}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "premium_history"},
+			{Key: "let", Value: bson.D{{Key: "userId", Value: "$_id"}}},
+			{Key: "pipeline", Value: mongo.Pipeline{
+				{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$userId", "$$userId"}}}}}}},
+				{{Key: "$limit", Value: 1}},
+				{{Key: "$project", Value: bson.D{{Key: "_id", Value: 1}}}},
+			}},
+			{Key: "as", Value: "history"},
+		}}},
+		{{Key: "$match", Value: bson.M{"history": bson.M{"$size": 0}}}},
+		{{Key: "$project", Value: bson.M{"Premium": 1}}},
+	}
+	cursor, err := db.Collection("Users").Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []interface{}
+	for cursor.Next(ctx) {
+		var user struct {
+			ID      primitive.ObjectID `bson:"_id"`
+			Premium userdomain.Premium `bson:"Premium"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return 0, err
+		}
+		createdAt := user.Premium.SubscriptionStart
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		entries = append(entries, userdomain.PremiumHistoryEntry{
+			UserID:            user.ID,
+			Event:             userdomain.PremiumEventBackfill,
+			SubscriptionStart: user.Premium.SubscriptionStart,
+			SubscriptionEnd:   user.Premium.SubscriptionEnd,
+			MonthsSubscribed:  user.Premium.MonthsSubscribed,
+			CreatedAt:         createdAt,
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	if _, err := db.Collection("premium_history").InsertMany(ctx, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// exactInsensitive compara un campo de texto completo sin distinguir mayúsculas.
+func exactInsensitive(value string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value) + "$", Options: "i"}
+}
+
+// userDirectoryQuery traduce el filtro del directorio a una consulta sobre Users.
+func userDirectoryQuery(filter admindomain.UserDirectoryFilter, now time.Time) bson.M {
+	query := bson.M{}
+	if filter.Query != "" {
+		contains := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Query), Options: "i"}
+		query["$or"] = bson.A{
+			bson.M{"NameUser": contains},
+			bson.M{"FullName": contains},
+			bson.M{"Email": contains},
+		}
+	}
+	// Users.Banned refleja los baneos vigentes: lo mantiene sanctions.Store al emitir y levantar baneos,
+	// y MigrateLegacyBans convierte los baneos anteriores a las sanciones
+	if filter.Banned != nil {
+		if *filter.Banned {
+			query["Banned"] = true
+		} else {
+			query["Banned"] = bson.M{"$ne": true}
+		}
+	}
+	if filter.Premium != nil {
+		if *filter.Premium {
+			query["Premium.SubscriptionEnd"] = bson.M{"$gt": now}
+		} else {
+			query["Premium.SubscriptionEnd"] = bson.M{"$not": bson.M{"$gt": now}}
+		}
+	}
+	if filter.Support != nil {
+		if *filter.Support {
+			query["Soporte"] = "activo"
+		} else {
+			query["Soporte"] = bson.M{"$ne": "activo"}
+		}
+	}
+	if filter.AvailableToWork != nil {
+		if *filter.AvailableToWork {
+			query["availableToWork"] = true
+		} else {
+			query["availableToWork"] = bson.M{"$ne": true}
+		}
+	}
+	if filter.Intentions != "" {
+		query["Intentions"] = filter.Intentions
+	}
+	if filter.Country != "" {
+		query["Pais"] = exactInsensitive(filter.Country)
+	}
+	if filter.City != "" {
+		query["Ciudad"] = exactInsensitive(filter.City)
+	}
+	registered := bson.M{}
+	if filter.RegisteredFrom != nil {
+		registered["$gte"] = *filter.RegisteredFrom
+	}
+	if filter.RegisteredTo != nil {
+		registered["$lt"] = *filter.RegisteredTo
+	}
+	if len(registered) > 0 {
+		query["Timestamp"] = registered
+	}
+	return query
+}
+
+func userDirectorySort(filter admindomain.UserDirectoryFilter) bson.D {
+	order := -1
+	if filter.Ascending {
+		order = 1
+	}
+	return bson.D{{Key: filter.Sort.Field(), Value: order}, {Key: "_id", Value: order}}
+}
+
+// SearchUsers devuelve una página del directorio de usuarios y el total que cumple el filtro.
+func (r *ReportRepository) SearchUsers(ctx context.Context, filter admindomain.UserDirectoryFilter) (admindomain.UserDirectoryPage, error) {
+	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	now := time.Now()
+	query := userDirectoryQuery(filter, now)
+
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return admindomain.UserDirectoryPage{}, fmt.Errorf("failed to count users: %v", err)
+	}
+	opts := options.Find().
+		SetProjection(userDirectoryFields).
+		SetSort(userDirectorySort(filter)).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return admindomain.UserDirectoryPage{}, fmt.Errorf("failed to search users: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	users := []admindomain.UserDirectoryEntry{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return admindomain.UserDirectoryPage{}, fmt.Errorf("failed to decode users: %v", err)
+	}
+	for i := range users {
+		users[i].IsPremium = users[i].Premium.SubscriptionEnd.After(now)
+	}
+	return admindomain.UserDirectoryPage{Users: users, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
+}
+
+// EachUser recorre, en el orden del filtro y sin paginar, hasta limit usuarios que cumplen el filtro.
+// Se usa para la exportación CSV sin cargar todo el directorio en memoria.
+func (r *ReportRepository) EachUser(ctx context.Context, filter admindomain.UserDirectoryFilter, limit int, fn func(admindomain.UserDirectoryEntry) error) error {
+	now := time.Now()
+	opts := options.Find().
+		SetProjection(userDirectoryFields).
+		SetSort(userDirectorySort(filter)).
+		SetLimit(int64(limit))
+	cursor, err := r.mongoClient.Database("NEXO-VECINAL").Collection("Users").Find(ctx, userDirectoryQuery(filter, now), opts)
+	if err != nil {
+		return fmt.Errorf("failed to export users: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user admindomain.UserDirectoryEntry
+		if err := cursor.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %v", err)
+		}
+		user.IsPremium = user.Premium.SubscriptionEnd.After(now)
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// GetUserDetail arma la vista de un usuario con sus trabajos, reportes, sanciones, calificaciones
+// e historial de premium.
+func (r *ReportRepository) GetUserDetail(ctx context.Context, userID primitive.ObjectID) (*admindomain.UserDetail, error) {
+	db := r.mongoClient.Database("NEXO-VECINAL")
+	detail := &admindomain.UserDetail{}
+	err := db.Collection("Users").
+		FindOne(ctx, bson.M{"_id": userID}, options.FindOne().SetProjection(userDirectoryFields)).
+		Decode(&detail.User)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.New("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %v", err)
+	}
+	detail.User.IsPremium = detail.User.Premium.SubscriptionEnd.After(time.Now())
+
+	if detail.Jobs, err = r.userJobsActivity(ctx, userID); err != nil {
+		return nil, err
+	}
+	if detail.Reports, err = r.userReportsActivity(ctx, userID); err != nil {
+		return nil, err
+	}
+	if detail.Sanctions, err = r.sanctions.History(ctx, userID); err != nil {
+		return nil, err
+	}
+	// Como trabajador califica el empleador y como empleador, el trabajador
+	if detail.WorkerRating, err = r.userRating(ctx, workerJobsFilter(userID), "employerFeedback"); err != nil {
+		return nil, err
+	}
+	if detail.EmployerRating, err = r.userRating(ctx, bson.M{"userId": userID}, "workerFeedback"); err != nil {
+		return nil, err
+	}
+	if detail.PremiumHistory, err = r.userPremiumHistory(ctx, userID); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// workerJobsFilter filtra los trabajos realizados por userID: los asignados tras postularse y las
+// solicitudes directas.
+func workerJobsFilter(userID primitive.ObjectID) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"assignedApplication.applicantId": userID},
+		bson.M{"workerId": userID},
+	}}
+}
+
+func (r *ReportRepository) countJobsByStatus(ctx context.Context, match bson.M) (map[string]int, error) {
+	cursor, err := r.mongoClient.Database("NEXO-VECINAL").Collection("Job").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status string `bson:"_id"`
+		Count  int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode job counts: %v", err)
+	}
+	counts := map[string]int{}
+	for _, group := range groups {
+		counts[group.Status] = group.Count
+	}
+	return counts, nil
+}
+
+func (r *ReportRepository) userJobsActivity(ctx context.Context, userID primitive.ObjectID) (admindomain.UserJobsActivity, error) {
+	var activity admindomain.UserJobsActivity
+	var err error
+	if activity.PostedByStatus, err = r.countJobsByStatus(ctx, bson.M{"userId": userID}); err != nil {
+		return activity, err
+	}
+	if activity.WorkedByStatus, err = r.countJobsByStatus(ctx, workerJobsFilter(userID)); err != nil {
+		return activity, err
+	}
+
+	match := workerJobsFilter(userID)
+	match["$or"] = append(match["$or"].(bson.A), bson.M{"userId": userID})
+	cursor, err := r.mongoClient.Database("NEXO-VECINAL").Collection("Job").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}}}},
+		{{Key: "$limit", Value: userDetailRecentLimit}},
+		{{Key: "$project", Value: bson.M{
+			"title":     1,
+			"status":    1,
+			"createdAt": 1,
+			"role":      bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$userId", userID}}, "employer", "worker"}},
+		}}},
+	})
+	if err != nil {
+		return activity, fmt.Errorf("failed to get recent jobs: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	activity.Recent = []admindomain.UserJobSummary{}
+	if err := cursor.All(ctx, &activity.Recent); err != nil {
+		return activity, fmt.Errorf("failed to decode recent jobs: %v", err)
+	}
+	return activity, nil
+}
+
+// recentUserReports devuelve los últimos reportes de usuario que cumplen el filtro y cuántos hay.
+func (r *ReportRepository) recentUserReports(ctx context.Context, filter bson.M) ([]admindomain.UserReport, int64, error) {
+	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("user_reports")
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count user reports: %v", err)
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(userDetailRecentLimit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get user reports: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	reports := []admindomain.UserReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode user reports: %v", err)
+	}
+	return reports, count, nil
+}
+
+func (r *ReportRepository) userReportsActivity(ctx context.Context, userID primitive.ObjectID) (admindomain.UserReportsActivity, error) {
+	var activity admindomain.UserReportsActivity
+	var err error
+	if activity.Received, activity.ReceivedCount, err = r.recentUserReports(ctx, bson.M{"reportedUserId": userID}); err != nil {
+		return activity, err
+	}
+	if activity.Filed, activity.FiledCount, err = r.recentUserReports(ctx, bson.M{"reporterUserId": userID}); err != nil {
+		return activity, err
+	}
+	activity.ContentFiledCount, err = r.mongoClient.Database("NEXO-VECINAL").Collection("content_reports").
+		CountDocuments(ctx, bson.M{"reports.reporterUserId": userID})
+	if err != nil {
+		return activity, fmt.Errorf("failed to count content reports: %v", err)
+	}
+	return activity, nil
+}
+
+// userRating promedia la calificación de feedbackField en los trabajos del filtro que la tienen.
+func (r *ReportRepository) userRating(ctx context.Context, match bson.M, feedbackField string) (admindomain.RatingSummary, error) {
+	match[feedbackField+".rating"] = bson.M{"$gt": 0}
+	cursor, err := r.mongoClient.Database("NEXO-VECINAL").Collection("Job").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"average": bson.M{"$avg": "$" + feedbackField + ".rating"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return admindomain.RatingSummary{}, fmt.Errorf("failed to get ratings: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var summary admindomain.RatingSummary
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&summary); err != nil {
+			return summary, fmt.Errorf("failed to decode ratings: %v", err)
+		}
+		summary.Average = math.Round(summary.Average*10) / 10
+	}
+	return summary, cursor.Err()
+}
+
+func (r *ReportRepository) userPremiumHistory(ctx context.Context, userID primitive.ObjectID) ([]userdomain.PremiumHistoryEntry, error) {
+	cursor, err := r.mongoClient.Database("NEXO-VECINAL").Collection("premium_history").
+		Find(ctx, bson.M{"userId": userID}, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get premium history: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	history := []userdomain.PremiumHistoryEntry{}
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, fmt.Errorf("failed to decode premium history: %v", err)
+	}
+	return history, nil
+}
@@ -58,6 +58,9 @@ func (h *ReportHandler) CreateOrUpdateContentReport(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err})
 	}
+	if _, err := admindomain.ParseReportReason(req.Reason); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
 	err = h.ReportService.CreateOrUpdateContentReport(req, userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -125,7 +128,7 @@ func (h *ReportHandler) BlockUser(c *fiber.Ctx) error {
 	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
 	}
-	if err := h.ReportService.BlockUser(context.Background(), req.UserID); err != nil {
+	if err := h.ReportService.BlockUser(context.Background(), auditActor(c, idValue), req.UserID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"status": "usuario bloqueado"})
@@ -143,7 +146,7 @@ func (h *ReportHandler) UnblockUser(c *fiber.Ctx) error {
 	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
 	}
-	if err := h.ReportService.UnblockUser(context.Background(), req.UserID); err != nil {
+	if err := h.ReportService.UnblockUser(context.Background(), auditActor(c, idValue), req.UserID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"status": "usuario desbloqueado"})
@@ -158,24 +161,35 @@ func (h *ReportHandler) GetAllTagsHandler(c *fiber.Ctx) error {
 	return c.JSON(tags)
 }
 
+// AddTagHandler agrega un tag (requiere autorización de admin).
 func (h *ReportHandler) AddTagHandler(c *fiber.Ctx) error {
 	var data struct {
-		Tag string `json:"tag"`
+		Tag       string `json:"tag"`
+		AdminCode string `json:"AdminCode"`
 	}
 	if err := c.BodyParser(&data); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
 	}
 	ctx := context.Background()
-	if err := h.ReportService.AddTag(ctx, data.Tag); err != nil {
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(ctx, idValue, data.AdminCode); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	if err := h.ReportService.AddTag(ctx, auditActor(c, idValue), data.Tag); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"message": "Tag added successfully"})
 }
 
+// RemoveTagHandler elimina un tag (requiere autorización de admin, header X-Admin-Code).
 func (h *ReportHandler) RemoveTagHandler(c *fiber.Ctx) error {
 	tag := c.Params("tag")
 	ctx := context.Background()
-	if err := h.ReportService.RemoveTag(ctx, tag); err != nil {
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(ctx, idValue, adminCode(c)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	if err := h.ReportService.RemoveTag(ctx, auditActor(c, idValue), tag); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"message": "Tag removed successfully"})
@@ -184,6 +198,7 @@ func (h *ReportHandler) DeleteJob(c *fiber.Ctx) error {
 	type request struct {
 		JobId     string `json:"JobId"`
 		AdminCode string `json:"AdminCode"`
+		Note      string `json:"Note"` // Motivo de la baja, queda en la resolución de los reportes
 	}
 	var req request
 	if err := c.BodyParser(&req); err != nil {
@@ -193,7 +208,7 @@ func (h *ReportHandler) DeleteJob(c *fiber.Ctx) error {
 	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
 	}
-	if err := h.ReportService.DeleteJob(context.Background(), req.JobId); err != nil {
+	if err := h.ReportService.DeleteJob(context.Background(), auditActor(c, idValue), req.JobId, req.Note); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"status": "job delete"})
@@ -202,6 +217,7 @@ func (h *ReportHandler) DeletePost(c *fiber.Ctx) error {
 	type request struct {
 		PostId    primitive.ObjectID `json:"PostId"`
 		AdminCode string             `json:"AdminCode"`
+		Note      string             `json:"Note"` // Motivo de la baja, queda en la resolución de los reportes
 	}
 	var req request
 	if err := c.BodyParser(&req); err != nil {
@@ -211,15 +227,19 @@ func (h *ReportHandler) DeletePost(c *fiber.Ctx) error {
 	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
 	}
-	if err := h.ReportService.DeletePost(context.Background(), req.PostId); err != nil {
+	if err := h.ReportService.DeletePost(context.Background(), auditActor(c, idValue), req.PostId, req.Note); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"status": "job delete"})
 }
+
+// DeleteContentReport descarta un reporte de contenido: queda cerrado como "dismissed" con el
+// admin que lo decidió, no se borra.
 func (h *ReportHandler) DeleteContentReport(c *fiber.Ctx) error {
 	type request struct {
 		IdReport  primitive.ObjectID `json:"IdReport"`
 		AdminCode string             `json:"AdminCode"`
+		Note      string             `json:"Note"`
 	}
 	var req request
 	if err := c.BodyParser(&req); err != nil {
@@ -229,20 +249,125 @@ func (h *ReportHandler) DeleteContentReport(c *fiber.Ctx) error {
 	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
 	}
-	if err := h.ReportService.DeleteContentReport(context.Background(), req.IdReport); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	if err := h.ReportService.DismissContentReport(context.Background(), auditActor(c, idValue), req.IdReport, req.Note); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
-	return c.JSON(fiber.Map{"status": "ContentReport delete"})
+	return c.JSON(fiber.Map{"status": "ContentReport dismissed"})
+}
+
+// GetContentReport devuelve un reporte de contenido con sus notas y resolución.
+func (h *ReportHandler) GetContentReport(c *fiber.Ctx) error {
+	reportID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "id de reporte inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, adminCode(c)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	report, err := h.ReportService.GetContentReport(context.Background(), reportID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "StatusOK", "data": report})
+}
+
+// AssignContentReport asigna un reporte a un moderador (por defecto, al propio admin) y lo pasa a "in_review".
+func (h *ReportHandler) AssignContentReport(c *fiber.Ctx) error {
+	type request struct {
+		IdReport   primitive.ObjectID  `json:"IdReport"`
+		AssigneeId *primitive.ObjectID `json:"AssigneeId,omitempty"`
+		AdminCode  string              `json:"AdminCode"`
+	}
+	var req request
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	actor := auditActor(c, idValue)
+	assigneeID := actor.ID
+	if req.AssigneeId != nil {
+		assigneeID = *req.AssigneeId
+	}
+	if err := h.ReportService.AssignContentReport(context.Background(), actor, req.IdReport, assigneeID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"status": "ContentReport assigned"})
+}
+
+// AddContentReportNote agrega una nota interna del admin a un reporte.
+func (h *ReportHandler) AddContentReportNote(c *fiber.Ctx) error {
+	type request struct {
+		IdReport  primitive.ObjectID `json:"IdReport"`
+		Text      string             `json:"Text"`
+		AdminCode string             `json:"AdminCode"`
+	}
+	var req request
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	if err := h.ReportService.AddContentReportNote(context.Background(), auditActor(c, idValue), req.IdReport, req.Text); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"status": "nota agregada"})
 }
 
+// ReleaseHeldContent publica el contenido retenido por la moderación automática.
+func (h *ReportHandler) ReleaseHeldContent(c *fiber.Ctx) error {
+	return h.resolveHeldContent(c, true)
+}
+
+// DiscardHeldContent descarta el contenido retenido por la moderación automática.
+func (h *ReportHandler) DiscardHeldContent(c *fiber.Ctx) error {
+	return h.resolveHeldContent(c, false)
+}
+
+func (h *ReportHandler) resolveHeldContent(c *fiber.Ctx, approve bool) error {
+	type request struct {
+		IdReport  primitive.ObjectID `json:"IdReport"`
+		AdminCode string             `json:"AdminCode"`
+		Note      string             `json:"Note"`
+	}
+	var req request
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	if err := h.ReportService.ResolveHeldContent(context.Background(), auditActor(c, idValue), req.IdReport, approve, req.Note); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if approve {
+		return c.JSON(fiber.Map{"status": "contenido publicado"})
+	}
+	return c.JSON(fiber.Map{"status": "contenido descartado"})
+}
+
+// GetContentReports lista la cola de reportes de contenido. Query params: page, status
+// (open, in_review, actioned, dismissed; por defecto los sin cerrar) y type (post, job, comment).
 func (h *ReportHandler) GetContentReports(c *fiber.Ctx) error {
 	pageStr := c.Query("page", "1")
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
 		page = 1
 	}
+	filter := admindomain.ContentReportFilter{Page: page, ContentType: c.Query("type")}
+	if status := c.Query("status"); status != "" {
+		if filter.Status, err = admindomain.ParseReportStatus(status); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
 	ctx := context.Background()
-	reports, err := h.ReportService.GetContentReports(ctx, page)
+	reports, err := h.ReportService.GetContentReports(ctx, filter)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -280,7 +405,7 @@ func (h *ReportHandler) DisableUserForWork(c *fiber.Ctx) error {
 	}
 	ctx := context.Background()
 
-	if err := h.ReportService.DisableUserForWork(ctx, req.IdUser); err != nil {
+	if err := h.ReportService.DisableUserForWork(ctx, auditActor(c, idValue), req.IdUser); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"status": "ContentReport delete"})
@@ -300,8 +425,48 @@ func (h *ReportHandler) EnableUserForWork(c *fiber.Ctx) error {
 	}
 	ctx := context.Background()
 
-	if err := h.ReportService.EnableUserForWork(ctx, req.IdUser); err != nil {
+	if err := h.ReportService.EnableUserForWork(ctx, auditActor(c, idValue), req.IdUser); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.JSON(fiber.Map{"status": "ContentReport delete"})
 }
+
+// GetDeadLetterEvents lista los eventos del outbox que no se pudieron entregar (requiere autorización de admin).
+func (h *ReportHandler) GetDeadLetterEvents(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, adminCode(c)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	events, err := h.ReportService.GetDeadLetterEvents(context.Background(), page)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"data":    events,
+	})
+}
+
+// RetryDeadLetterEvent reencola un evento del outbox (requiere autorización de admin).
+func (h *ReportHandler) RetryDeadLetterEvent(c *fiber.Ctx) error {
+	type request struct {
+		EventID   primitive.ObjectID `json:"eventId"`
+		AdminCode string             `json:"code"`
+	}
+	var req request
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	if err := h.ReportService.RetryDeadLetterEvent(context.Background(), auditActor(c, idValue), req.EventID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"status": "evento reencolado"})
+}
@@ -0,0 +1,93 @@
+package admininterfaces
+
+import (
+	"back-end/internal/admin/admindomain"
+	userdomain "back-end/internal/user/user-domain"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// auditActor arma el actor de auditoría con el admin autenticado y la IP de la petición.
+func auditActor(c *fiber.Ctx, idValue string) admindomain.AuditActor {
+	adminID, _ := primitive.ObjectIDFromHex(idValue) // Ya validado por CheckAdminAuthorization o el extractor
+	return admindomain.AuditActor{ID: adminID, IP: c.IP()}
+}
+
+// adminCodeHeader es el header con el código de admin en las peticiones GET; no se usa un query param
+// para que el código no quede en los logs de acceso.
+const adminCodeHeader = "X-Admin-Code"
+
+// adminCode devuelve el código de admin enviado en adminCodeHeader.
+func adminCode(c *fiber.Ctx) string {
+	return c.Get(adminCodeHeader)
+}
+
+// parseAuditTime acepta fechas RFC3339 o YYYY-MM-DD (inicio del día en UTC).
+func parseAuditTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", value); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+// GetAuditLog consulta el log de auditoría (requiere autorización de admin).
+// Header X-Admin-Code. Query params: actorId, targetId, action, from y to (RFC3339 o YYYY-MM-DD; to es exclusivo), page y limit.
+func (h *ReportHandler) GetAuditLog(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, adminCode(c)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+
+	filter := admindomain.AuditFilter{
+		TargetID: c.Query("targetId"),
+		Action:   admindomain.AuditAction(c.Query("action")),
+	}
+	if actor := c.Query("actorId"); actor != "" {
+		actorID, err := primitive.ObjectIDFromHex(actor)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "actorId inválido"})
+		}
+		filter.ActorID = &actorID
+	}
+	var err error
+	if filter.From, err = parseAuditTime(c.Query("from")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from inválido"})
+	}
+	if filter.To, err = parseAuditTime(c.Query("to")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "to inválido"})
+	}
+	filter.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	filter.Limit, _ = strconv.Atoi(c.Query("limit", "50"))
+
+	entries, err := h.ReportService.GetAuditLog(context.Background(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "StatusOK", "data": entries})
+}
+
+// ChangeNameUser cambia el NameUser de un usuario (requiere el código de un admin de nivel 1).
+func (h *ReportHandler) ChangeNameUser(c *fiber.Ctx) error {
+	var req userdomain.ChangeNameUser
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.Code); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	if err := h.ReportService.ChangeNameUser(context.Background(), auditActor(c, idValue), req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"status": "NameUser actualizado"})
+}
@@ -0,0 +1,99 @@
+package admininterfaces
+
+import (
+	"context"
+	"time"
+
+	"back-end/pkg/sanctions"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IssueSanction sanciona a un usuario (requiere autorización de admin).
+// Type: warning, posting_restriction, chat_restriction, suspension o ban. DurationHours se ignora en
+// los baneos; en las advertencias es opcional (por defecto vencen a los 30 días).
+func (h *ReportHandler) IssueSanction(c *fiber.Ctx) error {
+	type request struct {
+		UserId        primitive.ObjectID `json:"UserId"`
+		Type          string             `json:"Type"`
+		Reason        string             `json:"Reason"`
+		DurationHours int                `json:"DurationHours"`
+		AdminCode     string             `json:"AdminCode"`
+	}
+	var req request
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	sanctionType, err := sanctions.ParseType(req.Type)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	duration := time.Duration(req.DurationHours) * time.Hour
+	sanction, err := h.ReportService.IssueSanction(context.Background(), auditActor(c, idValue), req.UserId, sanctionType, req.Reason, duration)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "sanción aplicada", "data": sanction})
+}
+
+type sanctionRequest struct {
+	SanctionId primitive.ObjectID `json:"SanctionId"`
+	Note       string             `json:"Note"`
+	AdminCode  string             `json:"AdminCode"`
+}
+
+// LiftSanction levanta una sanción vigente (requiere autorización de admin). Una apelación pendiente
+// queda aceptada.
+func (h *ReportHandler) LiftSanction(c *fiber.Ctx) error {
+	var req sanctionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	if err := h.ReportService.LiftSanction(context.Background(), auditActor(c, idValue), req.SanctionId, req.Note); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"status": "sanción levantada"})
+}
+
+// RejectSanctionAppeal rechaza la apelación pendiente de una sanción (requiere autorización de admin).
+func (h *ReportHandler) RejectSanctionAppeal(c *fiber.Ctx) error {
+	var req sanctionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, req.AdminCode); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	if err := h.ReportService.RejectSanctionAppeal(context.Background(), auditActor(c, idValue), req.SanctionId, req.Note); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"status": "apelación rechazada"})
+}
+
+// GetUserSanctions devuelve el historial de sanciones de un usuario (requiere autorización de admin).
+// Header X-Admin-Code. Query params: userId.
+func (h *ReportHandler) GetUserSanctions(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, adminCode(c)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Query("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "userId inválido"})
+	}
+	history, err := h.ReportService.GetUserSanctions(context.Background(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "StatusOK", "data": history})
+}
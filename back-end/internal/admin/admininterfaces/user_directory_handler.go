@@ -0,0 +1,125 @@
+package admininterfaces
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"back-end/internal/admin/admindomain"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// parseBoolQuery lee un filtro booleano opcional; vacío no filtra.
+func parseBoolQuery(c *fiber.Ctx, name string) (*bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s inválido", name)
+	}
+	return &b, nil
+}
+
+// userDirectoryFilter arma el filtro del directorio con los query params: q, banned, premium, support,
+// availableToWork, intentions, country, city, registeredFrom y registeredTo (RFC3339 o YYYY-MM-DD;
+// registeredTo es exclusivo), sort (registered, nameUser, lastConnection, completedJobs o rating),
+// order (asc o desc), page y limit.
+func userDirectoryFilter(c *fiber.Ctx) (admindomain.UserDirectoryFilter, error) {
+	filter := admindomain.UserDirectoryFilter{
+		Query:      c.Query("q"),
+		Intentions: c.Query("intentions"),
+		Country:    c.Query("country"),
+		City:       c.Query("city"),
+		Ascending:  c.Query("order") == "asc",
+	}
+	var err error
+	if filter.Banned, err = parseBoolQuery(c, "banned"); err != nil {
+		return filter, err
+	}
+	if filter.Premium, err = parseBoolQuery(c, "premium"); err != nil {
+		return filter, err
+	}
+	if filter.Support, err = parseBoolQuery(c, "support"); err != nil {
+		return filter, err
+	}
+	if filter.AvailableToWork, err = parseBoolQuery(c, "availableToWork"); err != nil {
+		return filter, err
+	}
+	if filter.RegisteredFrom, err = parseAuditTime(c.Query("registeredFrom")); err != nil {
+		return filter, fmt.Errorf("registeredFrom inválido")
+	}
+	if filter.RegisteredTo, err = parseAuditTime(c.Query("registeredTo")); err != nil {
+		return filter, fmt.Errorf("registeredTo inválido")
+	}
+	if filter.Sort, err = admindomain.ParseUserSort(c.Query("sort")); err != nil {
+		return filter, err
+	}
+	filter.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	filter.Limit, _ = strconv.Atoi(c.Query("limit", "50"))
+	return filter, nil
+}
+
+// SearchUsers lista el directorio de usuarios con filtros, orden y paginación (requiere autorización
+// de admin con el header X-Admin-Code). Ver userDirectoryFilter para el resto de los query params.
+func (h *ReportHandler) SearchUsers(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, adminCode(c)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	filter, err := userDirectoryFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	page, err := h.ReportService.SearchUsers(context.Background(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "StatusOK", "data": page})
+}
+
+// ExportUsersCSV descarga como CSV los usuarios que cumplen los filtros del directorio, hasta 10000
+// filas y sin paginar (requiere autorización de admin con el header X-Admin-Code).
+func (h *ReportHandler) ExportUsersCSV(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, adminCode(c)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	filter, err := userDirectoryFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	var buf bytes.Buffer
+	if err := h.ReportService.ExportUsersCSV(context.Background(), auditActor(c, idValue), filter, &buf); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="users-%s.csv"`, time.Now().Format("20060102-150405")))
+	return c.Send(buf.Bytes())
+}
+
+// GetUserDetail devuelve la vista de un usuario con sus trabajos, reportes recibidos y hechos,
+// sanciones, calificaciones e historial de premium (requiere autorización de admin con el header X-Admin-Code).
+func (h *ReportHandler) GetUserDetail(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	if err := h.ReportService.CheckAdminAuthorization(context.Background(), idValue, adminCode(c)); err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "No autorizado: " + err.Error()})
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "id de usuario inválido"})
+	}
+	detail, err := h.ReportService.GetUserDetail(context.Background(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "StatusOK", "data": detail})
+}
@@ -1,9 +1,13 @@
 package adminroutes
 
 import (
+	"context"
+	"fmt"
+
 	"back-end/internal/admin/adminapplication"
 	"back-end/internal/admin/admininfrastructure"
 	"back-end/internal/admin/admininterfaces"
+	userinfrastructure "back-end/internal/user/user-infrastructure"
 	"back-end/pkg/middleware"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,8 +19,30 @@ import (
 func AdminReportRoutes(app *fiber.App, redisClient *redis.Client, mongoClient *mongo.Client) {
 	// Se crea el repositorio de reportes (no se necesita Redis para estos endpoints)
 	reportRepo := admininfrastructure.NewReportRepository(mongoClient)
-	reportService := adminapplication.NewReportService(reportRepo)
+	userRepo := userinfrastructure.NewUserRepository(redisClient, mongoClient)
+	reportService := adminapplication.NewReportService(reportRepo, userRepo)
 	reportHandler := admininterfaces.NewReportHandler(reportService)
+	if err := reportRepo.EnsureContentReportIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de reportes de contenido:", err)
+	}
+	if err := reportRepo.EnsureAuditIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices del log de auditoría:", err)
+	}
+	if err := reportRepo.EnsureSanctionIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de sanciones:", err)
+	}
+	if err := reportRepo.MigrateLegacyBans(context.Background()); err != nil {
+		fmt.Println("Error migrando baneos anteriores a las sanciones:", err)
+	}
+	if err := reportRepo.EnsureUserDirectoryIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices del directorio de usuarios:", err)
+	}
+	// historial de premium de los usuarios suscriptos antes de que existiera
+	if backfilled, err := reportRepo.BackfillPremiumHistory(context.Background()); err != nil {
+		fmt.Println("Error completando el historial de premium:", err)
+	} else if backfilled > 0 {
+		fmt.Println("Historial de premium completado para", backfilled, "usuarios")
+	}
 
 	adminGroup := app.Group("/admin")
 	reportsGroup := app.Group("/reports")
@@ -37,6 +63,11 @@ func AdminReportRoutes(app *fiber.App, redisClient *redis.Client, mongoClient *m
 
 	adminGroup.Get("/GetUsers", middleware.UseExtractor(), reportHandler.GetUsers) // Obtener a los usuarios desde admin
 
+	// directorio de usuarios; código de admin en el header X-Admin-Code
+	adminGroup.Get("/users", middleware.UseExtractor(), reportHandler.SearchUsers)
+	adminGroup.Get("/users/export", middleware.UseExtractor(), reportHandler.ExportUsersCSV)
+	adminGroup.Get("/users/:id", middleware.UseExtractor(), reportHandler.GetUserDetail)
+
 	// admmistrar habilidad de trabajar
 
 	adminGroup.Post("/disableUserForWork", middleware.UseExtractor(), reportHandler.DisableUserForWork) // Bloquear usuario (requiere autorización de admin)
@@ -44,11 +75,37 @@ func AdminReportRoutes(app *fiber.App, redisClient *redis.Client, mongoClient *m
 
 	adminGroup.Delete("/deleteJob", middleware.UseExtractor(), reportHandler.DeleteJob)                     // delete job(requiere autorización de admin)
 	adminGroup.Delete("/deletePost", middleware.UseExtractor(), reportHandler.DeletePost)                   // delete job(requiere autorización de admin)
-	adminGroup.Delete("/deleteContentReport", middleware.UseExtractor(), reportHandler.DeleteContentReport) // Crear reporte
+	adminGroup.Delete("/deleteContentReport", middleware.UseExtractor(), reportHandler.DeleteContentReport) // Descartar reporte (queda cerrado como "dismissed")
+
+	// flujo de moderación de reportes de contenido
+	adminGroup.Get("/contentReports/:id", middleware.UseExtractor(), reportHandler.GetContentReport) // Código de admin en el header X-Admin-Code
+	adminGroup.Post("/contentReports/assign", middleware.UseExtractor(), reportHandler.AssignContentReport)
+	adminGroup.Post("/contentReports/notes", middleware.UseExtractor(), reportHandler.AddContentReportNote)
+	adminGroup.Post("/contentReports/dismiss", middleware.UseExtractor(), reportHandler.DeleteContentReport)
+
+	// contenido retenido por la moderación automática
+	adminGroup.Post("/moderation/release", middleware.UseExtractor(), reportHandler.ReleaseHeldContent)
+	adminGroup.Post("/moderation/discard", middleware.UseExtractor(), reportHandler.DiscardHeldContent)
 
 	// admin tags
 	adminGroup.Get("/tags", reportHandler.GetAllTagsHandler)
-	adminGroup.Post("/tags", middleware.UseExtractor(), reportHandler.AddTagHandler)
-	adminGroup.Delete("/tags/:tag", middleware.UseExtractor(), reportHandler.RemoveTagHandler)
+	adminGroup.Post("/tags", middleware.UseExtractor(), reportHandler.AddTagHandler)           // Agregar tag (requiere autorización de admin)
+	adminGroup.Delete("/tags/:tag", middleware.UseExtractor(), reportHandler.RemoveTagHandler) // Eliminar tag (requiere autorización de admin, header X-Admin-Code)
+
+	// cambio de NameUser por un admin
+	adminGroup.Post("/changeNameUser", middleware.UseExtractor(), reportHandler.ChangeNameUser)
+
+	// sanciones graduadas: advertencias, restricciones, suspensiones y baneos
+	adminGroup.Get("/sanctions", middleware.UseExtractor(), reportHandler.GetUserSanctions) // Código de admin en el header X-Admin-Code
+	adminGroup.Post("/sanctions", middleware.UseExtractor(), reportHandler.IssueSanction)
+	adminGroup.Post("/sanctions/lift", middleware.UseExtractor(), reportHandler.LiftSanction)
+	adminGroup.Post("/sanctions/appeals/reject", middleware.UseExtractor(), reportHandler.RejectSanctionAppeal)
+
+	// log de auditoría de las acciones de los admins
+	adminGroup.Get("/audit", middleware.UseExtractor(), reportHandler.GetAuditLog)
+
+	// outbox: eventos que agotaron sus reintentos
+	adminGroup.Get("/outbox/dead-letters", middleware.UseExtractor(), reportHandler.GetDeadLetterEvents)
+	adminGroup.Post("/outbox/retry", middleware.UseExtractor(), reportHandler.RetryDeadLetterEvent)
 
 }
@@ -1,6 +1,7 @@
 package chatdomain
 
 import (
+	"back-end/pkg/mentions"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -26,6 +27,9 @@ type ChatMessage struct {
 	Text       string             `json:"text" bson:"text"`
 	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
 	IsRead     bool               `json:"isRead" bson:"isRead"`
+	// Menciones y hashtags del texto. No generan avisos aparte: el único que puede leer el
+	// mensaje es el receptor, que ya recibe la notificación del mensaje.
+	mentions.Entities `bson:",inline"`
 }
 
 // ChatDetails es utilizado para retornar la información agregada de una sala de chat,
@@ -42,3 +46,15 @@ type ChatDetails struct {
 		Avatar   string             `bson:"Avatar" json:"avatar"`
 	} `bson:"otherUser" json:"otherUser"`
 }
+
+// EventMessageSent es el evento del outbox que dispara el push al receptor de un mensaje.
+const EventMessageSent = "chat.message_sent"
+
+// MessageSentEvent es el payload de EventMessageSent.
+type MessageSentEvent struct {
+	ChatRoomID primitive.ObjectID `bson:"chatRoomId"`
+	SenderID   primitive.ObjectID `bson:"senderId"`
+	ReceiverID primitive.ObjectID `bson:"receiverId"`
+	SenderName string             `bson:"senderName"`
+	Text       string             `bson:"text"`
+}
@@ -1,15 +1,20 @@
 package chatinfrastructure
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"time"
 
 	"back-end/internal/chat/chatdomain"
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/internal/notifications/notificationinfrastructure"
+	"back-end/pkg/mentions"
+	"back-end/pkg/moderation"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"back-end/pkg/sanctions"
 
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
@@ -22,6 +27,12 @@ import (
 type ChatRepository struct {
 	mongoClient *mongo.Client
 	redisClient *redis.Client
+	outbox      *outbox.Store
+	push        *push.Service
+	moderator   *moderation.Moderator
+	sanctions   *sanctions.Store
+
+	notifications *notificationinfrastructure.NotificationRepository
 }
 
 // NewChatRepository crea una nueva instancia de ChatRepository.
@@ -29,11 +40,29 @@ func NewChatRepository(mongoClient *mongo.Client, redisClient *redis.Client) *Ch
 	return &ChatRepository{
 		mongoClient: mongoClient,
 		redisClient: redisClient,
+		outbox:      outbox.NewStore(mongoClient),
+		push:        push.NewDefaultService(mongoClient),
+		moderator:   moderation.NewDefaultModerator(mongoClient),
+		sanctions:   sanctions.NewStore(mongoClient),
+
+		notifications: notificationinfrastructure.NewNotificationRepository(mongoClient, redisClient),
 	}
 }
 
-// SendMessage guarda un mensaje en la colección "chat_messages" y lo publica en Redis.
+// SendMessage verifica que el remitente no tenga sanciones que le impidan chatear, pasa el mensaje por
+// la moderación, lo guarda en la colección "chat_messages" y lo publica en Redis. Un mensaje rechazado
+// devuelve moderation.ErrRejected sin crear la sala.
 func (r *ChatRepository) SendMessage(ctx context.Context, msg chatdomain.ChatMessage, senderName string) (chatdomain.ChatMessage, error) {
+	if err := r.sanctions.Check(ctx, msg.SenderID, sanctions.ScopeChat); err != nil {
+		return chatdomain.ChatMessage{}, err
+	}
+	decision, err := r.moderator.Review(ctx, moderation.ContentMessage, &msg.Text)
+	if err != nil {
+		return chatdomain.ChatMessage{}, err
+	}
+	if err := decision.Err(); err != nil {
+		return chatdomain.ChatMessage{}, err
+	}
 	// Obtener o crear ChatRoom entre sender y receiver usando el arreglo "participants".
 	chatRoom, err := r.findOrCreateChatRoom(ctx, msg.SenderID, msg.ReceiverID)
 	if err != nil {
@@ -45,10 +74,30 @@ func (r *ChatRepository) SendMessage(ctx context.Context, msg chatdomain.ChatMes
 	msg.ID = primitive.NewObjectID()
 	msg.CreatedAt = time.Now()
 	msg.IsRead = false
+	msg.Entities, err = mentions.Extract(ctx, r.mongoClient, msg.Text)
+	if err != nil {
+		return chatdomain.ChatMessage{}, fmt.Errorf("error buscando menciones: %v", err)
+	}
 	db := r.mongoClient.Database("NEXO-VECINAL")
 	collectionChat := db.Collection("chat_messages")
 
-	_, err = collectionChat.InsertOne(ctx, msg)
+	// El push al receptor se registra en el outbox junto con el mensaje; un fallo de Expo no afecta el envío.
+	event, err := outbox.NewEvent(chatdomain.EventMessageSent, chatdomain.MessageSentEvent{
+		ChatRoomID: msg.ChatRoomID,
+		SenderID:   msg.SenderID,
+		ReceiverID: msg.ReceiverID,
+		SenderName: senderName,
+		Text:       msg.Text,
+	})
+	if err != nil {
+		return chatdomain.ChatMessage{}, err
+	}
+	err = r.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		if _, err := collectionChat.InsertOne(sessCtx, msg); err != nil {
+			return err
+		}
+		return r.outbox.Record(sessCtx, event)
+	})
 	if err != nil {
 		return chatdomain.ChatMessage{}, fmt.Errorf("error insertando mensaje: %v", err)
 	}
@@ -62,14 +111,40 @@ func (r *ChatRepository) SendMessage(ctx context.Context, msg chatdomain.ChatMes
 	if err := r.redisClient.Publish(ctx, channel, messageBytes).Err(); err != nil {
 		return chatdomain.ChatMessage{}, fmt.Errorf("error publicando mensaje: %v", err)
 	}
-
-	// Enviar notificación push al receptor.
-	if err := r.notifyMessage(msg.ReceiverID, msg.Text, senderName); err != nil {
-		return chatdomain.ChatMessage{}, errors.New("error enviando notificación push")
-	}
 	return msg, nil
 }
 
+// RegisterOutboxHandlers registra la notificación y el push de los mensajes de chat.
+func (r *ChatRepository) RegisterOutboxHandlers(d *outbox.Dispatcher) {
+	d.Handle(chatdomain.EventMessageSent, func(ctx context.Context, event outbox.Event) error {
+		var payload chatdomain.MessageSentEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		err := r.notifications.Create(ctx, notificationdomain.Notification{
+			UserID:   payload.ReceiverID,
+			Type:     notificationdomain.TypeMessage,
+			Title:    payload.SenderName,
+			Body:     payload.Text,
+			Data:     map[string]string{"chatRoomId": payload.ChatRoomID.Hex(), "senderId": payload.SenderID.Hex()},
+			SourceID: event.ID.Hex(),
+		})
+		if err != nil {
+			return err
+		}
+		n := messageNotification(payload.Text, payload.SenderName)
+		allowed, err := r.notifications.PushAllowed(ctx, payload.ReceiverID, notificationdomain.CategoryChat, n, time.Now())
+		if err != nil || !allowed {
+			return err
+		}
+		err = r.push.SendToUsers(ctx, []primitive.ObjectID{payload.ReceiverID}, n)
+		if errors.Is(err, push.ErrNoTokens) {
+			return nil
+		}
+		return err
+	})
+}
+
 // getUserNameByID busca en la colección "Users" el nombre del usuario por su ID
 func (r *ChatRepository) getUserNameByID(ctx context.Context, userID primitive.ObjectID) (string, error) {
 	collectionUsers := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
@@ -153,47 +228,14 @@ func (r *ChatRepository) BlockUser(ctx context.Context, chatRoomID, blockerID st
 	return err
 }
 
-func (r *ChatRepository) notifyMessage(user primitive.ObjectID, messageText, senderName string) error {
-	pushToken, err := r.getPushTokenUser(user)
-	if err != nil {
-		return err
-	}
-	payload := map[string]interface{}{
-		"to":        pushToken,
-		"title":     senderName,
-		"body":      messageText,
-		"data":      map[string]string{"message": messageText},
-		"channelId": "chats",
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-	resp, err := http.Post("https://exp.host/--/api/v2/push/send", "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error enviando notificación, status: %d", resp.StatusCode)
-	}
-	return nil
-}
-
-func (r *ChatRepository) getPushTokenUser(userID primitive.ObjectID) (string, error) {
-	userColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
-	var user struct {
-		PushToken string `bson:"pushToken"`
-	}
-	filter := bson.M{"_id": userID}
-	err := userColl.FindOne(context.Background(), filter).Decode(&user)
-	if err != nil {
-		return "", err
-	}
-	if user.PushToken == "" {
-		return "", errors.New("el usuario no tiene push token")
+// messageNotification arma el push de un mensaje nuevo.
+func messageNotification(messageText, senderName string) push.Notification {
+	return push.Notification{
+		Title:     senderName,
+		Body:      messageText,
+		Data:      map[string]string{"message": messageText},
+		ChannelID: "chats",
 	}
-	return user.PushToken, nil
 }
 
 // GetOrCreateChatRoom utiliza "participantsKey" para asegurar la unicidad de la combinación completa.
@@ -4,7 +4,10 @@ package chatinterfaces
 import (
 	"back-end/internal/chat/chatapplication"
 	"back-end/internal/chat/chatdomain"
+	"back-end/pkg/moderation"
+	"back-end/pkg/sanctions"
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -44,7 +47,8 @@ func (h *ChatHandler) GetChatRoom(c *fiber.Ctx) error {
 }
 
 // SendMessage endpoint para enviar un mensaje (POST /chat/messages).
-// Se espera en el body JSON: senderId, receiverId y text.
+// Se espera en el body JSON: receiverId y text. El remitente es siempre el usuario del token, para
+// que las sanciones de chat no se puedan eludir enviando otro senderId.
 func (h *ChatHandler) SendMessage(c *fiber.Ctx) error {
 	nameuser := c.Context().UserValue("nameUser").(string)
 	var msg chatdomain.ChatMessage
@@ -52,15 +56,11 @@ func (h *ChatHandler) SendMessage(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
 	}
 
-	// Convertir senderId si es necesario.
-	if msg.SenderID.IsZero() {
-		senderStr := c.FormValue("senderId")
-		senderID, err := primitive.ObjectIDFromHex(senderStr)
-		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "senderId inválido"})
-		}
-		msg.SenderID = senderID
+	senderID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "senderId inválido"})
 	}
+	msg.SenderID = senderID
 
 	// Convertir receiverId si es necesario.
 	if msg.ReceiverID.IsZero() {
@@ -73,6 +73,18 @@ func (h *ChatHandler) SendMessage(c *fiber.Ctx) error {
 	}
 
 	savedMsg, err := h.ChatService.SendMessage(context.Background(), msg, nameuser)
+	if sanction, ok := sanctions.As(err); ok {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message":  "Tu cuenta tiene una sanción que no te permite enviar mensajes",
+			"sanction": sanction,
+		})
+	}
+	if errors.Is(err, moderation.ErrRejected) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"message": "El mensaje no cumple las normas de la comunidad",
+			"data":    err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": "Error interno",
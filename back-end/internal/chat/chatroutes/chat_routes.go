@@ -6,6 +6,8 @@ import (
 	"back-end/internal/chat/chatinfrastructure"
 	"back-end/internal/chat/chatinterfaces"
 	"back-end/pkg/middleware"
+	"back-end/pkg/outbox"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
@@ -18,6 +20,10 @@ func ChatRoutes(app *fiber.App, redisClient *redis.Client, mongoClient *mongo.Cl
 	chatService := chatapplication.NewChatService(chatRepo)
 	chatHandler := chatinterfaces.NewChatHandler(chatService)
 
+	dispatcher := outbox.NewDispatcher(outbox.NewStore(mongoClient))
+	chatRepo.RegisterOutboxHandlers(dispatcher)
+	dispatcher.Start(5 * time.Second)
+
 	chatGroup := app.Group("/chat")
 	chatGroup.Get("/room", middleware.UseExtractor(), chatHandler.GetChatRoom)
 	chatGroup.Post("/messages", middleware.UseExtractor(), chatHandler.SendMessage)
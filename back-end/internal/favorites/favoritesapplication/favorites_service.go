@@ -0,0 +1,49 @@
+package favoritesapplication
+
+import (
+	"back-end/internal/favorites/favoritesdomain"
+	"back-end/internal/favorites/favoritesinfrastructure"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FavoriteService gestiona los trabajos y trabajadores favoritos.
+type FavoriteService struct {
+	repo *favoritesinfrastructure.FavoriteRepository
+}
+
+// NewFavoriteService crea una nueva instancia de FavoriteService.
+func NewFavoriteService(repo *favoritesinfrastructure.FavoriteRepository) *FavoriteService {
+	return &FavoriteService{repo: repo}
+}
+
+// AddJob guarda un trabajo para postularse más tarde.
+func (s *FavoriteService) AddJob(ctx context.Context, userID, jobID primitive.ObjectID) error {
+	return s.repo.AddJob(ctx, userID, jobID)
+}
+
+// RemoveJob quita un trabajo de favoritos.
+func (s *FavoriteService) RemoveJob(ctx context.Context, userID, jobID primitive.ObjectID) error {
+	return s.repo.RemoveJob(ctx, userID, jobID)
+}
+
+// ListJobs lista los trabajos favoritos paginados.
+func (s *FavoriteService) ListJobs(ctx context.Context, userID primitive.ObjectID, page int) ([]favoritesdomain.FavoriteJobDetails, error) {
+	return s.repo.ListJobs(ctx, userID, page)
+}
+
+// AddWorker agrega un trabajador a la lista de confianza.
+func (s *FavoriteService) AddWorker(ctx context.Context, userID, workerID primitive.ObjectID) error {
+	return s.repo.AddWorker(ctx, userID, workerID)
+}
+
+// RemoveWorker quita un trabajador de favoritos.
+func (s *FavoriteService) RemoveWorker(ctx context.Context, userID, workerID primitive.ObjectID) error {
+	return s.repo.RemoveWorker(ctx, userID, workerID)
+}
+
+// ListWorkers lista los trabajadores favoritos paginados.
+func (s *FavoriteService) ListWorkers(ctx context.Context, userID primitive.ObjectID, page int) ([]favoritesdomain.FavoriteWorkerDetails, error) {
+	return s.repo.ListWorkers(ctx, userID, page)
+}
@@ -0,0 +1,51 @@
+package favoritesdomain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FavoriteJob es un trabajo guardado por el usuario para postularse más tarde.
+type FavoriteJob struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
+	JobID     primitive.ObjectID `json:"jobId" bson:"jobId"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// FavoriteWorker es un trabajador de confianza en la lista del usuario.
+type FavoriteWorker struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
+	WorkerID  primitive.ObjectID `json:"workerId" bson:"workerId"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// FavoriteJobDetails es un trabajo favorito con los datos necesarios para listarlo.
+type FavoriteJobDetails struct {
+	JobID       primitive.ObjectID `json:"jobId" bson:"_id"`
+	Title       string             `json:"title" bson:"title"`
+	Budget      float64            `json:"budget" bson:"budget"`
+	Status      string             `json:"status" bson:"status"`
+	Tags        []string           `json:"tags" bson:"tags"`
+	Images      []string           `json:"Images" bson:"Images"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	SavedAt     time.Time          `json:"savedAt" bson:"savedAt"`
+	UserDetails struct {
+		ID       primitive.ObjectID `json:"id" bson:"_id"`
+		NameUser string             `json:"nameUser" bson:"NameUser"`
+		Avatar   string             `json:"avatar" bson:"Avatar"`
+	} `json:"userDetails" bson:"userDetails"`
+}
+
+// FavoriteWorkerDetails es un trabajador favorito con su perfil resumido.
+type FavoriteWorkerDetails struct {
+	WorkerID        primitive.ObjectID `json:"workerId" bson:"_id"`
+	NameUser        string             `json:"nameUser" bson:"NameUser"`
+	Avatar          string             `json:"avatar" bson:"Avatar"`
+	Tags            []string           `json:"tags" bson:"tags"`
+	CompletedJobs   int                `json:"completedJobs" bson:"completedJobs"`
+	AvailableToWork bool               `json:"availableToWork" bson:"availableToWork"`
+	SavedAt         time.Time          `json:"savedAt" bson:"savedAt"`
+}
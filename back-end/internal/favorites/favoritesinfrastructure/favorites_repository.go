@@ -0,0 +1,220 @@
+package favoritesinfrastructure
+
+import (
+	"back-end/internal/favorites/favoritesdomain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const pageSize = 10
+
+// FavoriteRepository persiste los trabajos y trabajadores favoritos de cada usuario.
+type FavoriteRepository struct {
+	mongoClient *mongo.Client
+}
+
+// NewFavoriteRepository crea una nueva instancia de FavoriteRepository.
+func NewFavoriteRepository(mongoClient *mongo.Client) *FavoriteRepository {
+	return &FavoriteRepository{mongoClient: mongoClient}
+}
+
+func (r *FavoriteRepository) db() *mongo.Database {
+	return r.mongoClient.Database("NEXO-VECINAL")
+}
+
+// EnsureIndexes crea los índices únicos que evitan favoritos duplicados.
+func (r *FavoriteRepository) EnsureIndexes(ctx context.Context) error {
+	unique := options.Index().SetUnique(true)
+	if _, err := r.db().Collection("FavoriteJobs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "jobId", Value: 1}},
+		Options: unique,
+	}); err != nil {
+		return err
+	}
+	_, err := r.db().Collection("FavoriteWorkers").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "workerId", Value: 1}},
+		Options: unique,
+	})
+	return err
+}
+
+// AddJob agrega un trabajo a favoritos; repetirlo no tiene efecto.
+func (r *FavoriteRepository) AddJob(ctx context.Context, userID, jobID primitive.ObjectID) error {
+	count, err := r.db().Collection("Job").CountDocuments(ctx, bson.M{"_id": jobID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("trabajo no encontrado")
+	}
+	return r.upsert(ctx, "FavoriteJobs", bson.M{"userId": userID, "jobId": jobID})
+}
+
+// RemoveJob quita un trabajo de favoritos.
+func (r *FavoriteRepository) RemoveJob(ctx context.Context, userID, jobID primitive.ObjectID) error {
+	return r.remove(ctx, "FavoriteJobs", bson.M{"userId": userID, "jobId": jobID})
+}
+
+// AddWorker agrega un trabajador a favoritos; repetirlo no tiene efecto.
+func (r *FavoriteRepository) AddWorker(ctx context.Context, userID, workerID primitive.ObjectID) error {
+	if userID == workerID {
+		return errors.New("no puedes agregarte a tus propios favoritos")
+	}
+	count, err := r.db().Collection("Users").CountDocuments(ctx, bson.M{"_id": workerID, "Banned": bson.M{"$ne": true}})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("usuario no encontrado")
+	}
+	return r.upsert(ctx, "FavoriteWorkers", bson.M{"userId": userID, "workerId": workerID})
+}
+
+// RemoveWorker quita un trabajador de favoritos.
+func (r *FavoriteRepository) RemoveWorker(ctx context.Context, userID, workerID primitive.ObjectID) error {
+	return r.remove(ctx, "FavoriteWorkers", bson.M{"userId": userID, "workerId": workerID})
+}
+
+func (r *FavoriteRepository) upsert(ctx context.Context, collection string, filter bson.M) error {
+	_, err := r.db().Collection(collection).UpdateOne(ctx, filter,
+		bson.M{"$setOnInsert": bson.M{"createdAt": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *FavoriteRepository) remove(ctx context.Context, collection string, filter bson.M) error {
+	res, err := r.db().Collection(collection).DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errors.New("favorito no encontrado")
+	}
+	return nil
+}
+
+// ListJobs devuelve los trabajos favoritos del usuario, los últimos guardados primero.
+func (r *FavoriteRepository) ListJobs(ctx context.Context, userID primitive.ObjectID, page int) ([]favoritesdomain.FavoriteJobDetails, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID}}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$skip", Value: int64((page - 1) * pageSize)}},
+		{{Key: "$limit", Value: pageSize}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "Job",
+			"localField":   "jobId",
+			"foreignField": "_id",
+			"as":           "job",
+		}}},
+		// Los trabajos eliminados desaparecen de la lista
+		{{Key: "$unwind", Value: "$job"}},
+		{{Key: "$replaceRoot", Value: bson.M{
+			"newRoot": bson.M{"$mergeObjects": bson.A{"$job", bson.M{"savedAt": "$createdAt"}}},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "Users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "userDetails",
+		}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$userDetails", "preserveNullAndEmptyArrays": true}}},
+	}
+	cursor, err := r.db().Collection("FavoriteJobs").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []favoritesdomain.FavoriteJobDetails{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ListWorkers devuelve los trabajadores favoritos del usuario, los últimos guardados primero.
+func (r *FavoriteRepository) ListWorkers(ctx context.Context, userID primitive.ObjectID, page int) ([]favoritesdomain.FavoriteWorkerDetails, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID}}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$skip", Value: int64((page - 1) * pageSize)}},
+		{{Key: "$limit", Value: pageSize}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "Users",
+			"localField":   "workerId",
+			"foreignField": "_id",
+			"as":           "worker",
+		}}},
+		{{Key: "$unwind", Value: "$worker"}},
+		{{Key: "$project", Value: bson.M{
+			"_id":             "$worker._id",
+			"NameUser":        "$worker.NameUser",
+			"Avatar":          "$worker.Avatar",
+			"tags":            "$worker.tags",
+			"completedJobs":   "$worker.completedJobs",
+			"availableToWork": "$worker.availableToWork",
+			"savedAt":         "$createdAt",
+		}}},
+	}
+	cursor, err := r.db().Collection("FavoriteWorkers").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workers := []favoritesdomain.FavoriteWorkerDetails{}
+	if err := cursor.All(ctx, &workers); err != nil {
+		return nil, err
+	}
+	return workers, nil
+}
+
+// FavoriteJobIDs indica cuáles de los trabajos dados son favoritos del usuario.
+func (r *FavoriteRepository) FavoriteJobIDs(ctx context.Context, userID primitive.ObjectID, jobIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	return r.favoriteIDs(ctx, "FavoriteJobs", "jobId", userID, jobIDs)
+}
+
+// FavoriteWorkerIDs indica cuáles de los usuarios dados son trabajadores favoritos del usuario.
+func (r *FavoriteRepository) FavoriteWorkerIDs(ctx context.Context, userID primitive.ObjectID, workerIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	return r.favoriteIDs(ctx, "FavoriteWorkers", "workerId", userID, workerIDs)
+}
+
+func (r *FavoriteRepository) favoriteIDs(ctx context.Context, collection, field string, userID primitive.ObjectID, ids []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	result := map[primitive.ObjectID]bool{}
+	if userID.IsZero() || len(ids) == 0 {
+		return result, nil
+	}
+	opts := options.Find().SetProjection(bson.M{field: 1})
+	cursor, err := r.db().Collection(collection).Find(ctx, bson.M{"userId": userID, field: bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		if id, ok := doc[field].(primitive.ObjectID); ok {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+// IsFavoriteWorker indica si workerID está en los favoritos del usuario.
+func (r *FavoriteRepository) IsFavoriteWorker(ctx context.Context, userID, workerID primitive.ObjectID) (bool, error) {
+	favorites, err := r.FavoriteWorkerIDs(ctx, userID, []primitive.ObjectID{workerID})
+	if err != nil {
+		return false, err
+	}
+	return favorites[workerID], nil
+}
@@ -0,0 +1,157 @@
+package favoritesinterfaces
+
+import (
+	"back-end/internal/favorites/favoritesapplication"
+	"context"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FavoriteHandler expone los endpoints de favoritos.
+type FavoriteHandler struct {
+	FavoriteService *favoritesapplication.FavoriteService
+}
+
+// NewFavoriteHandler crea una nueva instancia de FavoriteHandler.
+func NewFavoriteHandler(service *favoritesapplication.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{
+		FavoriteService: service,
+	}
+}
+
+// AddJob guarda un trabajo en favoritos (POST /favorites/jobs/:jobId).
+func (h *FavoriteHandler) AddJob(c *fiber.Ctx) error {
+	userID, jobID, err := favoriteParams(c, "jobId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid ID",
+		})
+	}
+	if err := h.FavoriteService.AddJob(context.Background(), userID, jobID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Error al guardar el trabajo",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Trabajo agregado a favoritos",
+	})
+}
+
+// RemoveJob quita un trabajo de favoritos (DELETE /favorites/jobs/:jobId).
+func (h *FavoriteHandler) RemoveJob(c *fiber.Ctx) error {
+	userID, jobID, err := favoriteParams(c, "jobId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid ID",
+		})
+	}
+	if err := h.FavoriteService.RemoveJob(context.Background(), userID, jobID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "Error al quitar el trabajo",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Trabajo quitado de favoritos",
+	})
+}
+
+// GetJobs lista los trabajos favoritos (GET /favorites/jobs?page=1).
+func (h *FavoriteHandler) GetJobs(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	jobs, err := h.FavoriteService.ListJobs(context.Background(), userID, pageQuery(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Error al obtener los favoritos",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"data":    jobs,
+	})
+}
+
+// AddWorker agrega un trabajador a favoritos (POST /favorites/workers/:workerId).
+func (h *FavoriteHandler) AddWorker(c *fiber.Ctx) error {
+	userID, workerID, err := favoriteParams(c, "workerId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid ID",
+		})
+	}
+	if err := h.FavoriteService.AddWorker(context.Background(), userID, workerID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Error al guardar el trabajador",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Trabajador agregado a favoritos",
+	})
+}
+
+// RemoveWorker quita un trabajador de favoritos (DELETE /favorites/workers/:workerId).
+func (h *FavoriteHandler) RemoveWorker(c *fiber.Ctx) error {
+	userID, workerID, err := favoriteParams(c, "workerId")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid ID",
+		})
+	}
+	if err := h.FavoriteService.RemoveWorker(context.Background(), userID, workerID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "Error al quitar el trabajador",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Trabajador quitado de favoritos",
+	})
+}
+
+// GetWorkers lista los trabajadores favoritos (GET /favorites/workers?page=1).
+func (h *FavoriteHandler) GetWorkers(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	workers, err := h.FavoriteService.ListWorkers(context.Background(), userID, pageQuery(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Error al obtener los favoritos",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"data":    workers,
+	})
+}
+
+func favoriteParams(c *fiber.Ctx, param string) (primitive.ObjectID, primitive.ObjectID, error) {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, err
+	}
+	targetID, err := primitive.ObjectIDFromHex(c.Params(param))
+	return userID, targetID, err
+}
+
+func pageQuery(c *fiber.Ctx) int {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
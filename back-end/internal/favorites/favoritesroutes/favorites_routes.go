@@ -0,0 +1,33 @@
+package favoritesroutes
+
+import (
+	"back-end/internal/favorites/favoritesapplication"
+	"back-end/internal/favorites/favoritesinfrastructure"
+	"back-end/internal/favorites/favoritesinterfaces"
+	"back-end/pkg/middleware"
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// FavoriteRoutes configura los endpoints de trabajos y trabajadores favoritos.
+func FavoriteRoutes(app *fiber.App, redisClient *redis.Client, mongoClient *mongo.Client) {
+	repo := favoritesinfrastructure.NewFavoriteRepository(mongoClient)
+	service := favoritesapplication.NewFavoriteService(repo)
+	handler := favoritesinterfaces.NewFavoriteHandler(service)
+
+	if err := repo.EnsureIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de favoritos:", err)
+	}
+
+	favoritesGroup := app.Group("/favorites")
+	favoritesGroup.Get("/jobs", middleware.UseExtractor(), handler.GetJobs)
+	favoritesGroup.Post("/jobs/:jobId", middleware.UseExtractor(), handler.AddJob)
+	favoritesGroup.Delete("/jobs/:jobId", middleware.UseExtractor(), handler.RemoveJob)
+	favoritesGroup.Get("/workers", middleware.UseExtractor(), handler.GetWorkers)
+	favoritesGroup.Post("/workers/:workerId", middleware.UseExtractor(), handler.AddWorker)
+	favoritesGroup.Delete("/workers/:workerId", middleware.UseExtractor(), handler.RemoveWorker)
+}
@@ -0,0 +1,209 @@
+package notificationapplication
+
+import (
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/internal/notifications/notificationinfrastructure"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// premiumNoticeWindow es la anticipación con la que se avisa el vencimiento del premium.
+const premiumNoticeWindow = 3 * 24 * time.Hour
+
+// NotificationService contiene la lógica del centro de notificaciones.
+type NotificationService struct {
+	Repo *notificationinfrastructure.NotificationRepository
+	Push *push.Service
+}
+
+// NewNotificationService crea una nueva instancia de NotificationService.
+func NewNotificationService(repo *notificationinfrastructure.NotificationRepository, pushService *push.Service) *NotificationService {
+	return &NotificationService{
+		Repo: repo,
+		Push: pushService,
+	}
+}
+
+// GetNotifications devuelve una página de notificaciones del usuario.
+func (s *NotificationService) GetNotifications(ctx context.Context, userID primitive.ObjectID, page int, onlyUnread bool) ([]notificationdomain.Notification, error) {
+	return s.Repo.List(ctx, userID, page, onlyUnread)
+}
+
+// GetUnreadCounts devuelve los contadores de no leídas (total y por tipo).
+func (s *NotificationService) GetUnreadCounts(ctx context.Context, userID primitive.ObjectID) (int64, map[string]int64, error) {
+	return s.Repo.UnreadCounts(ctx, userID)
+}
+
+// MarkRead marca una notificación como leída.
+func (s *NotificationService) MarkRead(ctx context.Context, userID, notificationID primitive.ObjectID) error {
+	return s.Repo.MarkRead(ctx, userID, notificationID)
+}
+
+// MarkAllRead marca todas las notificaciones del usuario como leídas.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.Repo.MarkAllRead(ctx, userID)
+}
+
+// Subscribe abre la suscripción al stream de notificaciones del usuario.
+func (s *NotificationService) Subscribe(ctx context.Context, userID primitive.ObjectID) (*redis.PubSub, error) {
+	return s.Repo.Subscribe(ctx, userID)
+}
+
+// StartPremiumExpiryNotifier avisa periódicamente a los usuarios cuyo premium está por vencer.
+func (s *NotificationService) StartPremiumExpiryNotifier(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := s.NotifyPremiumExpiring(context.Background(), time.Now()); err != nil {
+				fmt.Println("Error avisando vencimientos de premium:", err)
+			}
+		}
+	}()
+}
+
+// NotifyPremiumExpiring crea la notificación y envía el push una única vez por vencimiento.
+func (s *NotificationService) NotifyPremiumExpiring(ctx context.Context, now time.Time) error {
+	users, err := s.Repo.FindUsersWithPremiumExpiring(ctx, now, now.Add(premiumNoticeWindow))
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		end := user.Premium.SubscriptionEnd
+		title := "Tu Premium está por vencer"
+		body := fmt.Sprintf("Tu suscripción vence el %s. Renovala para seguir postulándote sin límites.", end.Format("02/01/2006"))
+		err := s.Repo.Create(ctx, notificationdomain.Notification{
+			UserID:   user.ID,
+			Type:     notificationdomain.TypePremiumExpiring,
+			Title:    title,
+			Body:     body,
+			SourceID: "premium:" + end.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+		err = s.Push.SendToUsers(ctx, []primitive.ObjectID{user.ID}, push.Notification{Title: title, Body: body})
+		if err != nil && !errors.Is(err, push.ErrNoTokens) {
+			fmt.Println("Error enviando push de premium:", err)
+		}
+		if err := s.Repo.MarkPremiumExpiryNotified(ctx, user.ID, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPreferences devuelve las preferencias de notificaciones del usuario.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID primitive.ObjectID) (notificationdomain.Preferences, error) {
+	return s.Repo.GetPreferences(ctx, userID)
+}
+
+// UpdatePreferences valida y guarda las preferencias de notificaciones del usuario.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID primitive.ObjectID, prefs notificationdomain.Preferences) error {
+	if err := prefs.Validate(); err != nil {
+		return err
+	}
+	return s.Repo.SavePreferences(ctx, userID, prefs)
+}
+
+// RegisterOutboxHandlers registra el envío de los push pospuestos por el horario silencioso.
+func (s *NotificationService) RegisterOutboxHandlers(d *outbox.Dispatcher) {
+	d.Handle(notificationdomain.EventDeferredPush, func(ctx context.Context, event outbox.Event) error {
+		var payload notificationdomain.DeferredPushEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		n := push.Notification{
+			Title:     payload.Title,
+			Body:      payload.Body,
+			Data:      payload.Data,
+			ChannelID: payload.ChannelID,
+		}
+		// Las preferencias pudieron cambiar mientras tanto; si sigue en horario silencioso se vuelve a posponer
+		allowed, err := s.Repo.PushAllowed(ctx, payload.UserID, payload.Category, n, time.Now())
+		if err != nil || !allowed {
+			return err
+		}
+		err = s.Push.SendToUsers(ctx, []primitive.ObjectID{payload.UserID}, n)
+		if errors.Is(err, push.ErrNoTokens) {
+			return nil
+		}
+		return err
+	})
+}
+
+// StartDigestSender envía periódicamente los resúmenes de nuevos trabajos que ya corresponden.
+func (s *NotificationService) StartDigestSender(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := s.SendDueDigests(context.Background(), time.Now()); err != nil {
+				fmt.Println("Error enviando resúmenes de trabajos:", err)
+			}
+		}
+	}()
+}
+
+// digestPageSize es la cantidad de resúmenes pendientes que se leen por página.
+const digestPageSize = 500
+
+// SendDueDigests agrupa en un único push los nuevos trabajos acumulados de cada usuario. Recorre
+// todos los resúmenes pendientes en páginas, así los que todavía no vencen no tapan a los siguientes.
+func (s *NotificationService) SendDueDigests(ctx context.Context, now time.Time) error {
+	after := primitive.NilObjectID
+	for {
+		digests, err := s.Repo.FindPendingDigests(ctx, after, digestPageSize)
+		if err != nil {
+			return err
+		}
+		for _, digest := range digests {
+			if err := s.sendDigest(ctx, digest, now); err != nil {
+				return err
+			}
+		}
+		if len(digests) < digestPageSize {
+			return nil
+		}
+		after = digests[len(digests)-1].UserID
+	}
+}
+
+// sendDigest envía el resumen del usuario si ya le corresponde y lo marca como enviado.
+func (s *NotificationService) sendDigest(ctx context.Context, digest notificationdomain.Digest, now time.Time) error {
+	prefs, err := s.Repo.GetPreferences(ctx, digest.UserID)
+	if err != nil {
+		fmt.Println("Error obteniendo preferencias:", digest.UserID.Hex(), err)
+		return nil
+	}
+	if !prefs.DigestDue(now, digest.LastSentDay) {
+		return nil
+	}
+
+	jobIDs := make([]primitive.ObjectID, len(digest.Items))
+	for i, item := range digest.Items {
+		jobIDs[i] = item.JobID
+	}
+	if prefs.Allows(notificationdomain.CategoryNewJobs) {
+		title := fmt.Sprintf("%d trabajos nuevos cerca tuyo", len(digest.Items))
+		body := digest.Items[0].Title
+		if len(digest.Items) > 1 {
+			body = fmt.Sprintf("%s y %d más", body, len(digest.Items)-1)
+		}
+		err = s.Push.SendToUsers(ctx, []primitive.ObjectID{digest.UserID}, push.Notification{
+			Title:     title,
+			Body:      body,
+			ChannelID: "jobs",
+		})
+		if err != nil && !errors.Is(err, push.ErrNoTokens) {
+			fmt.Println("Error enviando resumen:", digest.UserID.Hex(), err)
+			return nil
+		}
+	}
+	return s.Repo.MarkDigestSent(ctx, digest.UserID, jobIDs, prefs.LocalDay(now))
+}
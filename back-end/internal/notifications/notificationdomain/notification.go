@@ -0,0 +1,43 @@
+package notificationdomain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationType clasifica las notificaciones del centro de notificaciones.
+type NotificationType string
+
+const (
+	TypeNewApplicant     NotificationType = "new_applicant"     // Nueva postulación a un trabajo propio
+	TypeJobAssigned      NotificationType = "job_assigned"      // El usuario fue asignado a un trabajo
+	TypeJobRequest       NotificationType = "job_request"       // Solicitud directa de trabajo (o su respuesta)
+	TypeMessage          NotificationType = "message"           // Nuevo mensaje de chat
+	TypeFeedbackReceived NotificationType = "feedback_received" // La otra parte dejó una opinión
+	TypePremiumExpiring  NotificationType = "premium_expiring"  // La suscripción premium está por vencer
+	TypeNewJob           NotificationType = "new_job"           // Nuevo trabajo cercano que podría interesar
+	TypeJobReminder      NotificationType = "job_reminder"      // Recordatorios y avisos de vencimiento de trabajos
+	TypeJobAlert         NotificationType = "job_alert"         // Nuevo trabajo que cumple una búsqueda guardada
+	TypeAppointment      NotificationType = "appointment"       // Propuestas, cambios y recordatorios de citas
+	TypeOffer            NotificationType = "offer"             // Contraofertas y ofertas aceptadas de una postulación
+	TypeMention          NotificationType = "mention"           // Otro usuario lo mencionó en un post o comentario
+)
+
+// Notification es una notificación persistida para un usuario.
+type Notification struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
+	Type      NotificationType   `json:"type" bson:"type"`
+	Title     string             `json:"title" bson:"title"`
+	Body      string             `json:"body" bson:"body"`
+	Data      map[string]string  `json:"data,omitempty" bson:"data,omitempty"`
+	Read      bool               `json:"read" bson:"read"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	ReadAt    *time.Time         `json:"readAt,omitempty" bson:"readAt,omitempty"`
+	// SourceID identifica el evento que originó la notificación para no duplicarla en reintentos.
+	SourceID string `json:"-" bson:"sourceId,omitempty"`
+	// PushedAt registra que el push de la notificación ya se envió o se pospuso, para no repetirlo
+	// si el evento se reintenta.
+	PushedAt *time.Time `json:"-" bson:"pushedAt,omitempty"`
+}
@@ -0,0 +1,184 @@
+package notificationdomain
+
+import (
+	"errors"
+	"time"
+	_ "time/tzdata" // Zonas horarias disponibles aunque el contenedor no las traiga
+
+	"github.com/go-playground/validator"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Category agrupa los tipos de notificación que el usuario puede silenciar.
+type Category string
+
+const (
+	CategoryNewJobs     Category = "newJobs"
+	CategoryChat        Category = "chat"
+	CategoryJobRequests Category = "jobRequests"
+	CategoryMarketing   Category = "marketing"
+)
+
+// Preferences son las preferencias de notificaciones push del usuario.
+// Las notificaciones del centro de notificaciones se guardan siempre; estas reglas solo limitan el push.
+type Preferences struct {
+	NewJobs     bool `json:"newJobs" bson:"newJobs"`
+	Chat        bool `json:"chat" bson:"chat"`
+	JobRequests bool `json:"jobRequests" bson:"jobRequests"`
+	Marketing   bool `json:"marketing" bson:"marketing"`
+	// DailyCap es la cantidad máxima de push de nuevos trabajos por día; 0 significa sin límite.
+	DailyCap int `json:"dailyCap" bson:"dailyCap" validate:"min=0,max=100"`
+	// Horario silencioso en horas locales [QuietHoursStart, QuietHoursEnd); si son iguales está desactivado.
+	QuietHoursStart int    `json:"quietHoursStart" bson:"quietHoursStart" validate:"min=0,max=23"`
+	QuietHoursEnd   int    `json:"quietHoursEnd" bson:"quietHoursEnd" validate:"min=0,max=23"`
+	Timezone        string `json:"timezone" bson:"timezone" validate:"max=64"` // Zona IANA, por ejemplo "America/Argentina/Buenos_Aires"
+	// NewJobsDigest agrupa los nuevos trabajos en un único push diario a la hora DigestHour.
+	NewJobsDigest bool `json:"newJobsDigest" bson:"newJobsDigest"`
+	DigestHour    int  `json:"digestHour" bson:"digestHour" validate:"min=0,max=23"`
+}
+
+// DefaultPreferences son las preferencias de los usuarios que nunca las configuraron.
+func DefaultPreferences() Preferences {
+	return Preferences{
+		NewJobs:     true,
+		Chat:        true,
+		JobRequests: true,
+		Marketing:   false,
+		DailyCap:    10,
+		Timezone:    "UTC",
+		DigestHour:  19,
+	}
+}
+
+// PreferencesOrDefault devuelve las preferencias guardadas o las por defecto si no existen.
+func PreferencesOrDefault(p *Preferences) Preferences {
+	if p == nil {
+		return DefaultPreferences()
+	}
+	return *p
+}
+
+// Validate valida los rangos y la zona horaria.
+func (p *Preferences) Validate() error {
+	if err := validator.New().Struct(p); err != nil {
+		return err
+	}
+	if p.Timezone == "" {
+		p.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil {
+		return errors.New("zona horaria inválida")
+	}
+	// El resumen no se envía en horario silencioso: a esa hora nunca llegaría
+	if p.NewJobsDigest && p.quietHour(p.DigestHour) {
+		return errors.New("la hora del resumen no puede estar dentro del horario silencioso")
+	}
+	return nil
+}
+
+// Allows indica si el usuario acepta push de la categoría.
+func (p Preferences) Allows(category Category) bool {
+	switch category {
+	case CategoryNewJobs:
+		return p.NewJobs
+	case CategoryChat:
+		return p.Chat
+	case CategoryJobRequests:
+		return p.JobRequests
+	case CategoryMarketing:
+		return p.Marketing
+	}
+	return true
+}
+
+// Location devuelve la zona horaria del usuario (UTC si no es válida).
+func (p Preferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil || p.Timezone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// InQuietHours indica si now cae dentro del horario silencioso del usuario.
+func (p Preferences) InQuietHours(now time.Time) bool {
+	return p.quietHour(now.In(p.Location()).Hour())
+}
+
+// quietHour indica si la hora local hour está dentro del horario silencioso.
+func (p Preferences) quietHour(hour int) bool {
+	if p.QuietHoursStart == p.QuietHoursEnd {
+		return false
+	}
+	if p.QuietHoursStart < p.QuietHoursEnd {
+		return hour >= p.QuietHoursStart && hour < p.QuietHoursEnd
+	}
+	// El horario cruza la medianoche, por ejemplo de 22 a 8
+	return hour >= p.QuietHoursStart || hour < p.QuietHoursEnd
+}
+
+// QuietHoursEndAfter devuelve el momento en que termina el horario silencioso que incluye now.
+func (p Preferences) QuietHoursEndAfter(now time.Time) time.Time {
+	local := now.In(p.Location())
+	end := time.Date(local.Year(), local.Month(), local.Day(), p.QuietHoursEnd, 0, 0, 0, local.Location())
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// LocalDay devuelve el día calendario del usuario, usado para el límite diario y el resumen.
+func (p Preferences) LocalDay(now time.Time) string {
+	return now.In(p.Location()).Format("2006-01-02")
+}
+
+// DigestDue indica si corresponde enviar el resumen: pasada la hora elegida, fuera del horario
+// silencioso y sin haberlo enviado ya en el día local.
+func (p Preferences) DigestDue(now time.Time, lastSentDay string) bool {
+	return now.In(p.Location()).Hour() >= p.DigestHour &&
+		!p.InQuietHours(now) &&
+		lastSentDay != p.LocalDay(now)
+}
+
+// CategoryForType devuelve la categoría de preferencias de un tipo de notificación.
+// Los tipos sin categoría (asignaciones, opiniones, premium) no se pueden silenciar.
+func CategoryForType(t NotificationType) (Category, bool) {
+	switch t {
+	case TypeNewJob, TypeJobAlert:
+		return CategoryNewJobs, true
+	case TypeMessage:
+		return CategoryChat, true
+	case TypeJobRequest:
+		return CategoryJobRequests, true
+	}
+	return "", false
+}
+
+// DigestItem es un trabajo pendiente de incluir en el resumen de nuevos trabajos.
+type DigestItem struct {
+	JobID primitive.ObjectID `json:"jobId" bson:"jobId"`
+	Title string             `json:"title" bson:"title"`
+	// AlertID es la alerta diaria que encontró el trabajo; vacío si viene del modo resumen de nuevos trabajos.
+	AlertID primitive.ObjectID `json:"alertId,omitempty" bson:"alertId,omitempty"`
+}
+
+// Digest acumula los nuevos trabajos de un usuario con el modo resumen activo.
+type Digest struct {
+	UserID      primitive.ObjectID `bson:"_id"`
+	Items       []DigestItem       `bson:"items"`
+	LastSentDay string             `bson:"lastSentDay"`
+	UpdatedAt   time.Time          `bson:"updatedAt"`
+}
+
+// EventDeferredPush es un push pospuesto hasta que termine el horario silencioso del usuario.
+const EventDeferredPush = "notification.deferred_push"
+
+// DeferredPushEvent es el payload de EventDeferredPush.
+type DeferredPushEvent struct {
+	UserID    primitive.ObjectID `bson:"userId"`
+	Category  Category           `bson:"category,omitempty"` // Vacía si el tipo no se puede silenciar
+	Title     string             `bson:"title"`
+	Body      string             `bson:"body"`
+	Data      map[string]string  `bson:"data,omitempty"`
+	ChannelID string             `bson:"channelId"`
+}
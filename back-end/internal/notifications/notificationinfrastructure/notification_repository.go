@@ -0,0 +1,271 @@
+package notificationinfrastructure
+
+import (
+	"back-end/internal/notifications/notificationdomain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotificationRepository persiste las notificaciones y las publica en Redis para el stream en tiempo real.
+type NotificationRepository struct {
+	mongoClient *mongo.Client
+	redisClient *redis.Client
+}
+
+// NewNotificationRepository crea una nueva instancia de NotificationRepository.
+// redisClient puede ser nil; en ese caso no se publica en tiempo real.
+func NewNotificationRepository(mongoClient *mongo.Client, redisClient *redis.Client) *NotificationRepository {
+	return &NotificationRepository{
+		mongoClient: mongoClient,
+		redisClient: redisClient,
+	}
+}
+
+func (r *NotificationRepository) collection() *mongo.Collection {
+	return r.mongoClient.Database("NEXO-VECINAL").Collection("Notifications")
+}
+
+// EnsureIndexes crea los índices de las notificaciones: el listado por usuario y la unicidad por
+// (usuario, evento de origen) que evita duplicados cuando dos reintentos se cruzan.
+func (r *NotificationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "sourceId", Value: 1}},
+			// Las notificaciones sin sourceId no participan de la unicidad
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"sourceId": bson.M{"$exists": true}}),
+		},
+	})
+	return err
+}
+
+// StreamChannel es el canal de Redis donde se publican las notificaciones de un usuario.
+func StreamChannel(userID primitive.ObjectID) string {
+	return fmt.Sprintf("notifications:user:%s", userID.Hex())
+}
+
+// Create guarda las notificaciones. Las que tienen SourceID se insertan una sola vez por usuario,
+// de modo que un evento reintentado no las duplica.
+func (r *NotificationRepository) Create(ctx context.Context, notifications ...notificationdomain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	now := time.Now()
+	models := make([]mongo.WriteModel, len(notifications))
+	for i := range notifications {
+		n := &notifications[i]
+		n.ID = primitive.NewObjectID()
+		n.Read = false
+		n.CreatedAt = now
+		if n.SourceID == "" {
+			models[i] = mongo.NewInsertOneModel().SetDocument(n)
+			continue
+		}
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"userId": n.UserID, "sourceId": n.SourceID}).
+			SetUpdate(bson.M{"$setOnInsert": n}).
+			SetUpsert(true)
+	}
+
+	result, err := r.collection().BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("error guardando notificaciones: %v", err)
+	}
+
+	// Solo se publican las notificaciones realmente nuevas
+	for i, n := range notifications {
+		_, upserted := result.UpsertedIDs[int64(i)]
+		if n.SourceID == "" || upserted {
+			r.publish(ctx, n)
+		}
+	}
+	return nil
+}
+
+// PushedUsers devuelve cuáles de userIDs ya recibieron el push de la notificación originada por sourceID.
+func (r *NotificationRepository) PushedUsers(ctx context.Context, sourceID string, userIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	pushed := map[primitive.ObjectID]bool{}
+	if len(userIDs) == 0 {
+		return pushed, nil
+	}
+	filter := bson.M{"userId": bson.M{"$in": userIDs}, "sourceId": sourceID, "pushedAt": bson.M{"$exists": true}}
+	cursor, err := r.collection().Find(ctx, filter, options.Find().SetProjection(bson.M{"userId": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		UserID primitive.ObjectID `bson:"userId"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		pushed[doc.UserID] = true
+	}
+	return pushed, nil
+}
+
+// MarkPushed registra que el push de la notificación originada por sourceID ya se envió o se
+// pospuso para userIDs.
+func (r *NotificationRepository) MarkPushed(ctx context.Context, sourceID string, userIDs ...primitive.ObjectID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err := r.collection().UpdateMany(ctx,
+		bson.M{"userId": bson.M{"$in": userIDs}, "sourceId": sourceID},
+		bson.M{"$set": bson.M{"pushedAt": time.Now()}},
+	)
+	return err
+}
+
+func (r *NotificationRepository) publish(ctx context.Context, n notificationdomain.Notification) {
+	if r.redisClient == nil {
+		return
+	}
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return
+	}
+	if err := r.redisClient.Publish(ctx, StreamChannel(n.UserID), payload).Err(); err != nil {
+		fmt.Println("Error publicando notificación:", err)
+	}
+}
+
+// List devuelve las notificaciones del usuario, de a 10 por página y más recientes primero.
+func (r *NotificationRepository) List(ctx context.Context, userID primitive.ObjectID, page int, onlyUnread bool) ([]notificationdomain.Notification, error) {
+	filter := bson.M{"userId": userID}
+	if onlyUnread {
+		filter["read"] = false
+	}
+	opts := options.Find().
+		SetSort(bson.M{"createdAt": -1}).
+		SetSkip(int64((page - 1) * 10)).
+		SetLimit(10)
+	cursor, err := r.collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []notificationdomain.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// UnreadCounts devuelve la cantidad de notificaciones sin leer, en total y por tipo.
+func (r *NotificationRepository) UnreadCounts(ctx context.Context, userID primitive.ObjectID) (int64, map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"userId": userID, "read": false}}},
+		{{Key: "$group", Value: bson.M{"_id": "$type", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Type  string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return 0, nil, err
+	}
+	var total int64
+	byType := map[string]int64{}
+	for _, row := range rows {
+		byType[row.Type] = row.Count
+		total += row.Count
+	}
+	return total, byType, nil
+}
+
+// MarkRead marca como leída una notificación del usuario.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, notificationID primitive.ObjectID) error {
+	now := time.Now()
+	res, err := r.collection().UpdateOne(ctx,
+		bson.M{"_id": notificationID, "userId": userID},
+		bson.M{"$set": bson.M{"read": true, "readAt": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("notificación no encontrada")
+	}
+	return nil
+}
+
+// MarkAllRead marca como leídas todas las notificaciones pendientes del usuario.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	now := time.Now()
+	res, err := r.collection().UpdateMany(ctx,
+		bson.M{"userId": userID, "read": false},
+		bson.M{"$set": bson.M{"read": true, "readAt": now}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// ErrStreamUnavailable indica que no hay Redis configurado para el stream en tiempo real.
+var ErrStreamUnavailable = errors.New("notificaciones en tiempo real no disponibles")
+
+// Subscribe se suscribe al canal de notificaciones del usuario.
+func (r *NotificationRepository) Subscribe(ctx context.Context, userID primitive.ObjectID) (*redis.PubSub, error) {
+	if r.redisClient == nil {
+		return nil, ErrStreamUnavailable
+	}
+	return r.redisClient.Subscribe(ctx, StreamChannel(userID)), nil
+}
+
+// FindUsersWithPremiumExpiring devuelve los usuarios cuya suscripción vence antes de until
+// y que todavía no fueron avisados para ese vencimiento.
+func (r *NotificationRepository) FindUsersWithPremiumExpiring(ctx context.Context, now, until time.Time) ([]PremiumExpiringUser, error) {
+	userColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	filter := bson.M{
+		"Premium.SubscriptionEnd": bson.M{"$gt": now, "$lte": until},
+		"$expr":                   bson.M{"$ne": []interface{}{"$premiumExpiryNotifiedFor", "$Premium.SubscriptionEnd"}},
+	}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "Premium.SubscriptionEnd": 1}).SetLimit(500)
+	cursor, err := userColl.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []PremiumExpiringUser
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// MarkPremiumExpiryNotified registra que el usuario ya fue avisado del vencimiento indicado.
+func (r *NotificationRepository) MarkPremiumExpiryNotified(ctx context.Context, userID primitive.ObjectID, subscriptionEnd time.Time) error {
+	userColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	_, err := userColl.UpdateByID(ctx, userID, bson.M{"$set": bson.M{"premiumExpiryNotifiedFor": subscriptionEnd}})
+	return err
+}
+
+// PremiumExpiringUser es la proyección mínima usada para avisar vencimientos de premium.
+type PremiumExpiringUser struct {
+	ID      primitive.ObjectID `bson:"_id"`
+	Premium struct {
+		SubscriptionEnd time.Time `bson:"SubscriptionEnd"`
+	} `bson:"Premium"`
+}
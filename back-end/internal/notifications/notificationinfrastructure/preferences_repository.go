@@ -0,0 +1,164 @@
+package notificationinfrastructure
+
+import (
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *NotificationRepository) digestCollection() *mongo.Collection {
+	return r.mongoClient.Database("NEXO-VECINAL").Collection("NotificationDigests")
+}
+
+// GetPreferences devuelve las preferencias del usuario o las por defecto si nunca las configuró.
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID primitive.ObjectID) (notificationdomain.Preferences, error) {
+	userColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	var user struct {
+		NotificationPreferences *notificationdomain.Preferences `bson:"notificationPreferences"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"notificationPreferences": 1})
+	err := userColl.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return notificationdomain.Preferences{}, errors.New("usuario no encontrado")
+	}
+	if err != nil {
+		return notificationdomain.Preferences{}, err
+	}
+	return notificationdomain.PreferencesOrDefault(user.NotificationPreferences), nil
+}
+
+// SavePreferences reemplaza las preferencias del usuario.
+func (r *NotificationRepository) SavePreferences(ctx context.Context, userID primitive.ObjectID, prefs notificationdomain.Preferences) error {
+	userColl := r.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	res, err := userColl.UpdateByID(ctx, userID, bson.M{"$set": bson.M{"notificationPreferences": prefs}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("usuario no encontrado")
+	}
+	if !prefs.NewJobsDigest {
+		// Si se desactiva el resumen se descartan los nuevos trabajos acumulados; los de las
+		// alertas diarias siguen pendientes
+		_, err = r.digestCollection().UpdateByID(ctx, userID, bson.M{
+			"$pull": bson.M{"items": bson.M{"alertId": bson.M{"$exists": false}}},
+		})
+	}
+	return err
+}
+
+// PushAllowed indica si se puede enviar ahora el push n de la categoría al usuario. En horario
+// silencioso el push se pospone hasta que termine y devuelve false.
+func (r *NotificationRepository) PushAllowed(ctx context.Context, userID primitive.ObjectID, category notificationdomain.Category, n push.Notification, now time.Time) (bool, error) {
+	prefs, err := r.GetPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !prefs.Allows(category) {
+		return false, nil
+	}
+	if prefs.InQuietHours(now) {
+		return false, r.DeferPush(ctx, userID, category, n, prefs, now)
+	}
+	return true, nil
+}
+
+// DeferPush encola el push n para cuando termine el horario silencioso del usuario.
+func (r *NotificationRepository) DeferPush(ctx context.Context, userID primitive.ObjectID, category notificationdomain.Category, n push.Notification, prefs notificationdomain.Preferences, now time.Time) error {
+	event, err := outbox.NewEvent(notificationdomain.EventDeferredPush, notificationdomain.DeferredPushEvent{
+		UserID:    userID,
+		Category:  category,
+		Title:     n.Title,
+		Body:      n.Body,
+		Data:      n.Data,
+		ChannelID: n.ChannelID,
+	})
+	if err != nil {
+		return err
+	}
+	event.NextAttemptAt = prefs.QuietHoursEndAfter(now)
+	return outbox.NewStore(r.mongoClient).Record(ctx, event)
+}
+
+// takeDailyPushScript consume un push del límite diario una sola vez por origen: KEYS[1] es el
+// contador del día y KEYS[2] el hash con la decisión tomada para cada origen (ARGV[1]).
+var takeDailyPushScript = redis.NewScript(`
+local previous = redis.call("HGET", KEYS[2], ARGV[1])
+if previous then
+	return tonumber(previous)
+end
+local count = redis.call("INCR", KEYS[1])
+redis.call("EXPIRE", KEYS[1], ARGV[3])
+local allowed = 0
+if count <= tonumber(ARGV[2]) then
+	allowed = 1
+end
+redis.call("HSET", KEYS[2], ARGV[1], allowed)
+redis.call("EXPIRE", KEYS[2], ARGV[3])
+return allowed
+`)
+
+// TakeDailyPush consume un push del límite diario del usuario; devuelve false si ya lo alcanzó.
+// Es idempotente por sourceID: un evento reintentado obtiene la misma respuesta sin volver a contar.
+func (r *NotificationRepository) TakeDailyPush(ctx context.Context, userID primitive.ObjectID, prefs notificationdomain.Preferences, sourceID string, now time.Time) (bool, error) {
+	if prefs.DailyCap <= 0 || r.redisClient == nil {
+		return true, nil
+	}
+	day := prefs.LocalDay(now)
+	keys := []string{
+		fmt.Sprintf("notifications:push_count:%s:%s", userID.Hex(), day),
+		fmt.Sprintf("notifications:push_sources:%s:%s", userID.Hex(), day),
+	}
+	allowed, err := takeDailyPushScript.Run(ctx, r.redisClient, keys, sourceID, prefs.DailyCap, int((48 * time.Hour).Seconds())).Int()
+	if err != nil {
+		return false, err
+	}
+	return allowed == 1, nil
+}
+
+// QueueDigest agrega un trabajo al resumen pendiente del usuario.
+func (r *NotificationRepository) QueueDigest(ctx context.Context, userID primitive.ObjectID, item notificationdomain.DigestItem) error {
+	_, err := r.digestCollection().UpdateByID(ctx, userID, bson.M{
+		"$addToSet": bson.M{"items": item},
+		"$set":      bson.M{"updatedAt": time.Now()},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+// FindPendingDigests devuelve hasta limit resúmenes con trabajos pendientes de enviar cuyo _id es
+// mayor que after, ordenados por _id para recorrerlos en páginas.
+func (r *NotificationRepository) FindPendingDigests(ctx context.Context, after primitive.ObjectID, limit int64) ([]notificationdomain.Digest, error) {
+	filter := bson.M{"_id": bson.M{"$gt": after}, "items.0": bson.M{"$exists": true}}
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(limit)
+	cursor, err := r.digestCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var digests []notificationdomain.Digest
+	if err := cursor.All(ctx, &digests); err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
+
+// MarkDigestSent quita del resumen los trabajos enviados y registra el día local del envío.
+// Los trabajos agregados mientras tanto quedan para el siguiente resumen.
+func (r *NotificationRepository) MarkDigestSent(ctx context.Context, userID primitive.ObjectID, jobIDs []primitive.ObjectID, day string) error {
+	_, err := r.digestCollection().UpdateByID(ctx, userID, bson.M{
+		"$pull": bson.M{"items": bson.M{"jobId": bson.M{"$in": jobIDs}}},
+		"$set":  bson.M{"lastSentDay": day, "updatedAt": time.Now()},
+	})
+	return err
+}
@@ -0,0 +1,217 @@
+package notificationinterfaces
+
+import (
+	"back-end/internal/notifications/notificationapplication"
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/pkg/jwt"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationHandler expone los endpoints del centro de notificaciones.
+type NotificationHandler struct {
+	NotificationService *notificationapplication.NotificationService
+}
+
+// NewNotificationHandler crea una nueva instancia de NotificationHandler.
+func NewNotificationHandler(service *notificationapplication.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		NotificationService: service,
+	}
+}
+
+// GetNotifications lista las notificaciones del usuario (GET /notifications?page=1&unread=true).
+func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	userID, err := primitive.ObjectIDFromHex(idValue)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	notifications, err := h.NotificationService.GetNotifications(context.Background(), userID, page, c.Query("unread") == "true")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Error retrieving notifications",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"data":    notifications,
+	})
+}
+
+// GetUnreadCount devuelve los contadores de notificaciones sin leer.
+func (h *NotificationHandler) GetUnreadCount(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	userID, err := primitive.ObjectIDFromHex(idValue)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	total, byType, err := h.NotificationService.GetUnreadCounts(context.Background(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Error retrieving unread count",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"unread":  total,
+		"byType":  byType,
+	})
+}
+
+// MarkRead marca una notificación como leída (POST /notifications/:id/read).
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	userID, err := primitive.ObjectIDFromHex(idValue)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	notificationID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid notification ID",
+		})
+	}
+	if err := h.NotificationService.MarkRead(context.Background(), userID, notificationID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": "Notification not found",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+	})
+}
+
+// MarkAllRead marca todas las notificaciones como leídas (POST /notifications/read-all).
+func (h *NotificationHandler) MarkAllRead(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	userID, err := primitive.ObjectIDFromHex(idValue)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	updated, err := h.NotificationService.MarkAllRead(context.Background(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Error marking notifications as read",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"updated": updated,
+	})
+}
+
+// GetPreferences devuelve las preferencias de notificaciones del usuario.
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	userID, err := primitive.ObjectIDFromHex(idValue)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	prefs, err := h.NotificationService.GetPreferences(context.Background(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "Error retrieving preferences",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+		"data":    prefs,
+	})
+}
+
+// UpdatePreferences reemplaza las preferencias de notificaciones del usuario.
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	idValue := c.Context().UserValue("_id").(string)
+	userID, err := primitive.ObjectIDFromHex(idValue)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid user ID",
+		})
+	}
+	var prefs notificationdomain.Preferences
+	if err := c.BodyParser(&prefs); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+	if err := h.NotificationService.UpdatePreferences(context.Background(), userID, prefs); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Error updating preferences",
+			"error":   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+	})
+}
+
+// Subscribe envía por websocket las notificaciones nuevas del usuario.
+// Como el navegador no permite headers en el upgrade, el token se recibe en la query (?token=).
+func (h *NotificationHandler) Subscribe(c *websocket.Conn) {
+	_, idValue, _, err := jwt.ExtractDataFromToken(c.Query("token"))
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("Unauthorized"))
+		return
+	}
+	userID, err := primitive.ObjectIDFromHex(idValue)
+	if err != nil {
+		c.WriteMessage(websocket.TextMessage, []byte("Invalid user ID"))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pubsub, err := h.NotificationService.Subscribe(ctx, userID)
+	if err != nil {
+		c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, err.Error()))
+		return
+	}
+	defer pubsub.Close()
+
+	// Detectar cuando el cliente cierra la conexión
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			return
+		}
+		if err := c.WriteMessage(websocket.TextMessage, []byte(msg.Payload)); err != nil {
+			fmt.Println("Error al enviar notificación:", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package notificationroutes
+
+import (
+	"back-end/internal/notifications/notificationapplication"
+	"back-end/internal/notifications/notificationinfrastructure"
+	"back-end/internal/notifications/notificationinterfaces"
+	"back-end/pkg/middleware"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NotificationRoutes configura los endpoints del centro de notificaciones.
+func NotificationRoutes(app *fiber.App, redisClient *redis.Client, mongoClient *mongo.Client) {
+	repo := notificationinfrastructure.NewNotificationRepository(mongoClient, redisClient)
+	service := notificationapplication.NewNotificationService(repo, push.NewDefaultService(mongoClient))
+	handler := notificationinterfaces.NewNotificationHandler(service)
+	if err := repo.EnsureIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de notificaciones:", err)
+	}
+
+	dispatcher := outbox.NewDispatcher(outbox.NewStore(mongoClient))
+	service.RegisterOutboxHandlers(dispatcher)
+	dispatcher.Start(5 * time.Second)
+
+	service.StartPremiumExpiryNotifier(6 * time.Hour)
+	service.StartDigestSender(time.Hour)
+
+	notificationsGroup := app.Group("/notifications")
+	notificationsGroup.Get("/", middleware.UseExtractor(), handler.GetNotifications)
+	notificationsGroup.Get("/unread-count", middleware.UseExtractor(), handler.GetUnreadCount)
+	notificationsGroup.Post("/read-all", middleware.UseExtractor(), handler.MarkAllRead)
+	notificationsGroup.Get("/preferences", middleware.UseExtractor(), handler.GetPreferences)
+	notificationsGroup.Put("/preferences", middleware.UseExtractor(), handler.UpdatePreferences)
+	notificationsGroup.Post("/:id/read", middleware.UseExtractor(), handler.MarkRead)
+	notificationsGroup.Get("/subscribe", websocket.New(handler.Subscribe))
+}
@@ -3,8 +3,12 @@ package postapplication
 import (
 	"back-end/internal/posts/postdomain"
 	"back-end/internal/posts/postinfrastructure"
+	"back-end/pkg/mentions"
+	"errors"
+	"slices"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -19,34 +23,120 @@ func NewPostService(repo *postinfrastructure.PostRepository) *PostService {
 }
 
 // CreatePost crea un nuevo post a partir de la información del request y el ID del creador.
+// Si req.PublishAt es una fecha futura, el post queda programado hasta entonces.
 func (ps *PostService) CreatePost(req postdomain.CreatePostRequest, userID primitive.ObjectID) (primitive.ObjectID, error) {
+	publishAt := time.Now()
+	if req.PublishAt != nil {
+		if err := postdomain.ValidatePublishAt(*req.PublishAt, publishAt); err != nil {
+			return primitive.NilObjectID, err
+		}
+		publishAt = *req.PublishAt
+	}
+	if err := req.ValidateLocationCategory(); err != nil {
+		return primitive.NilObjectID, err
+	}
+	// El repositorio modera el texto y extrae las menciones y hashtags
 	newPost := postdomain.Post{
 		UserID:      userID,
 		Title:       req.Title,
 		Description: req.Description,
 		Images:      req.Images,
-		Likes:       []primitive.ObjectID{},
-		Dislikes:    []primitive.ObjectID{},
 		Comments:    []primitive.ObjectID{},
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		Available:   true,
+		PublishAt:   publishAt,
+		Category:    req.Category,
+	}
+	if req.Location != nil {
+		newPost.Location = &postdomain.GeoPoint{Type: "Point", Coordinates: req.Location.Coordinates}
 	}
 
 	return ps.PostRepository.CreatePost(newPost)
 }
+
+// UpdatePost edita un post propio. Se conservan las imágenes de req.KeepImages y se agregan
+// newImages, hasta MaxPostImages. La fecha de publicación solo cambia si el post sigue programado.
+func (ps *PostService) UpdatePost(postID, userID primitive.ObjectID, req postdomain.UpdatePostRequest, newImages []string) error {
+	post, err := ps.PostRepository.GetPost(postID)
+	if err != nil {
+		return err
+	}
+	if post.UserID != userID {
+		return errors.New("unauthorized: only the author can edit this post")
+	}
+
+	images := []string{}
+	for _, image := range req.KeepImages {
+		if !slices.Contains(post.Images, image) {
+			return errors.New("keepImages must reference images of the post")
+		}
+		images = append(images, image)
+	}
+	images = append(images, newImages...)
+	if len(images) > postdomain.MaxPostImages {
+		return errors.New("You can upload a maximum of 3 images")
+	}
+
+	// El repositorio modera el texto y extrae las menciones y hashtags
+	set := bson.M{
+		"title":       req.Title,
+		"description": req.Description,
+		"Images":      images,
+	}
+	if req.PublishAt != nil {
+		now := time.Now()
+		if !post.PublishAt.After(now) {
+			return errors.New("the post is already published")
+		}
+		if err := postdomain.ValidatePublishAt(*req.PublishAt, now); err != nil {
+			return err
+		}
+		set["publishAt"] = *req.PublishAt
+	}
+	if req.Location != nil {
+		set["location"] = postdomain.GeoPoint{Type: "Point", Coordinates: req.Location.Coordinates}
+	}
+	if req.Category != "" {
+		set["category"] = req.Category
+	}
+	return ps.PostRepository.UpdatePost(*post, set)
+}
+
+// DeletePost elimina un post propio.
+func (ps *PostService) DeletePost(postID, userID primitive.ObjectID) error {
+	post, err := ps.PostRepository.GetPost(postID)
+	if err != nil {
+		return err
+	}
+	if post.UserID != userID {
+		return errors.New("unauthorized: only the author can delete this post")
+	}
+	return ps.PostRepository.DeletePostByAuthor(postID, userID)
+}
+
+// GetScheduledPosts devuelve los posts programados del usuario.
+func (ps *PostService) GetScheduledPosts(userID primitive.ObjectID) ([]postdomain.Post, error) {
+	return ps.PostRepository.GetScheduledPosts(userID)
+}
+
 func (ps *PostService) GetPostByID(postID, userID primitive.ObjectID) (*postdomain.PostResponse, error) {
 	return ps.PostRepository.GetPostByID(postID, userID)
 }
 
 // AddLike agrega un like de un usuario al post. Si ya votó, no se permite duplicar.
 func (ps *PostService) AddLike(postID, userID primitive.ObjectID) error {
-	return ps.PostRepository.AddLike(postID, userID)
+	return ps.PostRepository.React(postID, userID, postdomain.ReactionLike)
 }
 
 // AddDislike agrega un dislike del usuario al post.
 func (ps *PostService) Dislike(postID, userID primitive.ObjectID) error {
-	return ps.PostRepository.Dislike(postID, userID)
+	return ps.PostRepository.React(postID, userID, postdomain.ReactionDislike)
+}
+
+// Unreact quita el like o dislike del usuario al post.
+func (ps *PostService) Unreact(postID, userID primitive.ObjectID) error {
+	return ps.PostRepository.Unreact(postID, userID)
 }
 
 // AddComment agrega un comentario al post.
@@ -65,10 +155,102 @@ func (ps *PostService) GetLatestPostsDetailed(currentUserID primitive.ObjectID,
 	return ps.PostRepository.GetLatestPostsDetailed(currentUserID, page, limit)
 }
 
+// GetFeed arma una página del feed de currentUserID y el cursor de la siguiente, si la hay.
+func (ps *PostService) GetFeed(currentUserID primitive.ObjectID, query postdomain.FeedQuery) (*postdomain.FeedPage, error) {
+	viewer, err := ps.PostRepository.GetFeedViewer(currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	posts, err := ps.PostRepository.GetFeed(currentUserID, query, viewer, now)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &postdomain.FeedPage{Posts: posts}
+	if len(posts) > query.Limit {
+		page.Posts = posts[:query.Limit]
+		last := page.Posts[query.Limit-1]
+		next := postdomain.FeedCursor{PublishAt: last.PublishAt, ID: last.ID}
+		if query.Mode == postdomain.FeedTrending {
+			// GetFeed ya validó el instante del cursor
+			anchor, _ := query.Cursor.TrendingAnchor(now)
+			next = postdomain.FeedCursor{Score: last.TrendScore, Now: anchor, ID: last.ID}
+		}
+		page.NextCursor = next.Encode()
+	}
+	if page.Posts == nil {
+		page.Posts = []postdomain.PostResponse{}
+	}
+	return page, nil
+}
+
+// GetPostsByHashtag devuelve los posts publicados con el hashtag.
+func (ps *PostService) GetPostsByHashtag(currentUserID primitive.ObjectID, tag string, page, limit int) ([]postdomain.PostResponse, error) {
+	tag = mentions.NormalizeHashtag(tag)
+	if tag == "" {
+		return nil, errors.New("hashtag required")
+	}
+	return ps.PostRepository.GetPostsByHashtag(currentUserID, tag, page, limit)
+}
+
+// GetMentions devuelve los posts y comentarios donde se mencionó al usuario.
+func (ps *PostService) GetMentions(userID primitive.ObjectID, page, limit int) ([]postdomain.MentionItem, error) {
+	return ps.PostRepository.GetMentions(userID, page, limit)
+}
+
 // postapplication/post_service.go
-func (ps *PostService) GetCommentsForPost(postID primitive.ObjectID, page, limit int) ([]postdomain.CommentResponse, error) {
-	return ps.PostRepository.GetCommentsForPost(postID, page, limit)
+func (ps *PostService) GetCommentsForPost(postID, currentUserID primitive.ObjectID, page, limit int) ([]postdomain.CommentResponse, error) {
+	comments, err := ps.PostRepository.GetCommentsForPost(postID, currentUserID, page, limit)
+	return hideDeletedAuthors(comments), err
+}
+
+// GetCommentReplies devuelve las respuestas directas a un comentario.
+func (ps *PostService) GetCommentReplies(commentID, currentUserID primitive.ObjectID, page, limit int) ([]postdomain.CommentResponse, error) {
+	comments, err := ps.PostRepository.GetCommentReplies(commentID, currentUserID, page, limit)
+	return hideDeletedAuthors(comments), err
+}
+
+func (ps *PostService) GetCommentByID(commentID, currentUserID primitive.ObjectID) (postdomain.CommentResponse, error) {
+	return ps.PostRepository.GetCommentByID(commentID, currentUserID)
 }
-func (ps *PostService) GetCommentByID(postID primitive.ObjectID) (postdomain.CommentResponse, error) {
-	return ps.PostRepository.GetCommentByID(postID)
+
+// EditComment cambia el texto de un comentario; solo lo puede hacer su autor.
+func (ps *PostService) EditComment(commentID, userID primitive.ObjectID, text string) error {
+	comment, err := ps.PostRepository.GetComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		return errors.New("unauthorized: only the author can edit this comment")
+	}
+	return ps.PostRepository.EditComment(*comment, text)
+}
+
+// DeleteComment borra un comentario del autor conservando la forma del hilo.
+func (ps *PostService) DeleteComment(commentID, userID primitive.ObjectID) error {
+	comment, err := ps.PostRepository.GetComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		return errors.New("unauthorized: only the author can delete this comment")
+	}
+	return ps.PostRepository.DeleteComment(commentID, userID)
+}
+
+// LikeComment agrega o quita el like del usuario en un comentario.
+func (ps *PostService) LikeComment(commentID, userID primitive.ObjectID, like bool) error {
+	return ps.PostRepository.LikeComment(commentID, userID, like)
+}
+
+// hideDeletedAuthors quita el autor de los comentarios borrados: solo queda su lugar en el hilo.
+func hideDeletedAuthors(comments []postdomain.CommentResponse) []postdomain.CommentResponse {
+	for i := range comments {
+		if comments[i].Deleted {
+			comments[i].UserID = primitive.NilObjectID
+			comments[i].UserDetail = postdomain.User{}
+		}
+	}
+	return comments
 }
@@ -0,0 +1,119 @@
+package postdomain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GeoPoint es una ubicación GeoJSON (longitud, latitud), como la de usuarios y trabajos.
+type GeoPoint struct {
+	Type        string    `json:"type" bson:"type"`
+	Coordinates []float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// Validate comprueba que sea un punto con longitud y latitud válidas.
+func (p GeoPoint) Validate() error {
+	if len(p.Coordinates) != 2 {
+		return errors.New("location must have [longitude, latitude]")
+	}
+	lon, lat := p.Coordinates[0], p.Coordinates[1]
+	if lon < -180 || lon > 180 || lat < -90 || lat > 90 {
+		return errors.New("location coordinates out of range")
+	}
+	return nil
+}
+
+// Categorías de los posts del barrio.
+const (
+	CategoryGeneral        = "general"
+	CategoryRecommendation = "recomendacion"
+	CategoryLostFound      = "perdidos"
+	CategorySafety         = "seguridad"
+	CategoryEvent          = "evento"
+	CategorySale           = "venta"
+)
+
+// FeedMode es la forma de armar el feed de la comunidad.
+type FeedMode string
+
+const (
+	FeedLatest    FeedMode = "latest"    // Los más nuevos de toda la comunidad
+	FeedNearby    FeedMode = "nearby"    // Los publicados dentro del radio del usuario
+	FeedFollowing FeedMode = "following" // Los de los usuarios que sigue (y los propios)
+	FeedTrending  FeedMode = "trending"  // Los más activos de la última semana cerca del usuario
+)
+
+// Valid indica si el modo es uno de los conocidos.
+func (m FeedMode) Valid() bool {
+	switch m {
+	case FeedLatest, FeedNearby, FeedFollowing, FeedTrending:
+		return true
+	}
+	return false
+}
+
+// DefaultFeedRadiusMeters se usa cuando el usuario no guardó su radio.
+const DefaultFeedRadiusMeters = 5000
+
+// TrendingWindow es la antigüedad máxima de los posts del modo trending.
+const TrendingWindow = 7 * 24 * time.Hour
+
+// FeedQuery es el pedido de una página del feed.
+type FeedQuery struct {
+	Mode     FeedMode
+	Category string
+	Cursor   *FeedCursor // nil para la primera página
+	Limit    int
+}
+
+// FeedCursor marca dónde terminó la página anterior. En los modos cronológicos se ordena por
+// (PublishAt, ID); en trending por (Score, ID), calculando el puntaje siempre en Now para que
+// no cambie entre páginas.
+type FeedCursor struct {
+	PublishAt time.Time          `json:"p,omitempty"`
+	Score     float64            `json:"s,omitempty"`
+	Now       time.Time          `json:"n,omitempty"`
+	ID        primitive.ObjectID `json:"i"`
+}
+
+// Encode devuelve el cursor como un token opaco para el cliente.
+func (c FeedCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeFeedCursor interpreta un token de Encode.
+func DecodeFeedCursor(token string) (*FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var cursor FeedCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID.IsZero() {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// TrendingAnchor devuelve el instante en que se calculan los puntajes de trending: el del cursor o now
+// en la primera página. El cursor lo arma el cliente, así que su instante debe caer dentro de la
+// ventana de trending y no puede ser posterior a now.
+func (c *FeedCursor) TrendingAnchor(now time.Time) (time.Time, error) {
+	if c == nil || c.Now.IsZero() {
+		return now, nil
+	}
+	if c.Now.After(now) || c.Now.Before(now.Add(-TrendingWindow)) {
+		return time.Time{}, errors.New("invalid cursor")
+	}
+	return c.Now, nil
+}
+
+// FeedPage es una página del feed; NextCursor está vacío cuando no hay más.
+type FeedPage struct {
+	Posts      []PostResponse `json:"posts"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
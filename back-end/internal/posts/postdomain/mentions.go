@@ -0,0 +1,35 @@
+package postdomain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EventUserMentioned es el evento del outbox que notifica a un usuario mencionado en un post o comentario.
+const EventUserMentioned = "post.user_mentioned"
+
+// MentionEvent es el payload de EventUserMentioned.
+type MentionEvent struct {
+	UserID    primitive.ObjectID  `bson:"userId"`   // Usuario mencionado
+	AuthorID  primitive.ObjectID  `bson:"authorId"` // Quien lo mencionó
+	PostID    primitive.ObjectID  `bson:"postId"`
+	CommentID *primitive.ObjectID `bson:"commentId,omitempty"` // Vacío si la mención está en el post
+	Text      string              `bson:"text"`
+}
+
+// Tipos de lugar donde se puede mencionar a un usuario.
+const (
+	MentionInPost    = "post"
+	MentionInComment = "comment"
+)
+
+// MentionItem es un post o comentario donde se mencionó al usuario.
+type MentionItem struct {
+	Kind       string              `json:"kind" bson:"kind"` // MentionInPost o MentionInComment
+	PostID     primitive.ObjectID  `json:"postId" bson:"postId"`
+	CommentID  *primitive.ObjectID `json:"commentId,omitempty" bson:"commentId,omitempty"`
+	Text       string              `json:"text" bson:"text"`
+	CreatedAt  time.Time           `json:"createdAt" bson:"createdAt"`
+	UserDetail User                `json:"userDetail" bson:"userDetail"` // Autor del post o comentario
+}
@@ -1,8 +1,11 @@
 package postdomain
 
 import (
+	"back-end/pkg/mentions"
+	"errors"
 	"time"
 
+	"github.com/go-playground/validator"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -14,30 +17,88 @@ type Post struct {
 	Description string             `json:"description" bson:"description"`
 	Images      []string           `json:"Images" bson:"Images"`
 	// En lugar de guardar los comentarios completos, almacenamos sus IDs.
-	Comments  []primitive.ObjectID `json:"comments" bson:"comments"`
-	Likes     []primitive.ObjectID `json:"likes" bson:"likes"`
-	Dislikes  []primitive.ObjectID `json:"dislikes" bson:"dislikes"`
-	CreatedAt time.Time            `json:"createdAt" bson:"createdAt"`
-	UpdatedAt time.Time            `json:"updatedAt" bson:"updatedAt"`
-	Available bool                 `json:"Available" bson:"available"`
+	Comments []primitive.ObjectID `json:"comments" bson:"comments"`
+	// Contadores de reacciones; el detalle por usuario está en la colección PostReactions
+	LikeCount    int       `json:"likeCount" bson:"likeCount"`
+	DislikeCount int       `json:"dislikeCount" bson:"dislikeCount"`
+	CreatedAt    time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt" bson:"updatedAt"`
+	Available    bool      `json:"Available" bson:"available"`
+	// PublishAt es cuándo aparece en el feed
+	PublishAt time.Time  `json:"publishAt" bson:"publishAt"`
+	EditedAt  *time.Time `json:"editedAt,omitempty" bson:"editedAt,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"` // Borrado por su autor
+	// Ubicación y categoría opcionales, para el feed del barrio
+	Location *GeoPoint `json:"location,omitempty" bson:"location,omitempty"`
+	Category string    `json:"category,omitempty" bson:"category,omitempty"`
+	// Menciones y hashtags del título y la descripción
+	mentions.Entities `bson:",inline"`
+	// Retenido por la moderación automática; no está disponible hasta que lo apruebe un administrador
+	Held bool `json:"held,omitempty" bson:"held,omitempty"`
 }
 
-// Comment representa un comentario en un post.
+// MaxPostImages es la cantidad máxima de imágenes de un post.
+const MaxPostImages = 3
+
+// MaxScheduleAhead es cuánto se puede programar un post hacia adelante.
+const MaxScheduleAhead = 30 * 24 * time.Hour
+
+// ValidatePublishAt comprueba que la fecha de publicación programada sea futura y no demasiado lejana.
+func ValidatePublishAt(publishAt, now time.Time) error {
+	if !publishAt.After(now) {
+		return errors.New("la fecha de publicación debe ser futura")
+	}
+	if publishAt.Sub(now) > MaxScheduleAhead {
+		return errors.New("la publicación se puede programar hasta 30 días adelante")
+	}
+	return nil
+}
+
+// Comment representa un comentario en un post. Las respuestas guardan el comentario al que
+// responden en ParentID; los comentarios de primer nivel no tienen ParentID.
 type Comment struct {
-	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	PostID    primitive.ObjectID `json:"postId" bson:"postId"`
-	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
-	Text      string             `json:"text" bson:"text"`
-	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	ID         primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	PostID     primitive.ObjectID  `json:"postId" bson:"postId"`
+	UserID     primitive.ObjectID  `json:"userId" bson:"userId"`
+	ParentID   *primitive.ObjectID `json:"parentId,omitempty" bson:"parentId,omitempty"`
+	Text       string              `json:"text" bson:"text"`
+	LikeCount  int                 `json:"likeCount" bson:"likeCount"` // Los likes están en CommentReactions
+	ReplyCount int                 `json:"replyCount" bson:"replyCount"`
+	CreatedAt  time.Time           `json:"createdAt" bson:"createdAt"`
+	EditedAt   *time.Time          `json:"editedAt,omitempty" bson:"editedAt,omitempty"`
+	// Los comentarios borrados conservan su lugar en el hilo, sin texto ni autor
+	Deleted           bool       `json:"deleted" bson:"deleted"`
+	DeletedAt         *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	mentions.Entities `bson:",inline"`
+	// Retenido por la moderación automática; no se muestra ni cuenta hasta que lo apruebe un administrador
+	Held bool `json:"held,omitempty" bson:"held,omitempty"`
+}
+
+// CommentRequest es el cuerpo para crear o editar un comentario.
+type CommentRequest struct {
+	Text     string              `json:"text" validate:"required,min=1,max=500"`
+	ParentID *primitive.ObjectID `json:"parentId,omitempty"` // Comentario al que responde (opcional)
+}
+
+func (req CommentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(req)
 }
 
 // CommentResponse es la estructura que se devolverá al obtener comentarios, con el detalle del usuario.
 type CommentResponse struct {
-	ID         primitive.ObjectID `json:"id"`
-	UserID     primitive.ObjectID `json:"userId"`
-	Text       string             `json:"text"`
-	CreatedAt  time.Time          `json:"createdAt"`
-	UserDetail User               `json:"userDetail"`
+	ID                primitive.ObjectID  `json:"id" bson:"_id"`
+	UserID            primitive.ObjectID  `json:"userId" bson:"userId"`
+	ParentID          *primitive.ObjectID `json:"parentId,omitempty" bson:"parentId,omitempty"`
+	Text              string              `json:"text" bson:"text"`
+	CreatedAt         time.Time           `json:"createdAt" bson:"createdAt"`
+	EditedAt          *time.Time          `json:"editedAt,omitempty" bson:"editedAt,omitempty"`
+	Deleted           bool                `json:"deleted" bson:"deleted"`
+	ReplyCount        int                 `json:"replyCount" bson:"replyCount"`
+	LikeCount         int                 `json:"likeCount" bson:"likeCount"`
+	UserLiked         bool                `json:"userLiked" bson:"userLiked"`
+	UserDetail        User                `json:"userDetail" bson:"userDetail"`
+	mentions.Entities `bson:",inline"`
 }
 
 type User struct {
@@ -46,24 +107,97 @@ type User struct {
 	Avatar   string             `json:"avatar" bson:"Avatar"`
 }
 type PostResponse struct {
-	ID           primitive.ObjectID `json:"id" bson:"_id"`
-	UserID       primitive.ObjectID `json:"userId" bson:"userId"`
-	Title        string             `json:"title" bson:"title"`
-	Description  string             `json:"description" bson:"description"`
-	Images       []string           `json:"Images" bson:"Images"`
-	LikeCount    int                `json:"likeCount"`
-	DislikeCount int                `json:"dislikeCount"`
-	CommentCount int                `json:"commentCount"`
-	UserLiked    bool               `json:"userLiked"`
-	UserDisliked bool               `json:"userDisliked"`
-	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+	ID                primitive.ObjectID `json:"id" bson:"_id"`
+	UserID            primitive.ObjectID `json:"userId" bson:"userId"`
+	Title             string             `json:"title" bson:"title"`
+	Description       string             `json:"description" bson:"description"`
+	Images            []string           `json:"Images" bson:"Images"`
+	LikeCount         int                `json:"likeCount" bson:"likeCount"`
+	DislikeCount      int                `json:"dislikeCount" bson:"dislikeCount"`
+	CommentCount      int                `json:"commentCount" bson:"commentCount"`
+	UserLiked         bool               `json:"userLiked" bson:"userLiked"`
+	UserDisliked      bool               `json:"userDisliked" bson:"userDisliked"`
+	CreatedAt         time.Time          `json:"createdAt" bson:"createdAt"`
+	PublishAt         time.Time          `json:"publishAt" bson:"publishAt"`
+	EditedAt          *time.Time         `json:"editedAt,omitempty" bson:"editedAt,omitempty"`
+	Scheduled         bool               `json:"scheduled" bson:"scheduled"` // Todavía no se publicó
+	Location          *GeoPoint          `json:"location,omitempty" bson:"location,omitempty"`
+	Category          string             `json:"category,omitempty" bson:"category,omitempty"`
+	TrendScore        float64            `json:"-" bson:"trendScore,omitempty"` // Solo en el modo trending, para el cursor
+	mentions.Entities `bson:",inline"`
 	// Datos del creador
-	UserDetails User `json:"userDetails,omitempty"`
+	UserDetails User `json:"userDetails,omitempty" bson:"userDetails"`
+}
+
+// ReactionType es el tipo de reacción de un usuario a un post.
+type ReactionType string
+
+const (
+	ReactionLike    ReactionType = "like"
+	ReactionDislike ReactionType = "dislike"
+)
+
+// CounterField devuelve el contador del post que lleva la cuenta de este tipo de reacción.
+func (t ReactionType) CounterField() string {
+	return string(t) + "Count"
+}
+
+// Reaction es la reacción de un usuario a un post. Un usuario tiene a lo sumo una por post.
+type Reaction struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	PostID    primitive.ObjectID `json:"postId" bson:"postId"`
+	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
+	Type      ReactionType       `json:"type" bson:"type"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CommentReaction es el like de un usuario a un comentario. Un usuario tiene a lo sumo uno por comentario.
+type CommentReaction struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CommentID primitive.ObjectID `json:"commentId" bson:"commentId"`
+	UserID    primitive.ObjectID `json:"userId" bson:"userId"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
 }
 
 // CreatePostRequest se usa para crear un nuevo post.
 type CreatePostRequest struct {
-	Title       string   `json:"title" bson:"title" validate:"required,min=3,max=100"`
-	Description string   `json:"description" bson:"description" validate:"required,min=5,max=1000"`
-	Images      []string `json:"Images" bson:"Images" validate:"required,dive,url,max=3"` // Se valida que se envíen hasta 3 URLs válidas
+	Title       string     `json:"title" bson:"title" validate:"required,min=3,max=100"`
+	Description string     `json:"description" bson:"description" validate:"required,min=5,max=1000"`
+	Images      []string   `json:"Images" bson:"Images" validate:"required,dive,url,max=3"` // Se valida que se envíen hasta 3 URLs válidas
+	PublishAt   *time.Time `json:"publishAt,omitempty" form:"-" bson:"-"`                   // Publicación programada (opcional)
+	Location    *GeoPoint  `json:"location,omitempty" form:"-" bson:"-"`                    // En multipart llega como JSON en "location"
+	Category    string     `json:"category" form:"category" bson:"-"`
+}
+
+// ValidateLocationCategory valida la ubicación y la categoría opcionales del post.
+func (req CreatePostRequest) ValidateLocationCategory() error {
+	if req.Location != nil {
+		if err := req.Location.Validate(); err != nil {
+			return err
+		}
+	}
+	validate := validator.New()
+	return validate.Var(req.Category, "omitempty,oneof=general recomendacion perdidos seguridad evento venta")
+}
+
+// UpdatePostRequest se usa para editar un post propio. Las imágenes que no estén en KeepImages
+// se quitan; las nuevas llegan como archivos.
+type UpdatePostRequest struct {
+	Title       string     `json:"title" form:"title" validate:"required,min=3,max=100"`
+	Description string     `json:"description" form:"description" validate:"required,min=5,max=1000"`
+	KeepImages  []string   `json:"keepImages" form:"keepImages" validate:"max=3,dive,url"`
+	PublishAt   *time.Time `json:"publishAt,omitempty" form:"-"` // Solo se puede cambiar si todavía no se publicó
+	Location    *GeoPoint  `json:"location,omitempty" form:"-"`  // nil mantiene la ubicación actual
+	Category    string     `json:"category" form:"category" validate:"omitempty,oneof=general recomendacion perdidos seguridad evento venta"`
+}
+
+func (req UpdatePostRequest) Validate() error {
+	if req.Location != nil {
+		if err := req.Location.Validate(); err != nil {
+			return err
+		}
+	}
+	validate := validator.New()
+	return validate.Struct(req)
 }
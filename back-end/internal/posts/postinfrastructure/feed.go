@@ -0,0 +1,177 @@
+package postinfrastructure
+
+import (
+	"back-end/internal/posts/postdomain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// earthRadiusMeters convierte el radio del usuario a radianes para $centerSphere.
+const earthRadiusMeters = 6378100
+
+// FeedViewer son los datos del usuario que arman su feed.
+type FeedViewer struct {
+	Location     *postdomain.GeoPoint
+	RadiusMeters float64
+	Following    []primitive.ObjectID
+}
+
+// BackfillPublishAt completa publishAt con createdAt en los posts anteriores a la programación,
+// para que el feed pueda ordenar y paginar por ese campo.
+func (pr *PostRepository) BackfillPublishAt(ctx context.Context) (int64, error) {
+	result, err := pr.getCollection().UpdateMany(ctx,
+		bson.M{"publishAt": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"publishAt": "$createdAt"}}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// GetFeedViewer lee la ubicación, el radio y los seguidos del usuario.
+func (pr *PostRepository) GetFeedViewer(userID primitive.ObjectID) (*FeedViewer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user struct {
+		Location  *postdomain.GeoPoint   `bson:"location"`
+		Ratio     float64                `bson:"ratio"`
+		Following map[string]interface{} `bson:"Following"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"location": 1, "ratio": 1, "Following": 1})
+	err := pr.mongoClient.Database("NEXO-VECINAL").Collection("Users").FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	viewer := &FeedViewer{RadiusMeters: user.Ratio}
+	if viewer.RadiusMeters <= 0 {
+		viewer.RadiusMeters = postdomain.DefaultFeedRadiusMeters
+	}
+	if user.Location != nil && user.Location.Validate() == nil {
+		viewer.Location = user.Location
+	}
+	for key := range user.Following {
+		if id, err := primitive.ObjectIDFromHex(key); err == nil {
+			viewer.Following = append(viewer.Following, id)
+		}
+	}
+	return viewer, nil
+}
+
+// withinRadius es la condición sobre location de los posts dentro del radio del usuario.
+func withinRadius(viewer *FeedViewer) bson.M {
+	return bson.M{"$geoWithin": bson.M{
+		"$centerSphere": bson.A{viewer.Location.Coordinates, viewer.RadiusMeters / earthRadiusMeters},
+	}}
+}
+
+// GetFeed devuelve hasta query.Limit+1 posts del feed según el modo; el elemento extra indica
+// que hay otra página. En trending cada post trae su trendScore para armar el cursor.
+func (pr *PostRepository) GetFeed(currentUserID primitive.ObjectID, query postdomain.FeedQuery, viewer *FeedViewer, now time.Time) ([]postdomain.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	match := bson.D{
+		{Key: "available", Value: true},
+		{Key: "publishAt", Value: bson.M{"$lte": now}},
+	}
+	if query.Category != "" {
+		match = append(match, bson.E{Key: "category", Value: query.Category})
+	}
+
+	switch query.Mode {
+	case postdomain.FeedNearby:
+		if viewer.Location == nil {
+			return nil, errors.New("location required")
+		}
+		match = append(match, bson.E{Key: "location", Value: withinRadius(viewer)})
+	case postdomain.FeedFollowing:
+		authors := append([]primitive.ObjectID{currentUserID}, viewer.Following...)
+		match = append(match, bson.E{Key: "userId", Value: bson.M{"$in": authors}})
+	case postdomain.FeedTrending:
+		// En trending todas las páginas se calculan en el mismo instante que la primera; nunca se
+		// muestran posts programados, aunque el instante del cursor sea posterior
+		anchor, err := query.Cursor.TrendingAnchor(now)
+		if err != nil {
+			return nil, err
+		}
+		match[1] = bson.E{Key: "publishAt", Value: bson.M{"$lte": minTime(now, anchor), "$gte": anchor.Add(-postdomain.TrendingWindow)}}
+		if viewer.Location != nil {
+			match = append(match, bson.E{Key: "location", Value: withinRadius(viewer)})
+		}
+	}
+
+	var pipeline mongo.Pipeline
+	if query.Mode == postdomain.FeedTrending {
+		anchor, _ := query.Cursor.TrendingAnchor(now)
+		pipeline = trendingStages(match, query.Cursor, anchor)
+	} else {
+		if query.Cursor != nil {
+			match = append(match, bson.E{Key: "$or", Value: bson.A{
+				bson.M{"publishAt": bson.M{"$lt": query.Cursor.PublishAt}},
+				bson.M{"publishAt": query.Cursor.PublishAt, "_id": bson.M{"$lt": query.Cursor.ID}},
+			}})
+		}
+		pipeline = mongo.Pipeline{
+			{{Key: "$match", Value: match}},
+			{{Key: "$sort", Value: bson.D{{Key: "publishAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		}
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$limit", Value: query.Limit + 1}})
+	pipeline = append(pipeline, postDetailStages(currentUserID)...)
+
+	cursor, err := pr.getCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []postdomain.PostResponse
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// trendingStages puntúa los posts por actividad reciente: (likes + 2·comentarios + 1) / (horas + 2)^1.5.
+func trendingStages(match bson.D, cursor *postdomain.FeedCursor, now time.Time) mongo.Pipeline {
+	ageHours := bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{now, "$publishAt"}}, 3600000}}
+	activity := bson.M{"$add": bson.A{
+		bson.M{"$ifNull": bson.A{"$likeCount", 0}},
+		bson.M{"$multiply": bson.A{2, bson.M{"$size": bson.M{"$ifNull": bson.A{"$comments", bson.A{}}}}}},
+		1,
+	}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{"trendScore": bson.M{"$divide": bson.A{
+			activity,
+			bson.M{"$pow": bson.A{bson.M{"$add": bson.A{bson.M{"$max": bson.A{ageHours, 0}}, 2}}, 1.5}},
+		}}}}},
+	}
+	if cursor != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"trendScore": bson.M{"$lt": cursor.Score}},
+			bson.M{"trendScore": cursor.Score, "_id": bson.M{"$lt": cursor.ID}},
+		}}}})
+	}
+	return append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "trendScore", Value: -1}, {Key: "_id", Value: -1}}}})
+}
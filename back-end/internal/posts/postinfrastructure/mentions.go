@@ -0,0 +1,206 @@
+package postinfrastructure
+
+import (
+	"back-end/internal/notifications/notificationdomain"
+	"back-end/internal/posts/postdomain"
+	"back-end/pkg/mentions"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mentionEvents arma un aviso por cada usuario mencionado, salvo el propio autor. Los avisos
+// no salen antes de at, la publicación del post.
+func mentionEvents(authorID, postID primitive.ObjectID, commentID *primitive.ObjectID, text string, mentioned []mentions.Mention, at time.Time) ([]outbox.Event, error) {
+	var events []outbox.Event
+	for _, mention := range mentioned {
+		if mention.UserID == authorID {
+			continue
+		}
+		event, err := outbox.NewEvent(postdomain.EventUserMentioned, postdomain.MentionEvent{
+			UserID:    mention.UserID,
+			AuthorID:  authorID,
+			PostID:    postID,
+			CommentID: commentID,
+			Text:      text,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if at.After(event.NextAttemptAt) {
+			event.NextAttemptAt = at
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// RegisterOutboxHandlers registra la notificación y el push de las menciones.
+func (pr *PostRepository) RegisterOutboxHandlers(d *outbox.Dispatcher) {
+	d.Handle(postdomain.EventUserMentioned, func(ctx context.Context, event outbox.Event) error {
+		var payload postdomain.MentionEvent
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		// No se avisa de menciones en posts o comentarios que ya no se ven
+		visible, err := pr.mentionVisible(ctx, payload)
+		if err != nil || !visible {
+			return err
+		}
+		var author struct {
+			NameUser string `bson:"NameUser"`
+		}
+		err = pr.mongoClient.Database("NEXO-VECINAL").Collection("Users").FindOne(ctx, bson.M{"_id": payload.AuthorID}).Decode(&author)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+
+		title := fmt.Sprintf("%s te mencionó en un post", author.NameUser)
+		data := map[string]string{"postId": payload.PostID.Hex(), "authorId": payload.AuthorID.Hex()}
+		if payload.CommentID != nil {
+			title = fmt.Sprintf("%s te mencionó en un comentario", author.NameUser)
+			data["commentId"] = payload.CommentID.Hex()
+		}
+		err = pr.notifications.Create(ctx, notificationdomain.Notification{
+			UserID:   payload.UserID,
+			Type:     notificationdomain.TypeMention,
+			Title:    title,
+			Body:     payload.Text,
+			Data:     data,
+			SourceID: event.ID.Hex(),
+		})
+		if err != nil {
+			return err
+		}
+		err = pr.push.SendToUsers(ctx, []primitive.ObjectID{payload.UserID}, push.Notification{
+			Title:     title,
+			Body:      payload.Text,
+			Data:      data,
+			ChannelID: "posts",
+		})
+		if errors.Is(err, push.ErrNoTokens) {
+			return nil
+		}
+		return err
+	})
+}
+
+// mentionVisible indica si el post de la mención sigue disponible y ya está publicado y, si la
+// mención está en un comentario, si ese comentario no fue borrado ni retenido.
+func (pr *PostRepository) mentionVisible(ctx context.Context, payload postdomain.MentionEvent) (bool, error) {
+	postFilter := publishedFilter(time.Now())
+	postFilter["_id"] = payload.PostID
+	postFilter["available"] = true
+	count, err := pr.getCollection().CountDocuments(ctx, postFilter)
+	if err != nil || count == 0 {
+		return false, err
+	}
+	if payload.CommentID == nil {
+		return true, nil
+	}
+	count, err = pr.getCommentsCollection().CountDocuments(ctx, bson.M{
+		"_id":     *payload.CommentID,
+		"deleted": bson.M{"$ne": true},
+		"held":    bson.M{"$ne": true},
+	})
+	return count > 0, err
+}
+
+// GetPostsByHashtag devuelve los posts publicados con el hashtag, los más nuevos primero.
+func (pr *PostRepository) GetPostsByHashtag(currentUserID primitive.ObjectID, tag string, page, limit int) ([]postdomain.PostResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"hashtags":  tag,
+			"available": true,
+			"publishAt": bson.M{"$lte": time.Now()},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "publishAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$skip", Value: (page - 1) * limit}},
+		{{Key: "$limit", Value: limit}},
+	}
+	pipeline = append(pipeline, postDetailStages(currentUserID)...)
+
+	cursor, err := pr.getCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	posts := []postdomain.PostResponse{}
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetMentions devuelve los posts publicados y los comentarios vigentes donde se mencionó a
+// userID, los más recientes primero.
+func (pr *PostRepository) GetMentions(userID primitive.ObjectID, page, limit int) ([]postdomain.MentionItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"mentions.userId": userID,
+			"available":       true,
+			"publishAt":       bson.M{"$lte": now},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"kind":      bson.M{"$literal": postdomain.MentionInPost},
+			"postId":    "$_id",
+			"text":      "$title",
+			"userId":    1,
+			"createdAt": "$publishAt",
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": "Comments",
+			"pipeline": mongo.Pipeline{
+				{{Key: "$match", Value: bson.M{"mentions.userId": userID, "deleted": bson.M{"$ne": true}, "held": bson.M{"$ne": true}}}},
+				{{Key: "$project", Value: bson.M{
+					"_id":       0,
+					"kind":      bson.M{"$literal": postdomain.MentionInComment},
+					"postId":    1,
+					"commentId": "$_id",
+					"text":      1,
+					"userId":    1,
+					"createdAt": 1,
+				}}},
+			},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}}}},
+		{{Key: "$skip", Value: (page - 1) * limit}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "Users"},
+			{Key: "localField", Value: "userId"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "userDetailsArr"},
+		}}},
+		{{Key: "$addFields", Value: bson.M{"userDetail": bson.M{"$first": "$userDetailsArr"}}}},
+		{{Key: "$project", Value: bson.M{"userDetailsArr": 0}}},
+	}
+
+	cursor, err := pr.getCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	items := []postdomain.MentionItem{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -1,7 +1,13 @@
 package postinfrastructure
 
 import (
+	"back-end/internal/notifications/notificationinfrastructure"
 	"back-end/internal/posts/postdomain"
+	"back-end/pkg/mentions"
+	"back-end/pkg/moderation"
+	"back-end/pkg/outbox"
+	"back-end/pkg/push"
+	"back-end/pkg/sanctions"
 	"context"
 	"errors"
 	"time"
@@ -16,12 +22,24 @@ import (
 type PostRepository struct {
 	redisClient *redis.Client
 	mongoClient *mongo.Client
+	outbox      *outbox.Store
+	push        *push.Service
+	moderator   *moderation.Moderator
+	sanctions   *sanctions.Store
+
+	notifications *notificationinfrastructure.NotificationRepository
 }
 
 func NewPostRepository(redisClient *redis.Client, mongoClient *mongo.Client) *PostRepository {
 	return &PostRepository{
 		redisClient: redisClient,
 		mongoClient: mongoClient,
+		outbox:      outbox.NewStore(mongoClient),
+		push:        push.NewDefaultService(mongoClient),
+		moderator:   moderation.NewDefaultModerator(mongoClient),
+		sanctions:   sanctions.NewStore(mongoClient),
+
+		notifications: notificationinfrastructure.NewNotificationRepository(mongoClient, redisClient),
 	}
 }
 
@@ -29,29 +47,73 @@ func (pr *PostRepository) getCollection() *mongo.Collection {
 	return pr.mongoClient.Database("NEXO-VECINAL").Collection("Posts")
 }
 
+// CreatePost verifica que el autor no tenga sanciones que le impidan publicar, pasa el post por
+// la moderación y lo guarda junto con los avisos a los usuarios mencionados. Un post retenido se guarda no disponible,
+// con los avisos pendientes de aprobación, y devuelve moderation.ErrHeld.
 func (pr *PostRepository) CreatePost(post postdomain.Post) (primitive.ObjectID, error) {
 	collection := pr.getCollection()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	result, err := collection.InsertOne(ctx, post)
+	if err := pr.sanctions.Check(ctx, post.UserID, sanctions.ScopePosting); err != nil {
+		return primitive.NilObjectID, err
+	}
+	if post.ID.IsZero() {
+		post.ID = primitive.NewObjectID()
+	}
+	decision, err := pr.moderator.Review(ctx, moderation.ContentPost, &post.Title, &post.Description)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if err := decision.Err(); err != nil {
+		return primitive.NilObjectID, err
+	}
+	// Las entidades salen del texto ya enmascarado
+	if post.Entities, err = mentions.Extract(ctx, pr.mongoClient, post.Title, post.Description); err != nil {
+		return primitive.NilObjectID, err
+	}
+	if decision.Held() {
+		post.Available = false
+		post.Held = true
+	}
+	// Los avisos de un post retenido quedan en su reporte y se registran si un admin lo aprueba
+	events, err := mentionEvents(post.UserID, post.ID, nil, post.Title, post.Mentions, post.PublishAt)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	err = pr.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		if _, err := collection.InsertOne(sessCtx, post); err != nil {
+			return err
+		}
+		if decision.Held() {
+			return pr.moderator.Hold(sessCtx, moderation.Item{Type: moderation.ContentPost, ID: post.ID, AuthorID: post.UserID, Deferred: events}, decision)
+		}
+		return pr.outbox.Record(sessCtx, events...)
+	})
 	if err != nil {
 		return primitive.NilObjectID, err
 	}
-	insertedID, ok := result.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return primitive.NilObjectID, errors.New("failed to convert insertedID to ObjectID")
+	if decision.Held() {
+		return post.ID, moderation.ErrHeld
 	}
-	return insertedID, nil
+	return post.ID, nil
 }
 func (pr *PostRepository) GetPostByID(postID primitive.ObjectID, currentUserID primitive.ObjectID) (*postdomain.PostResponse, error) {
 	collection := pr.getCollection()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	now := time.Now()
 	pipeline := mongo.Pipeline{
-		// Filtrar por ID del post
-		{{Key: "$match", Value: bson.D{{Key: "_id", Value: postID}}}},
+		// Filtrar por ID del post; los programados solo los ve su autor
+		{{Key: "$match", Value: bson.M{
+			"_id":       postID,
+			"available": true,
+			"$or": bson.A{
+				publishedFilter(now),
+				bson.M{"userId": currentUserID},
+			},
+		}}},
 		// Lookup para traer los detalles del usuario creador
 		{{Key: "$lookup", Value: bson.D{
 			{Key: "from", Value: "Users"},
@@ -59,21 +121,23 @@ func (pr *PostRepository) GetPostByID(postID primitive.ObjectID, currentUserID p
 			{Key: "foreignField", Value: "_id"},
 			{Key: "as", Value: "userDetailsArr"},
 		}}},
+		// Reacción del usuario actual
+		viewerReactionLookup(currentUserID),
 		// Agregar campos computados
 		{{Key: "$addFields", Value: bson.D{
 			{Key: "userDetails", Value: bson.D{{Key: "$first", Value: "$userDetailsArr"}}},
-			{Key: "likeCount", Value: bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$likes", bson.A{}}}}}}},
-			{Key: "dislikeCount", Value: bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$dislikes", bson.A{}}}}}}},
+			{Key: "likeCount", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$likeCount", 0}}}},
+			{Key: "dislikeCount", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$dislikeCount", 0}}}},
 			{Key: "commentCount", Value: bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$comments", bson.A{}}}}}}},
-			{Key: "userLiked", Value: bson.D{{Key: "$in", Value: bson.A{currentUserID, "$likes"}}}},
-			{Key: "userDisliked", Value: bson.D{{Key: "$in", Value: bson.A{currentUserID, "$dislikes"}}}},
+			{Key: "userLiked", Value: bson.D{{Key: "$in", Value: bson.A{postdomain.ReactionLike, "$viewerReaction.type"}}}},
+			{Key: "userDisliked", Value: bson.D{{Key: "$in", Value: bson.A{postdomain.ReactionDislike, "$viewerReaction.type"}}}},
+			{Key: "scheduled", Value: bson.D{{Key: "$gt", Value: bson.A{"$publishAt", now}}}},
 		}}},
 		// Proyectar únicamente los campos necesarios
 		{{Key: "$project", Value: bson.D{
-			{Key: "likes", Value: 0},
-			{Key: "dislikes", Value: 0},
 			{Key: "comments", Value: 0},
 			{Key: "userDetailsArr", Value: 0},
+			{Key: "viewerReaction", Value: 0},
 		}}},
 	}
 
@@ -95,37 +159,174 @@ func (pr *PostRepository) GetPostByID(postID primitive.ObjectID, currentUserID p
 	return &posts[0], nil
 }
 
-func (pr *PostRepository) AddLike(postID, userID primitive.ObjectID) error {
-	collection := pr.getCollection()
+// publishedFilter selecciona los posts ya publicados: los programados cuya fecha llegó y los
+// anteriores a la programación, que no tienen publishAt.
+func publishedFilter(now time.Time) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"publishAt": bson.M{"$lte": now}},
+		bson.M{"publishAt": bson.M{"$exists": false}},
+	}}
+}
+
+// EnsurePostIndexes crea los índices del feed y de los posts programados de cada autor.
+func (pr *PostRepository) EnsurePostIndexes(ctx context.Context) error {
+	_, err := pr.getCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "available", Value: 1}, {Key: "publishAt", Value: -1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "publishAt", Value: -1}}},
+		{Keys: bson.D{{Key: "location", Value: "2dsphere"}}},
+		{Keys: bson.D{{Key: "hashtags", Value: 1}, {Key: "publishAt", Value: -1}}},
+		{Keys: bson.D{{Key: "mentions.userId", Value: 1}, {Key: "publishAt", Value: -1}}},
+	})
+	return err
+}
+
+// GetPost devuelve el post tal como está guardado, si no fue borrado.
+func (pr *PostRepository) GetPost(postID primitive.ObjectID) (*postdomain.Post, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	update := bson.M{
-		"$addToSet": bson.M{"likes": userID},    // agrega solo si no existe
-		"$pull":     bson.M{"dislikes": userID}, // remueve dislike si existe
-		"$set":      bson.M{"updatedAt": time.Now()},
+	var post postdomain.Post
+	err := pr.getCollection().FindOne(ctx, bson.M{"_id": postID, "available": true}).Decode(&post)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("post not found")
+		}
+		return nil, err
 	}
-	_, err := collection.UpdateByID(ctx, postID, update)
-	return err
+	return &post, nil
 }
 
-func (pr *PostRepository) Dislike(postID, userID primitive.ObjectID) error {
-	collection := pr.getCollection()
+// UpdatePost aplica los cambios del autor a un post vigente. El título y la descripción pasan por la
+// moderación como al crear el post, y las entidades se extraen del texto ya enmascarado; se avisa a
+// los mencionados por primera vez respecto de la versión anterior cuando el post se publique; si
+// cambia la fecha de publicación, los avisos pendientes se mueven con ella. Si la edición se
+// retiene, el post deja de estar disponible hasta que lo apruebe un admin y se devuelve moderation.ErrHeld.
+func (pr *PostRepository) UpdatePost(post postdomain.Post, set bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := pr.sanctions.Check(ctx, post.UserID, sanctions.ScopePosting); err != nil {
+		return err
+	}
+	title, _ := set["title"].(string)
+	description, _ := set["description"].(string)
+	decision, err := pr.moderator.Review(ctx, moderation.ContentPost, &title, &description)
+	if err != nil {
+		return err
+	}
+	if err := decision.Err(); err != nil {
+		return err
+	}
+	entities, err := mentions.Extract(ctx, pr.mongoClient, title, description)
+	if err != nil {
+		return err
+	}
+	set["title"] = title
+	set["description"] = description
+	set["mentions"] = entities.Mentions
+	set["hashtags"] = entities.Hashtags
+	if decision.Held() {
+		set["available"] = false
+		set["held"] = true
+	}
+	publishAt, rescheduled := set["publishAt"].(time.Time)
+	if !rescheduled {
+		publishAt = post.PublishAt
+	}
+	events, err := mentionEvents(post.UserID, post.ID, nil, title, entities.Added(post.Entities), publishAt)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	set["updatedAt"] = now
+	set["editedAt"] = now
+	err = pr.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		result, err := pr.getCollection().UpdateOne(sessCtx,
+			bson.M{"_id": post.ID, "userId": post.UserID, "available": true},
+			bson.M{"$set": set},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("post not found")
+		}
+		if decision.Held() {
+			return pr.moderator.Hold(sessCtx, moderation.Item{Type: moderation.ContentPost, ID: post.ID, AuthorID: post.UserID, Deferred: events}, decision)
+		}
+		if rescheduled {
+			match := bson.M{"postId": post.ID, "commentId": bson.M{"$exists": false}}
+			if err := pr.outbox.Reschedule(sessCtx, postdomain.EventUserMentioned, match, publishAt); err != nil {
+				return err
+			}
+		}
+		return pr.outbox.Record(sessCtx, events...)
+	})
+	if err != nil {
+		return err
+	}
+	if decision.Held() {
+		return moderation.ErrHeld
+	}
+	return nil
+}
+
+// DeletePostByAuthor "elimina" un post propio marcándolo como no disponible, igual que la moderación.
+func (pr *PostRepository) DeletePostByAuthor(postID, userID primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	update := bson.M{
-		"$addToSet": bson.M{"dislikes": userID},
-		"$pull":     bson.M{"likes": userID},
-		"$set":      bson.M{"updatedAt": time.Now()},
+	now := time.Now()
+	result, err := pr.getCollection().UpdateOne(ctx,
+		bson.M{"_id": postID, "userId": userID, "available": true},
+		bson.M{"$set": bson.M{"available": false, "deletedAt": now, "updatedAt": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("post not found")
+	}
+	return nil
+}
+
+// GetScheduledPosts devuelve los posts del usuario que todavía no se publicaron, el próximo primero.
+func (pr *PostRepository) GetScheduledPosts(userID primitive.ObjectID) ([]postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"userId": userID, "available": true, "publishAt": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.M{"publishAt": 1})
+	cursor, err := pr.getCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	posts := []postdomain.Post{}
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, err
 	}
-	_, err := collection.UpdateByID(ctx, postID, update)
+	return posts, nil
+}
+
+func (pr *PostRepository) getCommentsCollection() *mongo.Collection {
+	return pr.mongoClient.Database("NEXO-VECINAL").Collection("Comments")
+}
+
+// EnsureCommentIndexes crea los índices para listar comentarios de primer nivel y respuestas.
+func (pr *PostRepository) EnsureCommentIndexes(ctx context.Context) error {
+	_, err := pr.getCommentsCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "postId", Value: 1}, {Key: "parentId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "parentId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "mentions.userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+	})
 	return err
 }
 
 func (pr *PostRepository) AddCommentToPost(postID primitive.ObjectID, comment postdomain.Comment) (primitive.ObjectID, error) {
 	// Usamos la colección "Comments" para almacenar el comentario
-	commentsColl := pr.mongoClient.Database("NEXO-VECINAL").Collection("Comments")
+	commentsColl := pr.getCommentsCollection()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -135,29 +336,201 @@ func (pr *PostRepository) AddCommentToPost(postID primitive.ObjectID, comment po
 	}
 	comment.PostID = postID
 	comment.CreatedAt = time.Now()
+	comment.LikeCount = 0
 
-	result, err := commentsColl.InsertOne(ctx, comment)
+	if err := pr.sanctions.Check(ctx, comment.UserID, sanctions.ScopePosting); err != nil {
+		return primitive.NilObjectID, err
+	}
+	decision, err := pr.moderator.Review(ctx, moderation.ContentComment, &comment.Text)
 	if err != nil {
 		return primitive.NilObjectID, err
 	}
-
-	insertedID, ok := result.InsertedID.(primitive.ObjectID)
-	if !ok {
-		return primitive.NilObjectID, errors.New("failed to convert insertedID to ObjectID")
+	if err := decision.Err(); err != nil {
+		return primitive.NilObjectID, err
+	}
+	// Las entidades salen del texto ya enmascarado
+	if comment.Entities, err = mentions.Extract(ctx, pr.mongoClient, comment.Text); err != nil {
+		return primitive.NilObjectID, err
+	}
+	// Un comentario retenido no cuenta en el post ni en su comentario padre hasta que se apruebe
+	comment.Held = decision.Held()
+	replyInc := 1
+	if comment.Held {
+		replyInc = 0
 	}
 
-	// Ahora, actualizamos el documento del Post para agregar este ID
-	postColl := pr.getCollection()
-	update := bson.M{
-		"$push": bson.M{"comments": insertedID},
-		"$set":  bson.M{"updatedAt": time.Now()},
+	// Los avisos de un comentario retenido quedan en su reporte y se registran si un admin lo aprueba
+	events, err := mentionEvents(comment.UserID, postID, &comment.ID, comment.Text, comment.Mentions, time.Now())
+	if err != nil {
+		return primitive.NilObjectID, err
 	}
-	_, err = postColl.UpdateByID(ctx, postID, update)
+	// El comentario, el contador de respuestas del padre y la lista del post cambian juntos
+	err = pr.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		// Solo se comentan posts disponibles y ya publicados
+		postFilter := publishedFilter(time.Now())
+		postFilter["_id"] = postID
+		postFilter["available"] = true
+		count, err := pr.getCollection().CountDocuments(sessCtx, postFilter)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("post not found")
+		}
+		// Una respuesta solo puede colgar de un comentario vigente del mismo post
+		if comment.ParentID != nil {
+			result, err := commentsColl.UpdateOne(sessCtx,
+				bson.M{"_id": *comment.ParentID, "postId": postID, "deleted": bson.M{"$ne": true}},
+				bson.M{"$inc": bson.M{"replyCount": replyInc}},
+			)
+			if err != nil {
+				return err
+			}
+			if result.MatchedCount == 0 {
+				return errors.New("parent comment not found")
+			}
+		}
+		if _, err := commentsColl.InsertOne(sessCtx, comment); err != nil {
+			return err
+		}
+		if comment.Held {
+			return pr.moderator.Hold(sessCtx, moderation.Item{Type: moderation.ContentComment, ID: comment.ID, AuthorID: comment.UserID, Deferred: events}, decision)
+		}
+		// Actualizamos el documento del Post para agregar este ID
+		if _, err := pr.getCollection().UpdateByID(sessCtx, postID, bson.M{
+			"$push": bson.M{"comments": comment.ID},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		}); err != nil {
+			return err
+		}
+		return pr.outbox.Record(sessCtx, events...)
+	})
 	if err != nil {
 		return primitive.NilObjectID, err
 	}
+	if comment.Held {
+		return comment.ID, moderation.ErrHeld
+	}
+	return comment.ID, nil
+}
+
+// GetComment devuelve el comentario tal como está guardado.
+func (pr *PostRepository) GetComment(commentID primitive.ObjectID) (*postdomain.Comment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	return insertedID, nil
+	var comment postdomain.Comment
+	if err := pr.getCommentsCollection().FindOne(ctx, bson.M{"_id": commentID}).Decode(&comment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// EditComment cambia el texto de un comentario vigente de userID. El texto pasa por la moderación como
+// al comentar y las entidades se extraen del texto ya enmascarado; se avisa a los mencionados por
+// primera vez. Si la edición se retiene, el comentario deja de contar en el post y en su padre hasta
+// que lo apruebe un admin, igual que un comentario nuevo retenido, y se devuelve moderation.ErrHeld.
+func (pr *PostRepository) EditComment(comment postdomain.Comment, text string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := pr.sanctions.Check(ctx, comment.UserID, sanctions.ScopePosting); err != nil {
+		return err
+	}
+	decision, err := pr.moderator.Review(ctx, moderation.ContentComment, &text)
+	if err != nil {
+		return err
+	}
+	if err := decision.Err(); err != nil {
+		return err
+	}
+	entities, err := mentions.Extract(ctx, pr.mongoClient, text)
+	if err != nil {
+		return err
+	}
+	events, err := mentionEvents(comment.UserID, comment.PostID, &comment.ID, text, entities.Added(comment.Entities), time.Now())
+	if err != nil {
+		return err
+	}
+	set := bson.M{"text": text, "editedAt": time.Now(), "mentions": entities.Mentions, "hashtags": entities.Hashtags}
+	if decision.Held() {
+		set["held"] = true
+	}
+	err = pr.outbox.WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		// Un comentario retenido no se puede editar hasta que se resuelva
+		result, err := pr.getCommentsCollection().UpdateOne(sessCtx,
+			bson.M{"_id": comment.ID, "userId": comment.UserID, "deleted": bson.M{"$ne": true}, "held": bson.M{"$ne": true}},
+			bson.M{"$set": set},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("comment not found")
+		}
+		if !decision.Held() {
+			return pr.outbox.Record(sessCtx, events...)
+		}
+		if _, err := pr.getCollection().UpdateByID(sessCtx, comment.PostID, bson.M{"$pull": bson.M{"comments": comment.ID}}); err != nil {
+			return err
+		}
+		if comment.ParentID != nil {
+			if _, err := pr.getCommentsCollection().UpdateByID(sessCtx, *comment.ParentID, bson.M{"$inc": bson.M{"replyCount": -1}}); err != nil {
+				return err
+			}
+		}
+		return pr.moderator.Hold(sessCtx, moderation.Item{Type: moderation.ContentComment, ID: comment.ID, AuthorID: comment.UserID, Deferred: events}, decision)
+	})
+	if err != nil {
+		return err
+	}
+	if decision.Held() {
+		return moderation.ErrHeld
+	}
+	return nil
+}
+
+// DeleteComment borra un comentario de userID sin sacarlo del hilo: se vacía el texto y los likes,
+// pero las respuestas siguen colgando de él. Deja de contar en el commentCount del post y en el
+// replyCount de su padre.
+func (pr *PostRepository) DeleteComment(commentID, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return pr.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		now := time.Now()
+		var comment postdomain.Comment
+		err := pr.getCommentsCollection().FindOneAndUpdate(sessCtx,
+			bson.M{"_id": commentID, "userId": userID, "deleted": bson.M{"$ne": true}},
+			bson.M{
+				"$set":   bson.M{"deleted": true, "deletedAt": now, "text": "", "likeCount": 0},
+				"$unset": bson.M{"mentions": "", "hashtags": ""},
+			},
+		).Decode(&comment)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return errors.New("comment not found")
+			}
+			return err
+		}
+		if _, err := pr.getCommentReactionsCollection().DeleteMany(sessCtx, bson.M{"commentId": commentID}); err != nil {
+			return err
+		}
+		// Un comentario retenido todavía no contaba en su padre
+		if comment.ParentID != nil && !comment.Held {
+			if _, err := pr.getCommentsCollection().UpdateByID(sessCtx, *comment.ParentID, bson.M{"$inc": bson.M{"replyCount": -1}}); err != nil {
+				return err
+			}
+		}
+		_, err = pr.getCollection().UpdateByID(sessCtx, comment.PostID, bson.M{
+			"$pull": bson.M{"comments": commentID},
+			"$set":  bson.M{"updatedAt": now},
+		})
+		return err
+	})
 }
 
 func (pr *PostRepository) GetLatestPosts(limit int) ([]postdomain.Post, error) {
@@ -176,22 +549,11 @@ func (pr *PostRepository) GetLatestPosts(limit int) ([]postdomain.Post, error) {
 	}
 	return posts, nil
 }
-func (pr *PostRepository) GetLatestPostsDetailed(currentUserID primitive.ObjectID, page, limit int) ([]postdomain.PostResponse, error) {
-	collection := pr.getCollection()
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	skip := (page - 1) * limit
-	filter := bson.M{}
-	filter["available"] = true
 
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: filter}},
-		// Ordenar por CreatedAt descendente
-		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}}}},
-		// Aplicar paginación: skip y limit
-		{{Key: "$skip", Value: skip}},
-		{{Key: "$limit", Value: limit}},
+// postDetailStages agrega a cada post los datos del creador, los contadores y la reacción de
+// currentUserID, y quita los arrays completos.
+func postDetailStages(currentUserID primitive.ObjectID) mongo.Pipeline {
+	return mongo.Pipeline{
 		// Lookup para traer los detalles del usuario creador
 		{{Key: "$lookup", Value: bson.D{
 			{Key: "from", Value: "Users"},
@@ -199,31 +561,45 @@ func (pr *PostRepository) GetLatestPostsDetailed(currentUserID primitive.ObjectI
 			{Key: "foreignField", Value: "_id"},
 			{Key: "as", Value: "userDetailsArr"},
 		}}},
+		// Reacción del usuario actual (una consulta indexada por post, en lugar de recorrer arrays)
+		viewerReactionLookup(currentUserID),
 		// Agregar campos computados
 		{{Key: "$addFields", Value: bson.D{
 			// Extraer el primer elemento del array de usuarios
 			{Key: "userDetails", Value: bson.D{{Key: "$first", Value: "$userDetailsArr"}}},
-			{Key: "likeCount", Value: bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$likes", bson.A{}}}}}}},
-			{Key: "dislikeCount", Value: bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$dislikes", bson.A{}}}}}}},
+			{Key: "likeCount", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$likeCount", 0}}}},
+			{Key: "dislikeCount", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$dislikeCount", 0}}}},
 			{Key: "commentCount", Value: bson.D{{Key: "$size", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$comments", bson.A{}}}}}}},
-			// Aseguramos que si likes/dislikes son null, se usen como arrays vacíos
-			{Key: "userLiked", Value: bson.D{{Key: "$in", Value: bson.A{
-				currentUserID,
-				bson.D{{Key: "$ifNull", Value: bson.A{"$likes", bson.A{}}}},
-			}}}},
-			{Key: "userDisliked", Value: bson.D{{Key: "$in", Value: bson.A{
-				currentUserID,
-				bson.D{{Key: "$ifNull", Value: bson.A{"$dislikes", bson.A{}}}},
-			}}}},
+			{Key: "userLiked", Value: bson.D{{Key: "$in", Value: bson.A{postdomain.ReactionLike, "$viewerReaction.type"}}}},
+			{Key: "userDisliked", Value: bson.D{{Key: "$in", Value: bson.A{postdomain.ReactionDislike, "$viewerReaction.type"}}}},
 		}}},
 		// Proyectar únicamente los campos necesarios (eliminando arrays completos)
 		{{Key: "$project", Value: bson.D{
-			{Key: "likes", Value: 0},
-			{Key: "dislikes", Value: 0},
 			{Key: "comments", Value: 0},
 			{Key: "userDetailsArr", Value: 0},
+			{Key: "viewerReaction", Value: 0},
 		}}},
 	}
+}
+
+func (pr *PostRepository) GetLatestPostsDetailed(currentUserID primitive.ObjectID, page, limit int) ([]postdomain.PostResponse, error) {
+	collection := pr.getCollection()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	skip := (page - 1) * limit
+	filter := publishedFilter(time.Now())
+	filter["available"] = true
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		// Ordenar por fecha de publicación; los posts sin publishAt son anteriores a la programación
+		{{Key: "$sort", Value: bson.D{{Key: "publishAt", Value: -1}, {Key: "createdAt", Value: -1}}}},
+		// Aplicar paginación: skip y limit
+		{{Key: "$skip", Value: skip}},
+		{{Key: "$limit", Value: limit}},
+	}
+	pipeline = append(pipeline, postDetailStages(currentUserID)...)
 
 	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -238,106 +614,111 @@ func (pr *PostRepository) GetLatestPostsDetailed(currentUserID primitive.ObjectI
 	return posts, nil
 }
 
-func (pr *PostRepository) GetCommentsForPost(postID primitive.ObjectID, page, limit int) ([]postdomain.CommentResponse, error) {
-	// Usamos la colección "Comments" en lugar de la colección "Posts"
-	commentsColl := pr.mongoClient.Database("NEXO-VECINAL").Collection("Comments")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	skip := (page - 1) * limit
-
+// commentPipeline filtra, ordena y pagina comentarios, agregando el autor, los contadores y
+// si currentUserID les dio like. limit 0 no pagina.
+func commentPipeline(match bson.M, sort bson.D, currentUserID primitive.ObjectID, page, limit int) mongo.Pipeline {
 	pipeline := mongo.Pipeline{
-		// Filtrar comentarios por postId
-		{{Key: "$match", Value: bson.M{"postId": postID}}},
-		// Ordenar por fecha de creación (más recientes primero)
-		{{Key: "$sort", Value: bson.M{"createdAt": -1}}},
-		// Aplicar paginación
-		{{Key: "$skip", Value: skip}},
-		{{Key: "$limit", Value: limit}},
-
-		// Lookup para obtener el array de detalles del usuario
-		{{Key: "$lookup", Value: bson.D{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: sort}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$skip", Value: (page - 1) * limit}},
+			bson.D{{Key: "$limit", Value: limit}},
+		)
+	}
+	return append(pipeline,
+		// Lookup para traer el detalle del usuario que creó el comentario
+		bson.D{{Key: "$lookup", Value: bson.D{
 			{Key: "from", Value: "Users"},
-			{Key: "localField", Value: "userId"}, // o "comments.userID" en el contexto de comentarios
+			{Key: "localField", Value: "userId"},
 			{Key: "foreignField", Value: "_id"},
 			{Key: "as", Value: "userDetailsArr"},
 		}}},
-		// Extraer el primer elemento de userDetailsArr
-		{{Key: "$addFields", Value: bson.D{
-			{Key: "userDetail", Value: bson.D{
-				{Key: "$arrayElemAt", Value: bson.A{"$userDetailsArr", 0}},
-			}},
+		viewerCommentLikeLookup(currentUserID),
+		bson.D{{Key: "$addFields", Value: bson.D{
+			{Key: "userDetail", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$userDetailsArr", 0}}}},
+			{Key: "userLiked", Value: bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$size", Value: "$viewerLike"}}, 0}}}},
 		}}},
-
 		// Proyectar los campos deseados
-		{{Key: "$project", Value: bson.M{
-			"id":                  "$_id",
+		bson.D{{Key: "$project", Value: bson.M{
 			"text":                1,
 			"userId":              1,
+			"parentId":            1,
 			"createdAt":           1,
+			"editedAt":            1,
+			"deleted":             1,
+			"replyCount":          1,
+			"likeCount":           1,
+			"userLiked":           1,
+			"mentions":            1,
+			"hashtags":            1,
 			"userDetail._id":      1,
 			"userDetail.NameUser": 1,
 			"userDetail.Avatar":   1,
 		}}},
+	)
+}
+
+// visibleComments descarta los comentarios retenidos por la moderación y los borrados que no tienen
+// respuestas: no hay hilo que conservar.
+func visibleComments(match bson.M) bson.M {
+	match["held"] = bson.M{"$ne": true}
+	match["$or"] = bson.A{
+		bson.M{"deleted": bson.M{"$ne": true}},
+		bson.M{"replyCount": bson.M{"$gt": 0}},
 	}
+	return match
+}
 
-	cursor, err := commentsColl.Aggregate(ctx, pipeline)
+func (pr *PostRepository) findComments(pipeline mongo.Pipeline) ([]postdomain.CommentResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := pr.getCommentsCollection().Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var comments []postdomain.CommentResponse
+	comments := []postdomain.CommentResponse{}
 	if err = cursor.All(ctx, &comments); err != nil {
 		return nil, err
 	}
-
 	return comments, nil
 }
 
-func (pr *PostRepository) GetCommentByID(commentID primitive.ObjectID) (postdomain.CommentResponse, error) {
-	commentsColl := pr.mongoClient.Database("NEXO-VECINAL").Collection("Comments")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// GetCommentsForPost devuelve los comentarios de primer nivel del post, los más recientes primero.
+func (pr *PostRepository) GetCommentsForPost(postID, currentUserID primitive.ObjectID, page, limit int) ([]postdomain.CommentResponse, error) {
+	match := visibleComments(bson.M{"postId": postID, "parentId": nil})
+	return pr.findComments(commentPipeline(match, bson.D{{Key: "createdAt", Value: -1}}, currentUserID, page, limit))
+}
 
-	pipeline := mongo.Pipeline{
-		// Filtrar el comentario por su _id
-		{{Key: "$match", Value: bson.M{"_id": commentID}}},
-		// Lookup para traer el detalle del usuario que creó el comentario
-		{{Key: "$lookup", Value: bson.M{
-			"from":         "Users",
-			"localField":   "userId",
-			"foreignField": "_id",
-			"as":           "userDetail",
-		}}},
-		// Desempaquetar el array devuelto por el lookup
-		{{Key: "$unwind", Value: bson.M{"path": "$userDetail", "preserveNullAndEmptyArrays": true}}},
-		// Proyectar los campos necesarios
-		{{Key: "$project", Value: bson.M{
-			"id":                  "$_id",
-			"text":                1,
-			"userId":              1,
-			"createdAt":           1,
-			"userDetail._id":      1,
-			"userDetail.NameUser": 1,
-			"userDetail.Avatar":   1,
-		}}},
-	}
+// GetCommentReplies devuelve las respuestas directas a un comentario, en orden cronológico.
+func (pr *PostRepository) GetCommentReplies(commentID, currentUserID primitive.ObjectID, page, limit int) ([]postdomain.CommentResponse, error) {
+	match := visibleComments(bson.M{"parentId": commentID})
+	return pr.findComments(commentPipeline(match, bson.D{{Key: "createdAt", Value: 1}}, currentUserID, page, limit))
+}
 
-	cursor, err := commentsColl.Aggregate(ctx, pipeline)
+// GetCommentByID devuelve un comentario con las mismas reglas de visibilidad que los hilos, salvo que
+// su autor también ve sus comentarios retenidos.
+func (pr *PostRepository) GetCommentByID(commentID, currentUserID primitive.ObjectID) (postdomain.CommentResponse, error) {
+	match := bson.M{"_id": commentID, "$and": bson.A{
+		bson.M{"$or": bson.A{
+			bson.M{"held": bson.M{"$ne": true}},
+			bson.M{"userId": currentUserID},
+		}},
+		bson.M{"$or": bson.A{
+			bson.M{"deleted": bson.M{"$ne": true}},
+			bson.M{"replyCount": bson.M{"$gt": 0}},
+		}},
+	}}
+	comments, err := pr.findComments(commentPipeline(match, bson.D{{Key: "_id", Value: 1}}, currentUserID, 1, 0))
 	if err != nil {
 		return postdomain.CommentResponse{}, err
 	}
-	defer cursor.Close(ctx)
-
-	var comments []postdomain.CommentResponse
-	if err = cursor.All(ctx, &comments); err != nil {
-		return postdomain.CommentResponse{}, err
-	}
-
 	if len(comments) == 0 {
 		return postdomain.CommentResponse{}, errors.New("comment not found")
 	}
-
 	return comments[0], nil
 }
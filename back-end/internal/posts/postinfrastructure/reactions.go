@@ -0,0 +1,318 @@
+package postinfrastructure
+
+import (
+	"back-end/internal/posts/postdomain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (pr *PostRepository) getReactionsCollection() *mongo.Collection {
+	return pr.mongoClient.Database("NEXO-VECINAL").Collection("PostReactions")
+}
+
+// EnsureReactionIndexes crea el índice único (post, usuario): una reacción por usuario y post.
+func (pr *PostRepository) EnsureReactionIndexes(ctx context.Context) error {
+	_, err := pr.getReactionsCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "postId", Value: 1}, {Key: "userId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// viewerReactionLookup agrega en viewerReaction la reacción de userID al post (vacío si no reaccionó).
+func viewerReactionLookup(userID primitive.ObjectID) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.D{
+		{Key: "from", Value: "PostReactions"},
+		{Key: "let", Value: bson.D{{Key: "postId", Value: "$_id"}}},
+		{Key: "pipeline", Value: mongo.Pipeline{
+			{{Key: "$match", Value: bson.D{
+				{Key: "userId", Value: userID},
+				{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$postId", "$$postId"}}}},
+			}}},
+			{{Key: "$project", Value: bson.D{{Key: "type", Value: 1}}}},
+		}},
+		{Key: "as", Value: "viewerReaction"},
+	}}}
+}
+
+func (pr *PostRepository) withTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	session, err := pr.mongoClient.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// React deja la reacción reactionType de userID en el post, reemplazando la anterior (un like
+// quita el dislike y viceversa). La reacción y los contadores del post cambian en una transacción.
+func (pr *PostRepository) React(postID, userID primitive.ObjectID, reactionType postdomain.ReactionType) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return pr.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		now := time.Now()
+		var previous postdomain.Reaction
+		err := pr.getReactionsCollection().FindOneAndUpdate(sessCtx,
+			bson.M{"postId": postID, "userId": userID},
+			bson.M{
+				"$set":         bson.M{"type": reactionType, "updatedAt": now},
+				"$setOnInsert": bson.M{"createdAt": now},
+			},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+		).Decode(&previous)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		if previous.Type == reactionType {
+			return nil
+		}
+
+		inc := bson.M{reactionType.CounterField(): 1}
+		if previous.Type != "" {
+			inc[previous.Type.CounterField()] = -1
+		}
+		return pr.incReactionCounters(sessCtx, postID, inc)
+	})
+}
+
+// Unreact quita la reacción de userID al post, si tenía una.
+func (pr *PostRepository) Unreact(postID, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return pr.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		var previous postdomain.Reaction
+		err := pr.getReactionsCollection().FindOneAndDelete(sessCtx, bson.M{"postId": postID, "userId": userID}).Decode(&previous)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return pr.incReactionCounters(sessCtx, postID, bson.M{previous.Type.CounterField(): -1})
+	})
+}
+
+func (pr *PostRepository) incReactionCounters(ctx context.Context, postID primitive.ObjectID, inc bson.M) error {
+	result, err := pr.getCollection().UpdateOne(ctx,
+		bson.M{"_id": postID, "available": true},
+		bson.M{"$inc": inc, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("post not found")
+	}
+	return nil
+}
+
+// MigrateReactionArrays pasa los arrays likes/dislikes de los posts anteriores a PostReactions y
+// recalcula sus contadores. Es idempotente: un post solo pierde sus arrays después de migrarlos,
+// y las reacciones hechas después con la API nueva se respetan.
+func (pr *PostRepository) MigrateReactionArrays(ctx context.Context) (int, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"likes": bson.M{"$exists": true}},
+		bson.M{"dislikes": bson.M{"$exists": true}},
+	}}
+	opts := options.Find().SetProjection(bson.M{"likes": 1, "dislikes": 1})
+	cursor, err := pr.getCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var legacy struct {
+			ID       primitive.ObjectID   `bson:"_id"`
+			Likes    []primitive.ObjectID `bson:"likes"`
+			Dislikes []primitive.ObjectID `bson:"dislikes"`
+		}
+		if err := cursor.Decode(&legacy); err != nil {
+			return migrated, err
+		}
+		if err := pr.migratePostReactions(ctx, legacy.ID, legacy.Likes, legacy.Dislikes); err != nil {
+			return migrated, fmt.Errorf("post %s: %w", legacy.ID.Hex(), err)
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+func (pr *PostRepository) migratePostReactions(ctx context.Context, postID primitive.ObjectID, likes, dislikes []primitive.ObjectID) error {
+	now := time.Now()
+	var models []mongo.WriteModel
+	add := func(userIDs []primitive.ObjectID, reactionType postdomain.ReactionType) {
+		for _, userID := range userIDs {
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"postId": postID, "userId": userID}).
+				SetUpdate(bson.M{"$setOnInsert": bson.M{"type": reactionType, "createdAt": now, "updatedAt": now}}).
+				SetUpsert(true))
+		}
+	}
+	// Si un usuario quedó en ambos arrays, gana el like
+	add(likes, postdomain.ReactionLike)
+	add(dislikes, postdomain.ReactionDislike)
+	if len(models) > 0 {
+		if _, err := pr.getReactionsCollection().BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true)); err != nil {
+			return err
+		}
+	}
+
+	return pr.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		likeCount, err := pr.getReactionsCollection().CountDocuments(sessCtx, bson.M{"postId": postID, "type": postdomain.ReactionLike})
+		if err != nil {
+			return err
+		}
+		dislikeCount, err := pr.getReactionsCollection().CountDocuments(sessCtx, bson.M{"postId": postID, "type": postdomain.ReactionDislike})
+		if err != nil {
+			return err
+		}
+		_, err = pr.getCollection().UpdateByID(sessCtx, postID, bson.M{
+			"$set":   bson.M{"likeCount": likeCount, "dislikeCount": dislikeCount},
+			"$unset": bson.M{"likes": "", "dislikes": ""},
+		})
+		return err
+	})
+}
+
+func (pr *PostRepository) getCommentReactionsCollection() *mongo.Collection {
+	return pr.mongoClient.Database("NEXO-VECINAL").Collection("CommentReactions")
+}
+
+// EnsureCommentReactionIndexes crea el índice único (comentario, usuario): un like por usuario y comentario.
+func (pr *PostRepository) EnsureCommentReactionIndexes(ctx context.Context) error {
+	_, err := pr.getCommentReactionsCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "commentId", Value: 1}, {Key: "userId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// viewerCommentLikeLookup agrega en viewerLike el like de userID al comentario (vacío si no lo dio).
+func viewerCommentLikeLookup(userID primitive.ObjectID) bson.D {
+	return bson.D{{Key: "$lookup", Value: bson.D{
+		{Key: "from", Value: "CommentReactions"},
+		{Key: "let", Value: bson.D{{Key: "commentId", Value: "$_id"}}},
+		{Key: "pipeline", Value: mongo.Pipeline{
+			{{Key: "$match", Value: bson.D{
+				{Key: "userId", Value: userID},
+				{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$commentId", "$$commentId"}}}},
+			}}},
+			{{Key: "$project", Value: bson.D{{Key: "_id", Value: 1}}}},
+		}},
+		{Key: "as", Value: "viewerLike"},
+	}}}
+}
+
+// LikeComment agrega (like=true) o quita el like de userID en un comentario vigente. El like y el
+// likeCount del comentario cambian en una transacción.
+func (pr *PostRepository) LikeComment(commentID, userID primitive.ObjectID, like bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return pr.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		filter := bson.M{"commentId": commentID, "userId": userID}
+		inc := -1
+		if like {
+			result, err := pr.getCommentReactionsCollection().UpdateOne(sessCtx, filter,
+				bson.M{"$setOnInsert": bson.M{"createdAt": time.Now()}},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return err
+			}
+			if result.UpsertedCount == 0 {
+				return nil
+			}
+			inc = 1
+		} else {
+			result, err := pr.getCommentReactionsCollection().DeleteOne(sessCtx, filter)
+			if err != nil {
+				return err
+			}
+			if result.DeletedCount == 0 {
+				return nil
+			}
+		}
+		result, err := pr.getCommentsCollection().UpdateOne(sessCtx,
+			bson.M{"_id": commentID, "deleted": bson.M{"$ne": true}, "held": bson.M{"$ne": true}},
+			bson.M{"$inc": bson.M{"likeCount": inc}},
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errors.New("comment not found")
+		}
+		return nil
+	})
+}
+
+// MigrateCommentLikes pasa los arrays likes de los comentarios anteriores a CommentReactions y
+// recalcula su likeCount. Es idempotente igual que MigrateReactionArrays.
+func (pr *PostRepository) MigrateCommentLikes(ctx context.Context) (int, error) {
+	opts := options.Find().SetProjection(bson.M{"likes": 1})
+	cursor, err := pr.getCommentsCollection().Find(ctx, bson.M{"likes": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var legacy struct {
+			ID    primitive.ObjectID   `bson:"_id"`
+			Likes []primitive.ObjectID `bson:"likes"`
+		}
+		if err := cursor.Decode(&legacy); err != nil {
+			return migrated, err
+		}
+		if err := pr.migrateCommentLikes(ctx, legacy.ID, legacy.Likes); err != nil {
+			return migrated, fmt.Errorf("comment %s: %w", legacy.ID.Hex(), err)
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+func (pr *PostRepository) migrateCommentLikes(ctx context.Context, commentID primitive.ObjectID, likes []primitive.ObjectID) error {
+	now := time.Now()
+	var models []mongo.WriteModel
+	for _, userID := range likes {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"commentId": commentID, "userId": userID}).
+			SetUpdate(bson.M{"$setOnInsert": bson.M{"createdAt": now}}).
+			SetUpsert(true))
+	}
+	if len(models) > 0 {
+		if _, err := pr.getCommentReactionsCollection().BulkWrite(ctx, models); err != nil {
+			return err
+		}
+	}
+
+	return pr.withTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		likeCount, err := pr.getCommentReactionsCollection().CountDocuments(sessCtx, bson.M{"commentId": commentID})
+		if err != nil {
+			return err
+		}
+		_, err = pr.getCommentsCollection().UpdateByID(sessCtx, commentID, bson.M{
+			"$set":   bson.M{"likeCount": likeCount},
+			"$unset": bson.M{"likes": ""},
+		})
+		return err
+	})
+}
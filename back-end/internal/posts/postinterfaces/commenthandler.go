@@ -0,0 +1,132 @@
+package postinterfaces
+
+import (
+	"back-end/internal/posts/postdomain"
+	"back-end/pkg/moderation"
+	"back-end/pkg/sanctions"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// commentIDs obtiene el comentario de la URL y el usuario del token.
+func commentIDs(c *fiber.Ctx) (primitive.ObjectID, primitive.ObjectID, error) {
+	commentID, err := primitive.ObjectIDFromHex(c.Params("commentId"))
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("Invalid comment ID")
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, errors.New("Invalid user ID")
+	}
+	return commentID, userID, nil
+}
+
+// commentErrorStatus distingue los comentarios ajenos o inexistentes de los errores internos.
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sanctions.ErrSanctioned):
+		return fiber.StatusForbidden
+	case errors.Is(err, moderation.ErrRejected):
+		return fiber.StatusUnprocessableEntity
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		return fiber.StatusForbidden
+	case err.Error() == "comment not found":
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
+
+// GetCommentReplies obtiene las respuestas a un comentario.
+func (ph *PostHandler) GetCommentReplies(c *fiber.Ctx) error {
+	commentID, userID, err := commentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
+	}
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	replies, err := ph.PostService.GetCommentReplies(commentID, userID, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "ok",
+		"replies": replies,
+	})
+}
+
+// EditComment edita el texto de un comentario propio.
+func (ph *PostHandler) EditComment(c *fiber.Ctx) error {
+	commentID, userID, err := commentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
+	}
+	var req postdomain.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Bad Request"})
+	}
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Bad Request", "error": err.Error()})
+	}
+	err = ph.PostService.EditComment(commentID, userID, req.Text)
+	if errors.Is(err, moderation.ErrHeld) {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "El comentario quedó pendiente de revisión", "commentId": commentID})
+	}
+	if err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
+	}
+	comment, err := ph.PostService.GetCommentByID(commentID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Comment updated",
+		"comment": comment,
+	})
+}
+
+// DeleteComment borra un comentario propio; sus respuestas se conservan.
+func (ph *PostHandler) DeleteComment(c *fiber.Ctx) error {
+	commentID, userID, err := commentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
+	}
+	if err := ph.PostService.DeleteComment(commentID, userID); err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Comment deleted"})
+}
+
+// LikeComment agrega un like a un comentario.
+func (ph *PostHandler) LikeComment(c *fiber.Ctx) error {
+	commentID, userID, err := commentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
+	}
+	if err := ph.PostService.LikeComment(commentID, userID, true); err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Like added"})
+}
+
+// UnlikeComment quita el like de un comentario.
+func (ph *PostHandler) UnlikeComment(c *fiber.Ctx) error {
+	commentID, userID, err := commentIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
+	}
+	if err := ph.PostService.LikeComment(commentID, userID, false); err != nil {
+		return c.Status(commentErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Like removed"})
+}
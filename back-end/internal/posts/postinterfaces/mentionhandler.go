@@ -0,0 +1,58 @@
+package postinterfaces
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pageParams lee page y limit de la query; limit se acota a 50.
+func pageParams(c *fiber.Ctx) (int, int) {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit < 1 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+	return page, limit
+}
+
+// GetPostsByHashtag devuelve los posts publicados con el hashtag (con o sin #, sin distinguir mayúsculas).
+func (ph *PostHandler) GetPostsByHashtag(c *fiber.Ctx) error {
+	currentUserID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+	page, limit := pageParams(c)
+	posts, err := ph.PostService.GetPostsByHashtag(currentUserID, c.Params("tag"), page, limit)
+	if err != nil {
+		if err.Error() == "hashtag required" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(posts)
+}
+
+// GetMentions devuelve los posts y comentarios donde se mencionó al usuario autenticado.
+func (ph *PostHandler) GetMentions(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+	page, limit := pageParams(c)
+	mentions, err := ph.PostService.GetMentions(userID, page, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":  "ok",
+		"mentions": mentions,
+	})
+}
@@ -4,8 +4,15 @@ import (
 	"back-end/internal/posts/postapplication"
 	"back-end/internal/posts/postdomain"
 	"back-end/pkg/helpers"
+	"back-end/pkg/moderation"
+	"back-end/pkg/sanctions"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"mime/multipart"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -40,55 +47,34 @@ func (ph *PostHandler) CreatePost(c *fiber.Ctx) error {
 		})
 	}
 
-	// Inicialmente, aseguramos que Images sea un slice vacío
-	req.Images = []string{}
-
-	// Intentamos obtener el multipart form para procesar imágenes, si existen
-	form, err := c.MultipartForm()
-	if err == nil && form != nil {
-		files := form.File["images"]
-		if len(files) > 3 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"message": "You can upload a maximum of 3 images",
-			})
-		}
-
-		// Si se enviaron imágenes, procesamos cada archivo
-		if len(files) > 0 {
-			imageURLs := make([]string, len(files))
-			errCh := make(chan error, len(files))
-			doneCh := make(chan int, len(files))
-
-			for i, fileHeader := range files {
-				go func(index int, fh *multipart.FileHeader) {
-					var postImageCh = make(chan string)
-					var procErrCh = make(chan error)
-					// Procesa la imagen de forma asíncrona (función definida en helpers)
-					go helpers.ProcessImage(fh, postImageCh, procErrCh, "post")
-					select {
-					case imageUrl := <-postImageCh:
-						imageURLs[index] = imageUrl
-						doneCh <- index
-					case procErr := <-procErrCh:
-						errCh <- procErr
-					}
-				}(i, fileHeader)
-			}
+	// Procesamos las imágenes del multipart form, si existen
+	images, status, err := processPostImages(c, postdomain.MaxPostImages)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{
+			"message": err.Error(),
+		})
+	}
+	req.Images = images
 
-			processed := 0
-			for processed < len(files) {
-				select {
-				case <-doneCh:
-					processed++
-				case procErr := <-errCh:
-					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-						"message": "Error processing images",
-						"error":   procErr.Error(),
-					})
-				}
-			}
-			req.Images = imageURLs
-		}
+	publishAt, err := parsePublishAt(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid publishAt",
+			"error":   err.Error(),
+		})
+	}
+	if publishAt != nil {
+		req.PublishAt = publishAt
+	}
+	location, err := parseLocation(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid location",
+			"error":   err.Error(),
+		})
+	}
+	if location != nil {
+		req.Location = location
 	}
 
 	// Obtener el ID del usuario desde el token
@@ -101,6 +87,21 @@ func (ph *PostHandler) CreatePost(c *fiber.Ctx) error {
 	}
 
 	postId, err := ph.PostService.CreatePost(req, userID)
+	if sanction, ok := sanctions.As(err); ok {
+		return sanctionResponse(c, sanction)
+	}
+	if errors.Is(err, moderation.ErrRejected) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"message": "El post no cumple las normas de la comunidad",
+			"error":   err.Error(),
+		})
+	}
+	if errors.Is(err, moderation.ErrHeld) {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"message": "El post quedó pendiente de revisión",
+			"postId":  postId,
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Could not create post",
@@ -115,6 +116,191 @@ func (ph *PostHandler) CreatePost(c *fiber.Ctx) error {
 	})
 }
 
+// processPostImages sube las imágenes del campo "images" del multipart form (hasta max) y
+// devuelve sus URLs. Sin multipart form devuelve un slice vacío.
+func processPostImages(c *fiber.Ctx, max int) ([]string, int, error) {
+	imageURLs := []string{}
+	form, err := c.MultipartForm()
+	if err != nil || form == nil {
+		return imageURLs, fiber.StatusOK, nil
+	}
+	files := form.File["images"]
+	if len(files) > max {
+		return nil, fiber.StatusBadRequest, fmt.Errorf("You can upload a maximum of %d images", max)
+	}
+	if len(files) == 0 {
+		return imageURLs, fiber.StatusOK, nil
+	}
+
+	imageURLs = make([]string, len(files))
+	errCh := make(chan error, len(files))
+	doneCh := make(chan int, len(files))
+
+	for i, fileHeader := range files {
+		go func(index int, fh *multipart.FileHeader) {
+			var postImageCh = make(chan string)
+			var procErrCh = make(chan error)
+			// Procesa la imagen de forma asíncrona (función definida en helpers)
+			go helpers.ProcessImage(fh, postImageCh, procErrCh, "post")
+			select {
+			case imageUrl := <-postImageCh:
+				imageURLs[index] = imageUrl
+				doneCh <- index
+			case procErr := <-procErrCh:
+				errCh <- procErr
+			}
+		}(i, fileHeader)
+	}
+
+	processed := 0
+	for processed < len(files) {
+		select {
+		case <-doneCh:
+			processed++
+		case procErr := <-errCh:
+			return nil, fiber.StatusInternalServerError, fmt.Errorf("Error processing images: %w", procErr)
+		}
+	}
+	return imageURLs, fiber.StatusOK, nil
+}
+
+// parsePublishAt lee el campo publishAt (RFC3339) de un multipart form; en JSON lo resuelve BodyParser.
+func parsePublishAt(c *fiber.Ctx) (*time.Time, error) {
+	value := c.FormValue("publishAt")
+	if value == "" {
+		return nil, nil
+	}
+	publishAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &publishAt, nil
+}
+
+// parseLocation lee el campo location de un multipart form como GeoJSON, por ejemplo
+// {"type":"Point","coordinates":[lng,lat]}; en JSON lo resuelve BodyParser.
+func parseLocation(c *fiber.Ctx) (*postdomain.GeoPoint, error) {
+	value := c.FormValue("location")
+	if value == "" {
+		return nil, nil
+	}
+	var location postdomain.GeoPoint
+	if err := json.Unmarshal([]byte(value), &location); err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// UpdatePost edita el título, la descripción, las imágenes o la fecha programada de un post propio.
+func (ph *PostHandler) UpdatePost(c *fiber.Ctx) error {
+	postID, err := primitive.ObjectIDFromHex(c.Params("postId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid post ID"})
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+
+	var req postdomain.UpdatePostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Bad Request", "error": err.Error()})
+	}
+	publishAt, err := parsePublishAt(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid publishAt", "error": err.Error()})
+	}
+	if publishAt != nil {
+		req.PublishAt = publishAt
+	}
+	location, err := parseLocation(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid location", "error": err.Error()})
+	}
+	if location != nil {
+		req.Location = location
+	}
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Bad Request", "error": err.Error()})
+	}
+
+	newImages, status, err := processPostImages(c, postdomain.MaxPostImages-len(req.KeepImages))
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{"message": err.Error()})
+	}
+	err = ph.PostService.UpdatePost(postID, userID, req, newImages)
+	if errors.Is(err, moderation.ErrHeld) {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "El post quedó pendiente de revisión", "postId": postID})
+	}
+	if err != nil {
+		return c.Status(postErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
+	}
+
+	post, err := ph.PostService.GetPostByID(postID, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Post updated successfully",
+		"post":    post,
+	})
+}
+
+// DeletePost elimina un post propio.
+func (ph *PostHandler) DeletePost(c *fiber.Ctx) error {
+	postID, err := primitive.ObjectIDFromHex(c.Params("postId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid post ID"})
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+	if err := ph.PostService.DeletePost(postID, userID); err != nil {
+		return c.Status(postErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Post deleted"})
+}
+
+// GetScheduledPosts devuelve los posts programados del usuario.
+func (ph *PostHandler) GetScheduledPosts(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+	posts, err := ph.PostService.GetScheduledPosts(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "ok",
+		"posts":   posts,
+	})
+}
+
+// postErrorStatus distingue los posts ajenos o inexistentes de los errores de validación.
+func postErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, sanctions.ErrSanctioned):
+		return fiber.StatusForbidden
+	case errors.Is(err, moderation.ErrRejected):
+		return fiber.StatusUnprocessableEntity
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		return fiber.StatusForbidden
+	case err.Error() == "post not found":
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusBadRequest
+}
+
+// sanctionResponse responde 403 con la sanción vigente que impide publicar.
+func sanctionResponse(c *fiber.Ctx, sanction *sanctions.Sanction) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"message":  "Tu cuenta tiene una sanción que no te permite publicar",
+		"sanction": sanction,
+	})
+}
+
 // AddLike agrega un like a un post.
 func (ph *PostHandler) GetPostByID(c *fiber.Ctx) error {
 	postIDStr := c.Params("postId")
@@ -150,7 +336,7 @@ func (ph *PostHandler) AddLike(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
 	}
 	if err := ph.PostService.AddLike(postID, userID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+		return c.Status(reactionErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Like added"})
 }
@@ -168,11 +354,34 @@ func (ph *PostHandler) Dislike(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
 	}
 	if err := ph.PostService.Dislike(postID, userID); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+		return c.Status(reactionErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Dislike added"})
 }
 
+// Unreact quita el like o dislike del usuario a un post.
+func (ph *PostHandler) Unreact(c *fiber.Ctx) error {
+	postID, err := primitive.ObjectIDFromHex(c.Params("postId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid post ID"})
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+	if err := ph.PostService.Unreact(postID, userID); err != nil {
+		return c.Status(reactionErrorStatus(err)).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Reaction removed"})
+}
+
+func reactionErrorStatus(err error) int {
+	if err.Error() == "post not found" {
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
+
 // AddComment agrega un comentario a un post.
 func (ph *PostHandler) AddComment(c *fiber.Ctx) error {
 	postIDStr := c.Params("postId")
@@ -180,13 +389,13 @@ func (ph *PostHandler) AddComment(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid post ID"})
 	}
-	type commentRequest struct {
-		Text string `json:"text"`
-	}
-	var req commentRequest
+	var req postdomain.CommentRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Bad Request"})
 	}
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Bad Request", "error": err.Error()})
+	}
 	idValue := c.Context().UserValue("_id").(string)
 
 	userID, err := primitive.ObjectIDFromHex(idValue)
@@ -194,14 +403,27 @@ func (ph *PostHandler) AddComment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
 	}
 	comment := postdomain.Comment{
-		UserID: userID,
-		Text:   req.Text,
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Text:     req.Text,
 	}
 	CommentId, err := ph.PostService.AddComment(postID, comment)
+	if sanction, ok := sanctions.As(err); ok {
+		return sanctionResponse(c, sanction)
+	}
+	if errors.Is(err, moderation.ErrRejected) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"message": "El comentario no cumple las normas de la comunidad", "error": err.Error()})
+	}
+	if errors.Is(err, moderation.ErrHeld) {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "El comentario quedó pendiente de revisión", "commentId": CommentId})
+	}
+	if err != nil && err.Error() == "post not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"message": err.Error()})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
 	}
-	commentRes, err := ph.PostService.GetCommentByID(CommentId)
+	commentRes, err := ph.PostService.GetCommentByID(CommentId, userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
 	}
@@ -239,6 +461,48 @@ func (ph *PostHandler) GetLatestPosts(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(posts)
 }
 
+// GetFeed devuelve una página del feed de la comunidad. mode puede ser latest, nearby (dentro del
+// radio guardado del usuario), following o trending; cursor es el nextCursor de la página anterior.
+func (ph *PostHandler) GetFeed(c *fiber.Ctx) error {
+	currentUserID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+
+	query := postdomain.FeedQuery{
+		Mode:     postdomain.FeedMode(c.Query("mode", string(postdomain.FeedLatest))),
+		Category: c.Query("category"),
+	}
+	if !query.Mode.Valid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid mode"})
+	}
+	query.Limit, err = strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || query.Limit < 1 {
+		query.Limit = 20
+	}
+	if query.Limit > 50 {
+		query.Limit = 50
+	}
+	if token := c.Query("cursor"); token != "" {
+		query.Cursor, err = postdomain.DecodeFeedCursor(token)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
+		}
+	}
+
+	page, err := ph.PostService.GetFeed(currentUserID, query)
+	if err != nil {
+		if err.Error() == "location required" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Set your location to see posts near you"})
+		}
+		if err.Error() == "invalid cursor" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(page)
+}
+
 // postinterfaces/post_handler.go
 func (ph *PostHandler) GetCommentsForPost(c *fiber.Ctx) error {
 	// Obtener el ID del post de los parámetros de la URL
@@ -259,8 +523,14 @@ func (ph *PostHandler) GetCommentsForPost(c *fiber.Ctx) error {
 		limit = 10 // Valor por defecto si no se pasa o es inválido
 	}
 
-	// Obtener los comentarios del servicio
-	comments, err := ph.PostService.GetCommentsForPost(postID, page, limit)
+	idValue := c.Context().UserValue("_id").(string)
+	userID, err := primitive.ObjectIDFromHex(idValue)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+
+	// Obtener los comentarios de primer nivel; las respuestas se piden por comentario
+	comments, err := ph.PostService.GetCommentsForPost(postID, userID, page, limit)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
 	}
@@ -4,7 +4,12 @@ import (
 	"back-end/internal/posts/postapplication"
 	"back-end/internal/posts/postinfrastructure"
 	"back-end/internal/posts/postinterfaces"
+	"back-end/pkg/mentions"
 	"back-end/pkg/middleware"
+	"back-end/pkg/outbox"
+	"context"
+	"fmt"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
@@ -15,12 +20,64 @@ func PostRoutes(App *fiber.App, redisClient *redis.Client, mongoClient *mongo.Cl
 	PostRepository := postinfrastructure.NewPostRepository(redisClient, mongoClient)
 	PostService := postapplication.NewPostService(PostRepository)
 	PostHandler := postinterfaces.NewPostHandler(PostService, mongoClient)
+	if err := PostRepository.EnsurePostIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de posts:", err)
+	}
+	// feed: los posts anteriores a la programación no tienen publishAt
+	if _, err := PostRepository.BackfillPublishAt(context.Background()); err != nil {
+		fmt.Println("Error completando publishAt de posts:", err)
+	}
+	if err := PostRepository.EnsureCommentIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de comentarios:", err)
+	}
+	// likes de comentarios: índice único y migración de los arrays likes antes de aceptar likes nuevos
+	if err := PostRepository.EnsureCommentReactionIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de likes de comentarios:", err)
+	}
+	if migrated, err := PostRepository.MigrateCommentLikes(context.Background()); err != nil {
+		fmt.Println("Error migrando likes de comentarios:", err)
+	} else if migrated > 0 {
+		fmt.Println("Likes migrados de", migrated, "comentarios")
+	}
+	// menciones: NameUser en minúsculas e indexado
+	if err := mentions.EnsureIndexes(context.Background(), mongoClient); err != nil {
+		fmt.Println("Error creando índices de menciones:", err)
+	}
+	// avisos a los usuarios mencionados en posts y comentarios
+	dispatcher := outbox.NewDispatcher(outbox.NewStore(mongoClient))
+	PostRepository.RegisterOutboxHandlers(dispatcher)
+	dispatcher.Start(5 * time.Second)
+	// reacciones: índice único y migración de los arrays likes/dislikes de posts anteriores; se
+	// completa antes de registrar las rutas para que React/Unreact no convivan con los arrays
+	if err := PostRepository.EnsureReactionIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices de reacciones:", err)
+	}
+	if migrated, err := PostRepository.MigrateReactionArrays(context.Background()); err != nil {
+		fmt.Println("Error migrando reacciones de posts:", err)
+	} else if migrated > 0 {
+		fmt.Println("Reacciones migradas de", migrated, "posts")
+	}
 
 	App.Post("/post/create", middleware.UseExtractor(), PostHandler.CreatePost)
 	App.Put("/post/:postId/like", middleware.UseExtractor(), PostHandler.AddLike)
 	App.Put("/post/:postId/dislike", middleware.UseExtractor(), PostHandler.Dislike)
+	App.Delete("/post/:postId/reaction", middleware.UseExtractor(), PostHandler.Unreact)
 	App.Post("/post/:postId/comment", middleware.UseExtractor(), PostHandler.AddComment)
 	App.Get("/post/latest", middleware.UseExtractor(), PostHandler.GetLatestPosts)
+	App.Get("/post/feed", middleware.UseExtractor(), PostHandler.GetFeed)
+	// hashtags y menciones
+	App.Get("/post/hashtag/:tag", middleware.UseExtractor(), PostHandler.GetPostsByHashtag)
+	App.Get("/post/mentions", middleware.UseExtractor(), PostHandler.GetMentions)
+	// edición, borrado y publicaciones programadas del autor
+	App.Get("/post/scheduled", middleware.UseExtractor(), PostHandler.GetScheduledPosts)
+	App.Put("/post/:postId", middleware.UseExtractor(), PostHandler.UpdatePost)
+	App.Delete("/post/:postId", middleware.UseExtractor(), PostHandler.DeletePost)
 	App.Get("/post/:postId/getPostId", middleware.UseExtractor(), PostHandler.GetPostByID)
 	App.Get("/post/:postId/comments", middleware.UseExtractor(), PostHandler.GetCommentsForPost)
+	// respuestas, edición, borrado y likes de comentarios
+	App.Get("/post/comments/:commentId/replies", middleware.UseExtractor(), PostHandler.GetCommentReplies)
+	App.Put("/post/comments/:commentId", middleware.UseExtractor(), PostHandler.EditComment)
+	App.Delete("/post/comments/:commentId", middleware.UseExtractor(), PostHandler.DeleteComment)
+	App.Put("/post/comments/:commentId/like", middleware.UseExtractor(), PostHandler.LikeComment)
+	App.Delete("/post/comments/:commentId/like", middleware.UseExtractor(), PostHandler.UnlikeComment)
 }
@@ -17,7 +17,8 @@ import (
 // SupportRoutes configura las rutas del módulo de chat de soporte.
 func SupportRoutes(app *fiber.App, redisClient *redis.Client, mongoClient *mongo.Client) {
 	supportRepo := supportinfrastructure.NewSupportRepository(mongoClient, redisClient)
-	userRepo := userinfrastructure.NewUserRepository(nil, mongoClient)
+	// Redis se usa para el bloqueo por intentos fallidos de las apelaciones con credenciales
+	userRepo := userinfrastructure.NewUserRepository(redisClient, mongoClient)
 	supportService := supportapplication.NewSupportService(supportRepo, userRepo)
 	supportHandler := supportinterfaces.NewSupportHandler(supportService)
 
@@ -28,6 +29,10 @@ func SupportRoutes(app *fiber.App, redisClient *redis.Client, mongoClient *mongo
 	supportGroup.Get("/GetSupportAgent", middleware.UseExtractor(), supportHandler.GetSupportAgent)
 	supportGroup.Get("/conversations", middleware.UseExtractor(), supportHandler.GetConversationsForSupport)
 
+	// apelaciones de sanciones; la segunda ruta es para los usuarios suspendidos o baneados, que no tienen token
+	supportGroup.Post("/sanctions/:id/appeal", middleware.UseExtractorAllowSanctioned(), supportHandler.AppealSanction)
+	supportGroup.Post("/sanctions/:id/appeal-with-credentials", supportHandler.AppealSanctionWithCredentials)
+
 	// room = fmt.Sprintf("support:conversation:%s", senderID.Hex()+receiverID.Hex()) send = soporte
 	supportGroup.Get("/subscribe/:supportID", websocket.New(supportHandler.SubscribeSupportMessages))
 
@@ -3,16 +3,24 @@ package supportapplication
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"back-end/internal/support/supportdomain"
 	supportinfrastructure "back-end/internal/support/supportinfrastructure"
 	userdomain "back-end/internal/user/user-domain"
 	userinfrastructure "back-end/internal/user/user-infrastructure"
+	"back-end/pkg/helpers"
+	"back-end/pkg/sanctions"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var (
+	ErrInvalidCredentials = errors.New("usuario o contraseña incorrectos")
+	ErrTooManyAttempts    = errors.New("demasiados intentos fallidos, intenta más tarde")
+)
+
 // SupportService gestiona la lógica de negocio del chat de soporte.
 type SupportService struct {
 	SupportRepo *supportinfrastructure.SupportRepository
@@ -73,6 +81,50 @@ func (s *SupportService) SendMessage(ctx context.Context, msg supportdomain.Supp
 	return s.SupportRepo.PublishMessage(ctx, msg, room)
 }
 
+// AppealSanction registra la apelación de userID sobre una de sus sanciones vigentes y la envía como
+// mensaje a su agente de soporte. Si el mensaje no se puede enviar, la apelación se borra para que
+// el usuario pueda reintentarla.
+func (s *SupportService) AppealSanction(ctx context.Context, userID, sanctionID primitive.ObjectID, text string) (*sanctions.Sanction, error) {
+	sanction, err := s.SupportRepo.AppealSanction(ctx, sanctionID, userID, text)
+	if err != nil {
+		return nil, err
+	}
+	msg := supportdomain.SupportMessage{
+		SenderID:   userID,
+		Text:       fmt.Sprintf("Apelación de la sanción %s (%s): %s", sanction.ID.Hex(), sanction.Type, sanction.Appeal.Text),
+		SanctionID: &sanction.ID,
+	}
+	if err := s.SendMessage(ctx, msg, ""); err != nil {
+		if withdrawErr := s.SupportRepo.WithdrawSanctionAppeal(ctx, sanctionID, userID); withdrawErr != nil {
+			return nil, fmt.Errorf("%v (y no se pudo borrar la apelación: %v)", err, withdrawErr)
+		}
+		return nil, err
+	}
+	return sanction, nil
+}
+
+// AppealSanctionWithCredentials permite apelar a un usuario suspendido o baneado, que no puede
+// iniciar sesión: se autentica con su usuario y contraseña, con el mismo bloqueo por intentos
+// fallidos que el login.
+func (s *SupportService) AppealSanctionWithCredentials(ctx context.Context, nameUser, password string, sanctionID primitive.ObjectID, text string) (*sanctions.Sanction, error) {
+	blocked, err := s.UserRepo.IsUserBlocked(nameUser)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ErrTooManyAttempts
+	}
+	user, err := s.UserRepo.FindNameUserInternalOperation(nameUser, "")
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if err := helpers.DecodePassword(user.PasswordHash, password); err != nil {
+		s.UserRepo.HandleLoginFailure(nameUser)
+		return nil, ErrInvalidCredentials
+	}
+	return s.AppealSanction(ctx, user.ID, sanctionID, text)
+}
+
 // GetMessagesBetween obtiene los mensajes entre un usuario y un agente de soporte.
 func (s *SupportService) GetMessagesBetween(ctx context.Context, userID, supportID string) ([]supportdomain.SupportMessage, error) {
 	return s.SupportRepo.GetMessagesBetween(ctx, userID, supportID)
@@ -15,6 +15,8 @@ type SupportMessage struct {
 	Text       string             `bson:"text" json:"text"`
 	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
 	IsRead     bool               `bson:"isRead" json:"isRead"`
+	// SanctionID marca los mensajes que son la apelación de una sanción
+	SanctionID *primitive.ObjectID `bson:"sanctionId,omitempty" json:"sanctionId,omitempty"`
 }
 
 type UserInfo struct {
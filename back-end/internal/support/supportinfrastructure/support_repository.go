@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"back-end/internal/support/supportdomain"
+	"back-end/pkg/sanctions"
 
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,6 +21,7 @@ import (
 type SupportRepository struct {
 	mongoClient *mongo.Client
 	redisClient *redis.Client
+	sanctions   *sanctions.Store
 }
 
 // NewSupportRepository crea una nueva instancia de SupportRepository.
@@ -27,9 +29,20 @@ func NewSupportRepository(mongoClient *mongo.Client, redisClient *redis.Client)
 	return &SupportRepository{
 		mongoClient: mongoClient,
 		redisClient: redisClient,
+		sanctions:   sanctions.NewStore(mongoClient),
 	}
 }
 
+// AppealSanction registra la apelación de userID sobre una de sus sanciones vigentes.
+func (r *SupportRepository) AppealSanction(ctx context.Context, sanctionID, userID primitive.ObjectID, text string) (*sanctions.Sanction, error) {
+	return r.sanctions.Appeal(ctx, sanctionID, userID, text)
+}
+
+// WithdrawSanctionAppeal borra una apelación que no llegó a la cola de soporte.
+func (r *SupportRepository) WithdrawSanctionAppeal(ctx context.Context, sanctionID, userID primitive.ObjectID) error {
+	return r.sanctions.WithdrawAppeal(ctx, sanctionID, userID)
+}
+
 // SendMessage guarda un mensaje en la colección "support_messages" y lo publica en Redis.
 func (r *SupportRepository) SendMessage(ctx context.Context, msg supportdomain.SupportMessage) (supportdomain.SupportMessage, error) {
 	collection := r.mongoClient.Database("NEXO-VECINAL").Collection("support_messages")
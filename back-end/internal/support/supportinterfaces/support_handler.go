@@ -5,6 +5,7 @@ import (
 	"back-end/internal/support/supportapplication"
 	"back-end/internal/support/supportdomain"
 	"context"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
@@ -49,6 +50,57 @@ func (h *SupportHandler) SendSupportMessage(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "mensaje enviado"})
 }
 
+// AppealSanction endpoint para apelar una sanción vigente del usuario del token.
+// Se espera en el body JSON: text. La apelación llega como mensaje al agente de soporte asignado.
+func (h *SupportHandler) AppealSanction(c *fiber.Ctx) error {
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	sanctionID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "id de sanción inválido"})
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "id de usuario inválido"})
+	}
+	sanction, err := h.SupportService.AppealSanction(context.Background(), userID, sanctionID, req.Text)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "apelación enviada", "data": sanction})
+}
+
+// AppealSanctionWithCredentials endpoint para que un usuario suspendido o baneado apele sin token.
+// Se espera en el body JSON: NameUser, Password y text.
+func (h *SupportHandler) AppealSanctionWithCredentials(c *fiber.Ctx) error {
+	var req struct {
+		NameUser string `json:"NameUser"`
+		Password string `json:"Password"`
+		Text     string `json:"text"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.NameUser == "" || req.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "input inválido"})
+	}
+	sanctionID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "id de sanción inválido"})
+	}
+	sanction, err := h.SupportService.AppealSanctionWithCredentials(context.Background(), req.NameUser, req.Password, sanctionID, req.Text)
+	switch {
+	case errors.Is(err, supportapplication.ErrInvalidCredentials):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, supportapplication.ErrTooManyAttempts):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "apelación enviada", "data": sanction})
+}
+
 // GetSupportMessages endpoint para obtener los mensajes entre un usuario y un agente de soporte.
 func (h *SupportHandler) GetSupportMessages(c *fiber.Ctx) error {
 	userID := c.Query("user")
@@ -0,0 +1,26 @@
+package userapplication
+
+import (
+	domain "back-end/internal/user/user-domain"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetAvailability devuelve el calendario publicado por el trabajador; nil si no lo publicó.
+func (u *UserService) GetAvailability(userID primitive.ObjectID) (*domain.Availability, error) {
+	return u.roomRepository.GetAvailability(context.Background(), userID)
+}
+
+// UpdateAvailability reemplaza el calendario semanal y los bloqueos del trabajador.
+func (u *UserService) UpdateAvailability(userID primitive.ObjectID, availability domain.Availability) error {
+	if availability.Weekly == nil {
+		availability.Weekly = []domain.WeeklySlot{}
+	}
+	if availability.Blackouts == nil {
+		availability.Blackouts = []domain.Blackout{}
+	}
+	availability.UpdatedAt = time.Now()
+	return u.roomRepository.SaveAvailability(context.Background(), userID, availability)
+}
@@ -0,0 +1,25 @@
+package userapplication
+
+import (
+	userdomain "back-end/internal/user/user-domain"
+	"back-end/pkg/geoprivacy"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// La ubicación exacta del usuario solo se devuelve al propio usuario; en perfiles y búsquedas
+// públicas se muestra una ubicación aproximada y estable. Las consultas usan el punto exacto.
+func approximateUserLocation(userID primitive.ObjectID, location *userdomain.GeoPoint) {
+	location.Coordinates = geoprivacy.Approximate(userID, location.Coordinates)
+	location.Approximate = true
+}
+
+// FindPublicUserById devuelve el perfil de un usuario tal como lo ven los demás.
+func (u *UserService) FindPublicUserById(id primitive.ObjectID) (*userdomain.User, error) {
+	user, err := u.roomRepository.FindUserById(id)
+	if err != nil {
+		return nil, err
+	}
+	approximateUserLocation(user.ID, &user.Location)
+	return user, nil
+}
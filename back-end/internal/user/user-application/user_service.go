@@ -6,6 +6,7 @@ import (
 	userdomain "back-end/internal/user/user-domain"
 	infrastructure "back-end/internal/user/user-infrastructure"
 	"back-end/pkg/authGoogleAuthenticator"
+	"back-end/pkg/sanctions"
 	"context"
 	"time"
 
@@ -36,12 +37,15 @@ func (u *UserService) GenerateTOTPKey(ctx context.Context, userID primitive.Obje
 func (u *UserService) SavePushToken(id primitive.ObjectID, PushToken string) error {
 	return u.roomRepository.SavePushToken(id, PushToken)
 }
+func (u *UserService) RemovePushToken(id primitive.ObjectID, PushToken string) error {
+	return u.roomRepository.RemovePushToken(id, PushToken)
+}
 
 func (u *UserService) UserMetricts(user *domain.User, Intentions, Referral string) error {
 	return u.roomRepository.UserMetricts(user, Intentions, Referral)
 }
-func (u *UserService) UserPremiumAmonth(id primitive.ObjectID) error {
-	return u.roomRepository.UserPremiumExtend(id)
+func (u *UserService) UserPremiumAmonth(id primitive.ObjectID, event, productID string) error {
+	return u.roomRepository.UserPremiumExtend(id, event, productID)
 }
 func (u *UserService) UpdateRecommendedWorkerPremium(id primitive.ObjectID) error {
 	return u.roomRepository.UpdateRecommendedWorkerPremium(id)
@@ -184,6 +188,16 @@ func (u *UserService) IsUserBlocked(NameUser string) (bool, error) {
 	user, err := u.roomRepository.IsUserBlocked(NameUser)
 	return user, err
 }
+
+// CheckLoginSanctions devuelve un error de sanctions si el usuario está suspendido o baneado.
+func (u *UserService) CheckLoginSanctions(userID primitive.ObjectID) error {
+	return u.roomRepository.CheckSanctions(context.Background(), userID, sanctions.ScopeLogin)
+}
+
+// GetActiveSanctions devuelve las sanciones vigentes del usuario.
+func (u *UserService) GetActiveSanctions(userID primitive.ObjectID) ([]sanctions.Sanction, error) {
+	return u.roomRepository.ActiveSanctions(context.Background(), userID)
+}
 func (u *UserService) HandleLoginFailure(NameUser string) error {
 
 	return u.roomRepository.HandleLoginFailure(NameUser)
@@ -237,7 +251,14 @@ func (u *UserService) SaveLocationTags(id primitive.ObjectID, location userdomai
 	return u.roomRepository.SaveLocationTags(id, location)
 }
 func (u *UserService) GetFilteredUsers(location userdomain.ReqLocationTags) ([]userdomain.User, error) {
-	return u.roomRepository.GetFilteredUsers(location)
+	users, err := u.roomRepository.GetFilteredUsers(location)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		approximateUserLocation(users[i].ID, &users[i].Location)
+	}
+	return users, nil
 }
 func (u *UserService) FindUsersByNameTagOrLocation(
 	nameUser string,
@@ -245,6 +266,25 @@ func (u *UserService) FindUsersByNameTagOrLocation(
 	location *domain.GeoPoint,
 	radiusInMeters float64,
 	page int,
+	availableAt *domain.TimeSlot,
+	viewerID primitive.ObjectID,
 ) ([]domain.GetUser, error) {
-	return u.roomRepository.FindUsersByNameTagOrLocation(nameUser, tags, location, radiusInMeters, page)
+	users, err := u.roomRepository.FindUsersByNameTagOrLocation(nameUser, tags, location, radiusInMeters, page, availableAt)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, len(users))
+	for i := range users {
+		approximateUserLocation(users[i].ID, &users[i].Location)
+		ids[i] = users[i].ID
+	}
+	// Sin usuario logueado ningún resultado es favorito
+	favorites, err := u.roomRepository.FavoriteWorkerIDs(context.Background(), viewerID, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		users[i].IsFavorite = favorites[users[i].ID]
+	}
+	return users, nil
 }
@@ -0,0 +1,90 @@
+package userdomain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	_ "time/tzdata" // Zonas horarias disponibles aunque el contenedor no las traiga
+
+	"github.com/go-playground/validator"
+)
+
+// WeeklySlot es una franja semanal en la que el trabajador acepta trabajos, en su hora local.
+type WeeklySlot struct {
+	Weekday int    `json:"weekday" bson:"weekday" validate:"min=0,max=6"` // 0 = domingo, como time.Weekday
+	Start   string `json:"start" bson:"start" validate:"required"`        // "HH:MM"
+	End     string `json:"end" bson:"end" validate:"required"`            // "HH:MM", mayor que Start
+}
+
+// Blackout es un rango de días locales (inclusivo) en el que el trabajador no está disponible.
+type Blackout struct {
+	From   string `json:"from" bson:"from" validate:"required"` // "YYYY-MM-DD"
+	To     string `json:"to" bson:"to" validate:"required"`     // "YYYY-MM-DD"
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty" validate:"max=100"`
+}
+
+// Availability es el calendario semanal publicado por el trabajador.
+// Los usuarios sin calendario se consideran disponibles en cualquier horario.
+type Availability struct {
+	Timezone  string       `json:"timezone" bson:"timezone" validate:"max=64"` // Zona IANA; por defecto UTC
+	Weekly    []WeeklySlot `json:"weekly" bson:"weekly" validate:"max=50,dive"`
+	Blackouts []Blackout   `json:"blackouts" bson:"blackouts" validate:"max=100,dive"`
+	UpdatedAt time.Time    `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Validate valida el formato de horas y fechas y la zona horaria. Las horas se guardan como
+// "HH:MM" con ceros a la izquierda ("9:00" pasa a "09:00") para poder compararlas como texto.
+func (a *Availability) Validate() error {
+	if err := validator.New().Struct(a); err != nil {
+		return err
+	}
+	if a.Timezone == "" {
+		a.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(a.Timezone); err != nil {
+		return errors.New("zona horaria inválida")
+	}
+	for i, slot := range a.Weekly {
+		start, err := time.Parse("15:04", slot.Start)
+		if err != nil {
+			return fmt.Errorf("hora de inicio inválida: %s", slot.Start)
+		}
+		end, err := time.Parse("15:04", slot.End)
+		if err != nil {
+			return fmt.Errorf("hora de fin inválida: %s", slot.End)
+		}
+		if !end.After(start) {
+			return errors.New("cada franja debe terminar después de empezar")
+		}
+		a.Weekly[i].Start = start.Format("15:04")
+		a.Weekly[i].End = end.Format("15:04")
+	}
+	for _, blackout := range a.Blackouts {
+		from, err := time.Parse("2006-01-02", blackout.From)
+		if err != nil {
+			return fmt.Errorf("fecha inválida: %s", blackout.From)
+		}
+		to, err := time.Parse("2006-01-02", blackout.To)
+		if err != nil {
+			return fmt.Errorf("fecha inválida: %s", blackout.To)
+		}
+		if to.Before(from) {
+			return errors.New("cada bloqueo debe terminar después de empezar")
+		}
+	}
+	return nil
+}
+
+// TimeSlot es un horario concreto [Start, End) usado para filtrar trabajadores disponibles.
+type TimeSlot struct {
+	Start time.Time `json:"start" bson:"start"`
+	End   time.Time `json:"end" bson:"end"`
+}
+
+// Validate exige que el horario termine después de empezar.
+func (s TimeSlot) Validate() error {
+	if !s.End.After(s.Start) {
+		return errors.New("el horario debe terminar después de empezar")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package userdomain
 
 import (
+	"back-end/internal/notifications/notificationdomain"
 	"fmt"
 	"regexp"
 	"time"
@@ -13,6 +14,7 @@ import (
 type GeoPoint struct {
 	Type        string    `bson:"type" json:"type"`               // Siempre "Point"
 	Coordinates []float64 `bson:"coordinates" json:"coordinates"` // [longitud, latitud]
+	Approximate bool      `bson:"-" json:"approximate,omitempty"` // true si las coordenadas fueron desplazadas por privacidad
 }
 type User struct {
 	ID           primitive.ObjectID     `json:"id" bson:"_id,omitempty"`
@@ -54,6 +56,7 @@ type User struct {
 	Followers             map[primitive.ObjectID]FollowInfo `json:"Followers" bson:"Followers"`
 	Timestamp             time.Time                         `json:"Timestamp" bson:"Timestamp"`
 	Banned                bool                              `json:"Banned" bson:"Banned"`
+	NameUserLower         string                            `json:"-" bson:"nameUserLower"` // Para resolver menciones, ver mentions.NameField
 	TOTPSecret            string                            `json:"TOTPSecret" bson:"TOTPSecret"`
 	LastConnection        time.Time                         `json:"LastConnection" bson:"LastConnection"`
 	Premium               Premium                           `json:"Premium" bson:"Premium"`
@@ -67,11 +70,16 @@ type User struct {
 	Soporte         string             `json:"Soporte" bson:"Soporte"`
 	SoporteAssigned primitive.ObjectID `bson:"soporteassigned"`
 	PushToken       string             `json:"pushToken" bson:"pushToken"`
-	Tags            []string           `json:"tags" bson:"tags"`
-	Location        GeoPoint           `json:"location" bson:"location"`
-	Ratio           float64            `json:"Ratio" bson:"ratio"`
-	AvailableToWork bool               `json:"availableToWork" bson:"availableToWork"`
-	Intentions      string             `json:"Intentions" bson:"Intentions"` // hire work
+	PushTokens      []string           `json:"-" bson:"pushTokens"` // Todos los dispositivos registrados
+	// NotificationPreferences es nil mientras el usuario no configure sus notificaciones
+	NotificationPreferences *notificationdomain.Preferences `json:"notificationPreferences,omitempty" bson:"notificationPreferences,omitempty"`
+	Tags                    []string                        `json:"tags" bson:"tags"`
+	Location                GeoPoint                        `json:"location" bson:"location"`
+	Ratio                   float64                         `json:"Ratio" bson:"ratio"`
+	AvailableToWork         bool                            `json:"availableToWork" bson:"availableToWork"`
+	Intentions              string                          `json:"Intentions" bson:"Intentions"` // hire work
+	// Availability es nil mientras el trabajador no publique su calendario
+	Availability *Availability `json:"availability,omitempty" bson:"availability,omitempty"`
 }
 
 type Premium struct {
@@ -79,6 +87,22 @@ type Premium struct {
 	SubscriptionStart time.Time `bson:"SubscriptionStart"`
 	SubscriptionEnd   time.Time `bson:"SubscriptionEnd"`
 }
+
+// PremiumEventBackfill es el evento de las entradas creadas a partir del premium vigente de los
+// usuarios que se suscribieron antes de que existiera el historial.
+const PremiumEventBackfill = "BACKFILL"
+
+// PremiumHistoryEntry registra cada compra o renovación de premium con el período resultante.
+type PremiumHistoryEntry struct {
+	ID                primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID            primitive.ObjectID `json:"userId" bson:"userId"`
+	Event             string             `json:"event" bson:"event"` // Tipo de evento de RevenueCat
+	ProductID         string             `json:"productId,omitempty" bson:"productId,omitempty"`
+	SubscriptionStart time.Time          `json:"subscriptionStart" bson:"subscriptionStart"`
+	SubscriptionEnd   time.Time          `json:"subscriptionEnd" bson:"subscriptionEnd"`
+	MonthsSubscribed  int                `json:"monthsSubscribed" bson:"monthsSubscribed"`
+	CreatedAt         time.Time          `json:"createdAt" bson:"createdAt"`
+}
 type FollowInfo struct {
 	Since         time.Time `json:"since" bson:"since"`
 	Notifications bool      `json:"notifications" bson:"notifications"`
@@ -280,6 +304,7 @@ type GetUser struct {
 	AvailableToWork     bool               `json:"availableToWork" bson:"availableToWork"`
 	Intentions          string             `json:"Intentions" bson:"Intentions"`
 	CompletedJobs       int                `json:"completedJobs" bson:"completedJobs"`
+	IsFavorite          bool               `json:"isFavorite" bson:"-"` // El usuario está en los trabajadores favoritos de quien consulta
 }
 type UserInfoOAuth2 struct {
 	ID      string `json:"id"`
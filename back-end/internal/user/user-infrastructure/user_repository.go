@@ -1,11 +1,16 @@
 package userinfrastructure
 
 import (
+	"back-end/internal/favorites/favoritesinfrastructure"
 	domain "back-end/internal/user/user-domain"
 	userdomain "back-end/internal/user/user-domain"
 	"back-end/pkg/authGoogleAuthenticator"
+	"back-end/pkg/availability"
 	"back-end/pkg/helpers"
+	"back-end/pkg/mentions"
 	"back-end/pkg/metrics"
+	"back-end/pkg/outbox"
+	"back-end/pkg/sanctions"
 	"math/rand"
 
 	"context"
@@ -26,15 +31,36 @@ import (
 type UserRepository struct {
 	redisClient *redis.Client
 	mongoClient *mongo.Client
+	// favorites resuelve si un usuario está en los trabajadores favoritos de quien consulta
+	favorites *favoritesinfrastructure.FavoriteRepository
+	// sanctions resuelve las sanciones vigentes que se aplican en el login
+	sanctions *sanctions.Store
 }
 
 func NewUserRepository(redisClient *redis.Client, mongoClient *mongo.Client) *UserRepository {
 	return &UserRepository{
 		redisClient: redisClient,
 		mongoClient: mongoClient,
+		favorites:   favoritesinfrastructure.NewFavoriteRepository(mongoClient),
+		sanctions:   sanctions.NewStore(mongoClient),
 	}
 }
 
+// CheckSanctions devuelve un error de sanctions si una sanción vigente de userID impide la acción.
+func (u *UserRepository) CheckSanctions(ctx context.Context, userID primitive.ObjectID, scope sanctions.Scope) error {
+	return u.sanctions.Check(ctx, userID, scope)
+}
+
+// ActiveSanctions devuelve las sanciones vigentes de userID, las más severas primero.
+func (u *UserRepository) ActiveSanctions(ctx context.Context, userID primitive.ObjectID) ([]sanctions.Sanction, error) {
+	return u.sanctions.Active(ctx, userID)
+}
+
+// FavoriteWorkerIDs indica cuáles de los usuarios dados son trabajadores favoritos de userID.
+func (u *UserRepository) FavoriteWorkerIDs(ctx context.Context, userID primitive.ObjectID, workerIDs []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	return u.favorites.FavoriteWorkerIDs(ctx, userID, workerIDs)
+}
+
 func (u *UserRepository) IsUserBlocked(nameUser string) (bool, error) {
 	blockKey := fmt.Sprintf("login_blocked:%s", nameUser)
 
@@ -100,52 +126,97 @@ func (u *UserRepository) SetTOTPSecret(ctx context.Context, userID primitive.Obj
 func (u *UserRepository) SavePushToken(userID primitive.ObjectID, pushToken string) error {
 	ctx := context.Background()
 	usersCollection := u.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	// Un dispositivo pertenece a una sola cuenta: si cambió de usuario se quita del anterior
+	if _, err := usersCollection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$ne": userID}, "pushTokens": pushToken},
+		bson.M{"$pull": bson.M{"pushTokens": pushToken}},
+	); err != nil {
+		return err
+	}
+	if _, err := usersCollection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$ne": userID}, "pushToken": pushToken},
+		bson.M{"$set": bson.M{"pushToken": ""}},
+	); err != nil {
+		return err
+	}
 	filter := bson.M{"_id": userID}
-	update := bson.M{"$set": bson.M{"pushToken": pushToken}}
+	// pushToken conserva el último dispositivo; pushTokens guarda todos los dispositivos del usuario
+	update := bson.M{
+		"$set":      bson.M{"pushToken": pushToken},
+		"$addToSet": bson.M{"pushTokens": pushToken},
+	}
 	_, err := usersCollection.UpdateOne(ctx, filter, update)
 	return err
 }
 
-func (u *UserRepository) UserPremiumExtend(userID primitive.ObjectID) error {
+// RemovePushToken elimina un dispositivo del usuario (por ejemplo al cerrar sesión).
+func (u *UserRepository) RemovePushToken(userID primitive.ObjectID, pushToken string) error {
 	ctx := context.Background()
 	usersCollection := u.mongoClient.Database("NEXO-VECINAL").Collection("Users")
-
-	// Traer el usuario actual
-	var user struct {
-		Premium struct {
-			SubscriptionStart time.Time `bson:"SubscriptionStart"`
-			SubscriptionEnd   time.Time `bson:"SubscriptionEnd"`
-			MonthsSubscribed  int       `bson:"MonthsSubscribed"`
-		} `bson:"Premium"`
-	}
-	err := usersCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	_, err := usersCollection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$pull": bson.M{"pushTokens": pushToken}})
 	if err != nil {
 		return err
 	}
+	_, err = usersCollection.UpdateOne(ctx, bson.M{"_id": userID, "pushToken": pushToken}, bson.M{"$set": bson.M{"pushToken": ""}})
+	return err
+}
 
-	now := time.Now()
+// UserPremiumExtend suma un mes de premium y registra el período en el historial "premium_history".
+// La extensión y su historial se confirman en la misma transacción.
+func (u *UserRepository) UserPremiumExtend(userID primitive.ObjectID, event, productID string) error {
+	ctx := context.Background()
+	usersCollection := u.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	historyCollection := u.mongoClient.Database("NEXO-VECINAL").Collection("premium_history")
+
+	return outbox.NewStore(u.mongoClient).WithTransaction(ctx, func(sessCtx mongo.SessionContext) error {
+		// Traer el usuario actual
+		var user struct {
+			Premium struct {
+				SubscriptionStart time.Time `bson:"SubscriptionStart"`
+				SubscriptionEnd   time.Time `bson:"SubscriptionEnd"`
+				MonthsSubscribed  int       `bson:"MonthsSubscribed"`
+			} `bson:"Premium"`
+		}
+		err := usersCollection.FindOne(sessCtx, bson.M{"_id": userID}).Decode(&user)
+		if err != nil {
+			return err
+		}
 
-	start := user.Premium.SubscriptionStart
-	if start.IsZero() || start.Year() <= 1 {
-		start = now
-	}
+		now := time.Now()
 
-	end := user.Premium.SubscriptionEnd
-	if end.IsZero() || end.Year() <= 1 {
-		end = now
-	}
-	newEnd := end.AddDate(0, 1, 0) // +1 mes
-	newMontch := user.Premium.MonthsSubscribed + 1
-	update := bson.M{
-		"$set": bson.M{
-			"Premium.SubscriptionStart": start,
-			"Premium.SubscriptionEnd":   newEnd,
-			"Premium.MonthsSubscribed":  newMontch,
-		},
-	}
+		start := user.Premium.SubscriptionStart
+		if start.IsZero() || start.Year() <= 1 {
+			start = now
+		}
 
-	_, err = usersCollection.UpdateOne(ctx, bson.M{"_id": userID}, update)
-	return err
+		end := user.Premium.SubscriptionEnd
+		if end.IsZero() || end.Year() <= 1 {
+			end = now
+		}
+		newEnd := end.AddDate(0, 1, 0) // +1 mes
+		newMontch := user.Premium.MonthsSubscribed + 1
+		update := bson.M{
+			"$set": bson.M{
+				"Premium.SubscriptionStart": start,
+				"Premium.SubscriptionEnd":   newEnd,
+				"Premium.MonthsSubscribed":  newMontch,
+			},
+		}
+
+		if _, err := usersCollection.UpdateOne(sessCtx, bson.M{"_id": userID}, update); err != nil {
+			return err
+		}
+		_, err = historyCollection.InsertOne(sessCtx, userdomain.PremiumHistoryEntry{
+			UserID:            userID,
+			Event:             event,
+			ProductID:         productID,
+			SubscriptionStart: start,
+			SubscriptionEnd:   newEnd,
+			MonthsSubscribed:  newMontch,
+			CreatedAt:         now,
+		})
+		return err
+	})
 }
 
 func (j *UserRepository) UpdateRecommendedWorkerPremium(workerId primitive.ObjectID) error {
@@ -273,12 +344,11 @@ func (u *UserRepository) ChangeNameUser(changeNameUser domain.ChangeNameUser) er
 	return nil
 }
 
-func (u *UserRepository) ChangeNameUserCodeAdmin(changeNameUser domain.ChangeNameUser, id primitive.ObjectID) error {
+func (u *UserRepository) ChangeNameUserCodeAdmin(ctx context.Context, changeNameUser domain.ChangeNameUser, id primitive.ObjectID) error {
 	err := u.AutCode(id, changeNameUser.Code)
 	if err != nil {
 		return err
 	}
-	ctx := context.TODO()
 	db := u.mongoClient.Database("NEXO-VECINAL")
 	if !u.doesUserExist(ctx, db, changeNameUser.NameUserRemove) {
 		return fmt.Errorf("NameUserRemove does not exist")
@@ -349,6 +419,7 @@ func (u *UserRepository) updateUserNames(ctx context.Context, db *mongo.Database
 	updateTemp := bson.M{
 		"$set": bson.M{
 			"NameUser":              changeNameUser.NameUserNew,
+			mentions.NameField:      strings.ToLower(changeNameUser.NameUserNew),
 			"EditProfiile.NameUser": time.Now(), // Actualizamos la fecha de la última modificación del nombre de usuario
 		},
 	}
@@ -365,7 +436,10 @@ func (u *UserRepository) updateUserNamesAdmin(ctx context.Context, db *mongo.Dat
 	GoMongoDBCollUsers := db.Collection("Users")
 
 	userFilterTemp := bson.M{"NameUser": changeNameUser.NameUserRemove}
-	updateTemp := bson.M{"$set": bson.M{"NameUser": changeNameUser.NameUserNew}}
+	updateTemp := bson.M{"$set": bson.M{
+		"NameUser":         changeNameUser.NameUserNew,
+		mentions.NameField: strings.ToLower(changeNameUser.NameUserNew),
+	}}
 	_, err := GoMongoDBCollUsers.UpdateOne(ctx, userFilterTemp, updateTemp)
 	if err != nil {
 		return fmt.Errorf("error updating user collection to NameUserNew: %v", err)
@@ -508,6 +582,7 @@ func (u *UserRepository) GetUserByCodeFromRedis(code string) (*domain.User, erro
 func (u *UserRepository) SaveUser(User *domain.User) (primitive.ObjectID, error) {
 
 	GoMongoDBCollUsers := u.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	User.NameUserLower = strings.ToLower(User.NameUser)
 
 	insertResult, errInsertOne := GoMongoDBCollUsers.InsertOne(context.Background(), User)
 	if errInsertOne != nil {
@@ -1547,6 +1622,7 @@ func (u *UserRepository) FindUsersByNameTagOrLocation(
 	location *userdomain.GeoPoint, // puede ser nil si no se busca por ubicación
 	radiusInMeters float64, // ignorado si location es nil
 	page int,
+	availableAt *userdomain.TimeSlot, // nil si no se filtra por disponibilidad
 ) ([]userdomain.GetUser, error) {
 	usersCollection := u.mongoClient.Database("NEXO-VECINAL").Collection("Users")
 	filter := bson.M{
@@ -1590,6 +1666,11 @@ func (u *UserRepository) FindUsersByNameTagOrLocation(
 		})
 	}
 
+	// Buscar por disponibilidad en un horario concreto
+	if availableAt != nil {
+		andFilters = append(andFilters, availability.Filter("availability", availableAt.Start, availableAt.End))
+	}
+
 	if len(andFilters) > 0 {
 		filter["$and"] = andFilters
 	}
@@ -1624,3 +1705,29 @@ func (u *UserRepository) FindUsersByNameTagOrLocation(
 	}
 	return users, nil
 }
+
+// GetAvailability devuelve el calendario del usuario; nil si no lo publicó.
+func (u *UserRepository) GetAvailability(ctx context.Context, userID primitive.ObjectID) (*userdomain.Availability, error) {
+	usersCollection := u.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	var user struct {
+		Availability *userdomain.Availability `bson:"availability"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"availability": 1})
+	if err := usersCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		return nil, err
+	}
+	return user.Availability, nil
+}
+
+// SaveAvailability reemplaza el calendario del usuario.
+func (u *UserRepository) SaveAvailability(ctx context.Context, userID primitive.ObjectID, a userdomain.Availability) error {
+	usersCollection := u.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	res, err := usersCollection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"availability": a}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("usuario no encontrado")
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"back-end/pkg/auth"
 	"back-end/pkg/helpers"
 	"back-end/pkg/jwt"
+	"back-end/pkg/sanctions"
 	"context"
 	"fmt"
 	"os"
@@ -126,7 +127,12 @@ func (h *UserHandler) Google_callback(c *fiber.Ctx) error {
 		})
 	}
 
-	// 5) Usuario completo: generar JWT interno
+	// 5) Un usuario suspendido o baneado no recibe token
+	if err := h.userService.CheckLoginSanctions(user.ID); err != nil {
+		return loginSanctionResponse(c, err)
+	}
+
+	// 6) Usuario completo: generar JWT interno
 	tokenStr, err := jwt.CreateToken(user)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -193,6 +199,9 @@ func (h *UserHandler) LoginTOTPSecret(c *fiber.Ctx) error {
 			"data":    err,
 		})
 	}
+	if err := h.userService.CheckLoginSanctions(user.ID); err != nil {
+		return loginSanctionResponse(c, err)
+	}
 	token, err := jwt.CreateToken(user)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -219,6 +228,33 @@ func (h *UserHandler) GoogleLogin(c *fiber.Ctx) error {
 	})
 }
 
+// loginSanctionResponse responde 403 con la sanción que impide iniciar sesión, para que el usuario
+// sepa el motivo, hasta cuándo dura y con qué id puede apelarla desde soporte.
+func loginSanctionResponse(c *fiber.Ctx, err error) error {
+	if sanction, ok := sanctions.As(err); ok {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message":  "sanctioned",
+			"sanction": sanction,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"message": "Internal Server Error",
+	})
+}
+
+// GetActiveSanctions devuelve las sanciones vigentes del usuario del token.
+func (h *UserHandler) GetActiveSanctions(c *fiber.Ctx) error {
+	userID, err := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Invalid user ID"})
+	}
+	active, err := h.userService.GetActiveSanctions(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "StatusOK", "data": active})
+}
+
 // login
 func (h *UserHandler) Login(c *fiber.Ctx) error {
 	var DataForLogin domain.LoginValidatorStruct
@@ -269,6 +305,9 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 			"message": "TOTPSecret",
 		})
 	}
+	if err := h.userService.CheckLoginSanctions(user.ID); err != nil {
+		return loginSanctionResponse(c, err)
+	}
 	token, err := jwt.CreateToken(user)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -501,7 +540,7 @@ func (h *UserHandler) GetUserById(c *fiber.Ctx) error {
 			"message": "Invalid user ID",
 		})
 	}
-	user, err := h.userService.FindUserById(userID)
+	user, err := h.userService.FindPublicUserById(userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": err.Error(),
@@ -567,6 +606,33 @@ func (h *UserHandler) SavePushToken(c *fiber.Ctx) error {
 		"message": "StatusOK",
 	})
 }
+func (h *UserHandler) RemovePushToken(c *fiber.Ctx) error {
+	IdUserToken := c.Context().UserValue("_id").(string)
+
+	IdUserTokenP, errinObjectID := primitive.ObjectIDFromHex(IdUserToken)
+	if errinObjectID != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "StatusInternalServerError",
+			"data":    errinObjectID.Error(),
+		})
+	}
+
+	pushToken := c.Query("pushToken", "")
+	if pushToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "pushToken is required in query",
+		})
+	}
+	if err := h.userService.RemovePushToken(IdUserTokenP, pushToken); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": "StatusInternalServerError",
+			"data":    err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "StatusOK",
+	})
+}
 func (h *UserHandler) UserPremiumAmonth(c *fiber.Ctx) error {
 	var req domain.RevenueCatWebhook
 	if err := c.BodyParser(&req); err != nil {
@@ -584,7 +650,7 @@ func (h *UserHandler) UserPremiumAmonth(c *fiber.Ctx) error {
 	fmt.Println(req.Event)
 	switch req.Event.Type {
 	case "RENEWAL", "INITIAL_PURCHASE":
-		err := h.userService.UserPremiumAmonth(userID)
+		err := h.userService.UserPremiumAmonth(userID, req.Event.Type, req.Event.ProductID)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
@@ -690,16 +756,26 @@ func (h *UserHandler) SearchUsersByNameTagOrLocation(c *fiber.Ctx) error {
 		Location       *domain.GeoPoint `json:"location"`
 		RadiusInMeters float64          `json:"radiusInMeters"`
 		Page           int              `json:"page"`
+		AvailableAt    *domain.TimeSlot `json:"availableAt"` // Solo trabajadores disponibles en ese horario
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Bad Request", "error": err.Error()})
 	}
+	if req.AvailableAt != nil {
+		if err := req.AvailableAt.Validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Bad Request", "error": err.Error()})
+		}
+	}
+	// La ruta es pública; si hay token se marca qué usuarios son favoritos
+	viewerID, _ := primitive.ObjectIDFromHex(c.Context().UserValue("_id").(string))
 	users, err := h.userService.FindUsersByNameTagOrLocation(
 		req.NameUser,
 		req.Tags,
 		req.Location,
 		req.RadiusInMeters,
 		req.Page,
+		req.AvailableAt,
+		viewerID,
 	)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Error searching users", "error": err.Error()})
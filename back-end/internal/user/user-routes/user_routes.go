@@ -20,6 +20,7 @@ func UserRoutes(App *fiber.App, redisClient *redis.Client, newMongoDB *mongo.Cli
 	App.Post("/user/SaveUserCodeConfirm", UserHandler.SaveUserCodeConfirm)
 	App.Post("/user/login", UserHandler.Login)
 	App.Post("/user/save-push-token", middleware.UseExtractor(), UserHandler.SavePushToken)
+	App.Post("/user/remove-push-token", middleware.UseExtractor(), UserHandler.RemovePushToken)
 
 	// oauth2
 	App.Get("/user/google_login", UserHandler.GoogleLogin)
@@ -33,6 +34,8 @@ func UserRoutes(App *fiber.App, redisClient *redis.Client, newMongoDB *mongo.Cli
 
 	App.Get("/user/get-user-token", middleware.UseExtractor(), UserHandler.GetUserByIdTheToken)
 	App.Get("/user/get-user-by-id", UserHandler.GetUserById)
+	// sanciones vigentes del usuario; las apelaciones se envían desde soporte
+	App.Get("/user/sanctions", middleware.UseExtractorAllowSanctioned(), UserHandler.GetActiveSanctions)
 
 	// edit user
 	App.Post("/user/edit-biografia", middleware.UseExtractor(), UserHandler.UpdateUserBiography)
@@ -43,6 +46,9 @@ func UserRoutes(App *fiber.App, redisClient *redis.Client, newMongoDB *mongo.Cli
 
 	App.Post("/user/premium", UserHandler.UserPremiumAmonth)
 
-	App.Post("/user/search", UserHandler.SearchUsersByNameTagOrLocation)
+	App.Post("/user/search", middleware.OptionalExtractor(), UserHandler.SearchUsersByNameTagOrLocation)
+	// calendario de disponibilidad del trabajador
+	App.Get("/user/availability/:userId", UserHandler.GetAvailability)
+	App.Put("/user/availability", middleware.UseExtractor(), UserHandler.UpdateAvailability)
 
 }
@@ -7,9 +7,15 @@ import (
 	"back-end/internal/admin/adminroutes"
 	"back-end/internal/chat/chatroutes"
 	"back-end/internal/cursos/cursosroutes"
+	"back-end/internal/favorites/favoritesroutes"
+	"back-end/internal/notifications/notificationroutes"
 	"back-end/internal/posts/postroutes"
 	supportroutes "back-end/internal/support/support_routes"
 	userroutes "back-end/internal/user/user-routes"
+	"back-end/pkg/geoprivacy"
+	"back-end/pkg/middleware"
+	"back-end/pkg/push"
+	"back-end/pkg/sanctions"
 	"strings"
 	"time"
 
@@ -27,6 +33,9 @@ import (
 )
 
 func main() {
+	if err := geoprivacy.CheckSecret(); err != nil {
+		log.Fatal(err)
+	}
 	redisClient := setupRedisClient()
 	newMongoDB := setupMongoDB()
 	defer redisClient.Close()
@@ -50,6 +59,8 @@ func main() {
 		}
 		return c.Status(fiber.StatusUpgradeRequired).SendString("Upgrade required")
 	})
+	// suspensiones y baneos en todas las rutas autenticadas
+	middleware.EnforceSanctions(sanctions.NewStore(newMongoDB))
 	// users
 	userroutes.UserRoutes(app, redisClient, newMongoDB)
 	jobroutes.JobRoutes(app, redisClient, newMongoDB)
@@ -59,6 +70,10 @@ func main() {
 	supportroutes.SupportRoutes(app, redisClient, newMongoDB)
 	postroutes.PostRoutes(app, redisClient, newMongoDB)
 	recommendedworkersroutes.RecommendedWorkersRoutes(app, redisClient, newMongoDB)
+	notificationroutes.NotificationRoutes(app, redisClient, newMongoDB)
+	favoritesroutes.FavoriteRoutes(app, redisClient, newMongoDB)
+	// limpieza de tokens push no registrados a partir de los recibos de Expo
+	push.NewDefaultService(newMongoDB).StartReceiptPoller(15 * time.Minute)
 	PORT := config.PORT()
 	if PORT == "" {
 		PORT = "8081"
@@ -0,0 +1,61 @@
+// Package availability arma los filtros de MongoDB sobre el calendario de disponibilidad
+// de los trabajadores (userdomain.Availability), para usarlos en búsquedas y validaciones.
+package availability
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Expr devuelve una expresión de agregación que vale true si el calendario guardado en path
+// (por ejemplo "$availability") cubre el horario [start, end). El horario debe caer dentro de
+// una franja semanal del mismo día local y ese día no puede estar bloqueado.
+// Las franjas guardan horas "HH:MM" y los bloqueos fechas "YYYY-MM-DD", que se comparan como texto.
+func Expr(path string, start, end time.Time) bson.M {
+	local := func(format string, date time.Time) bson.M {
+		return bson.M{"$dateToString": bson.M{"format": format, "date": date, "timezone": "$$tz"}}
+	}
+	return bson.M{"$let": bson.M{
+		"vars": bson.M{"tz": bson.M{"$ifNull": bson.A{path + ".timezone", "UTC"}}},
+		"in": bson.M{"$let": bson.M{
+			"vars": bson.M{
+				"day":     local("%Y-%m-%d", start),
+				"endDay":  local("%Y-%m-%d", end),
+				"from":    local("%H:%M", start),
+				"to":      local("%H:%M", end),
+				"weekday": bson.M{"$subtract": bson.A{bson.M{"$dayOfWeek": bson.M{"date": start, "timezone": "$$tz"}}, 1}},
+			},
+			"in": bson.M{"$and": bson.A{
+				bson.M{"$eq": bson.A{"$$day", "$$endDay"}},
+				bson.M{"$anyElementTrue": bson.A{bson.M{"$map": bson.M{
+					"input": bson.M{"$ifNull": bson.A{path + ".weekly", bson.A{}}},
+					"as":    "s",
+					"in": bson.M{"$and": bson.A{
+						bson.M{"$eq": bson.A{"$$s.weekday", "$$weekday"}},
+						bson.M{"$lte": bson.A{"$$s.start", "$$from"}},
+						bson.M{"$gte": bson.A{"$$s.end", "$$to"}},
+					}},
+				}}}},
+				bson.M{"$not": bson.A{bson.M{"$anyElementTrue": bson.A{bson.M{"$map": bson.M{
+					"input": bson.M{"$ifNull": bson.A{path + ".blackouts", bson.A{}}},
+					"as":    "b",
+					"in": bson.M{"$and": bson.A{
+						bson.M{"$lte": bson.A{"$$b.from", "$$day"}},
+						bson.M{"$gte": bson.A{"$$b.to", "$$day"}},
+					}},
+				}}}}}},
+			}},
+		}},
+	}}
+}
+
+// Filter es el filtro de consulta equivalente a Expr, que además acepta a los usuarios
+// que no publicaron calendario. field es el nombre del campo sin "$", por ejemplo "availability".
+func Filter(field string, start, end time.Time) bson.M {
+	path := "$" + field
+	return bson.M{"$expr": bson.M{"$or": bson.A{
+		bson.M{"$in": bson.A{bson.M{"$type": path}, bson.A{"missing", "null"}}},
+		Expr(path, start, end),
+	}}}
+}
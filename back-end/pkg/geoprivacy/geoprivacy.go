@@ -0,0 +1,85 @@
+// Package geoprivacy calcula las ubicaciones aproximadas que se muestran públicamente.
+package geoprivacy
+
+import (
+	"back-end/config"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Rango del desplazamiento aplicado a cada entidad, en metros.
+const (
+	MinOffsetMeters = 150
+	MaxOffsetMeters = 400
+)
+
+const metersPerDegree = 111320.0
+
+var (
+	secretOnce sync.Once
+	secret     []byte
+)
+
+// MinSecretLength es el largo mínimo de LOCATION_FUZZ_SECRET. Con una clave vacía o corta cualquiera
+// puede recalcular el desplazamiento y recuperar la ubicación exacta.
+const MinSecretLength = 32
+
+// CheckSecret valida LOCATION_FUZZ_SECRET; se llama al iniciar el servidor.
+func CheckSecret() error {
+	if len(fuzzSecret()) < MinSecretLength {
+		return fmt.Errorf("LOCATION_FUZZ_SECRET debe tener al menos %d caracteres", MinSecretLength)
+	}
+	return nil
+}
+
+func fuzzSecret() []byte {
+	secretOnce.Do(func() {
+		secret = []byte(config.LocationFuzzSecret())
+	})
+	return secret
+}
+
+// Approximate devuelve las coordenadas [lng, lat] desplazadas entre MinOffsetMeters y MaxOffsetMeters.
+// El desplazamiento depende solo del id de la entidad, así que es estable en el tiempo y
+// consultar varias veces no permite promediar hasta el punto exacto.
+func Approximate(id primitive.ObjectID, coordinates []float64) []float64 {
+	if len(coordinates) != 2 {
+		return coordinates
+	}
+	key := fuzzSecret()
+	if len(key) < MinSecretLength {
+		// No debería pasar porque main valida la clave; sin ella no se muestra ninguna ubicación
+		return nil
+	}
+	return offset(key, id, coordinates[0], coordinates[1])
+}
+
+func offset(key []byte, id primitive.ObjectID, lng, lat float64) []float64 {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(id[:])
+	sum := mac.Sum(nil)
+
+	angle := unit(sum[0:8]) * 2 * math.Pi
+	distance := MinOffsetMeters + unit(sum[8:16])*(MaxOffsetMeters-MinOffsetMeters)
+
+	dLat := distance * math.Cos(angle) / metersPerDegree
+	dLng := distance * math.Sin(angle) / (metersPerDegree * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+
+	// 4 decimales (~11 m) para no aparentar más precisión de la que tiene
+	return []float64{round4(lng + dLng), round4(math.Max(-90, math.Min(90, lat+dLat)))}
+}
+
+// unit convierte 8 bytes en un número en [0, 1).
+func unit(b []byte) float64 {
+	return float64(binary.BigEndian.Uint64(b)>>11) / float64(1<<53)
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
@@ -0,0 +1,100 @@
+// Package ical genera calendarios iCalendar (RFC 5545) para exportar citas a Google Calendar,
+// Apple Calendar u Outlook.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Estados de un evento.
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+const prodID = "-//Nexo Vecinal//Agenda//ES"
+
+// Event es un evento del calendario. Las fechas se escriben en UTC.
+type Event struct {
+	UID         string // Identificador estable; los clientes lo usan para actualizar el evento
+	Sequence    int    // Versión del evento; debe subir con cada cambio
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+	Summary     string
+	Description string
+	Coordinates []float64 // [longitud, latitud], como GeoJSON; opcional
+	Status      string
+}
+
+// Calendar arma un VCALENDAR con los eventos dados.
+func Calendar(name string, events ...Event) []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+prodID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escape(name))
+	}
+	for _, event := range events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+escape(event.UID))
+		writeLine(&buf, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		writeLine(&buf, "DTSTAMP:"+formatTime(event.Stamp))
+		writeLine(&buf, "DTSTART:"+formatTime(event.Start))
+		writeLine(&buf, "DTEND:"+formatTime(event.End))
+		writeLine(&buf, "SUMMARY:"+escape(event.Summary))
+		if event.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escape(event.Description))
+		}
+		if len(event.Coordinates) == 2 {
+			// GEO va en orden latitud;longitud
+			writeLine(&buf, fmt.Sprintf("GEO:%.6f;%.6f", event.Coordinates[1], event.Coordinates[0]))
+		}
+		if event.Status != "" {
+			writeLine(&buf, "STATUS:"+event.Status)
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape aplica el escapado de texto de RFC 5545.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeLine escribe una línea terminada en CRLF, plegada a 75 octetos sin cortar caracteres UTF-8.
+func writeLine(buf *bytes.Buffer, line string) {
+	const limit = 75
+	width := 0
+	for len(line) > 0 {
+		_, size := utf8.DecodeRuneInString(line)
+		if width+size > limit {
+			buf.WriteString("\r\n ")
+			width = 1
+		}
+		buf.WriteString(line[:size])
+		width += size
+		line = line[size:]
+	}
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,149 @@
+// Package mentions extrae las menciones (@NameUser) y los hashtags (#tema) de los textos de los usuarios.
+package mentions
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Máximo de menciones y hashtags que se guardan por texto; el resto se ignora.
+const (
+	MaxMentions = 10
+	MaxHashtags = 10
+)
+
+var (
+	// El carácter previo evita tomar emails (a@b.com) o anclas de URLs (pagina#seccion).
+	mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])@([A-Za-z0-9]{3,20})\b`)
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/#])#([\p{L}\p{N}_]{2,50})`)
+)
+
+// Mention es un usuario mencionado en un texto.
+type Mention struct {
+	UserID   primitive.ObjectID `json:"userId" bson:"userId"`
+	NameUser string             `json:"nameUser" bson:"nameUser"`
+}
+
+// Entities son las menciones y hashtags guardados junto a un post, comentario o mensaje.
+type Entities struct {
+	Mentions []Mention `json:"mentions,omitempty" bson:"mentions,omitempty"`
+	Hashtags []string  `json:"hashtags,omitempty" bson:"hashtags,omitempty"`
+}
+
+// Names devuelve los NameUser mencionados en text, sin repetir y en orden de aparición.
+func Names(text string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		key := strings.ToLower(match[1])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, match[1])
+		if len(names) == MaxMentions {
+			break
+		}
+	}
+	return names
+}
+
+// Hashtags devuelve los hashtags de text normalizados, sin repetir y en orden de aparición.
+func Hashtags(text string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, match := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+		tag := NormalizeHashtag(match[1])
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == MaxHashtags {
+			break
+		}
+	}
+	return tags
+}
+
+// NormalizeHashtag quita el # inicial y pasa el hashtag a minúsculas, como se guarda.
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// NameField es el campo de Users con el NameUser en minúsculas, indexado para resolver las menciones
+// sin regex. Lo escriben el alta y el cambio de NameUser; EnsureIndexes completa los usuarios anteriores.
+const NameField = "nameUserLower"
+
+// EnsureIndexes completa NameField en los usuarios que no lo tienen y crea su índice.
+func EnsureIndexes(ctx context.Context, mongoClient *mongo.Client) error {
+	users := mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	_, err := users.UpdateMany(ctx,
+		bson.M{NameField: bson.M{"$exists": false}, "NameUser": bson.M{"$type": "string"}},
+		bson.A{bson.M{"$set": bson.M{NameField: bson.M{"$toLower": "$NameUser"}}}},
+	)
+	if err != nil {
+		return err
+	}
+	_, err = users.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: NameField, Value: 1}}})
+	return err
+}
+
+// Extract busca las menciones y hashtags de los textos y resuelve los NameUser contra la
+// colección Users; las menciones a usuarios inexistentes se descartan. Los textos deben llegar
+// ya moderados, para no guardar hashtags de partes enmascaradas.
+func Extract(ctx context.Context, mongoClient *mongo.Client, texts ...string) (Entities, error) {
+	text := strings.Join(texts, "\n")
+	entities := Entities{Hashtags: Hashtags(text)}
+
+	names := Names(text)
+	if len(names) == 0 {
+		return entities, nil
+	}
+	lowered := make(bson.A, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+	opts := options.Find().SetProjection(bson.M{"NameUser": 1}).SetLimit(MaxMentions)
+	cursor, err := mongoClient.Database("NEXO-VECINAL").Collection("Users").Find(ctx, bson.M{NameField: bson.M{"$in": lowered}}, opts)
+	if err != nil {
+		return entities, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			NameUser string             `bson:"NameUser"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return entities, err
+		}
+		entities.Mentions = append(entities.Mentions, Mention{UserID: user.ID, NameUser: user.NameUser})
+	}
+	return entities, cursor.Err()
+}
+
+// Added devuelve las menciones de e que no estaban en previous, para no volver a notificar al editar.
+func (e Entities) Added(previous Entities) []Mention {
+	var added []Mention
+	for _, mention := range e.Mentions {
+		found := false
+		for _, old := range previous.Mentions {
+			if old.UserID == mention.UserID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			added = append(added, mention)
+		}
+	}
+	return added
+}
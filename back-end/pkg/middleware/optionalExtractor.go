@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"back-end/pkg/jwt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OptionalExtractor es como UseExtractor pero no exige token: en rutas públicas
+// permite personalizar la respuesta si el usuario está logueado. Sin token válido
+// "_id" queda vacío.
+func OptionalExtractor() fiber.Handler {
+
+	return func(c *fiber.Ctx) error {
+		c.Context().SetUserValue("_id", "")
+
+		authHeader := c.Get("Authorization")
+		if authHeader == "" {
+			return c.Next()
+		}
+		token := strings.Replace(authHeader, "Bearer ", "", 1)
+
+		nameUser, _id, verified, err := jwt.ExtractDataFromToken(token)
+		if err != nil {
+			return c.Next()
+		}
+		// Un usuario suspendido o baneado ve las rutas públicas como anónimo
+		if checkLoginSanction(_id) != nil {
+			return c.Next()
+		}
+		c.Context().SetUserValue("nameUser", nameUser)
+		c.Context().SetUserValue("_id", _id)
+		c.Context().SetUserValue("partner", verified)
+		return c.Next()
+	}
+
+}
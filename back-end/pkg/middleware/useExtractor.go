@@ -2,12 +2,36 @@ package middleware
 
 import (
 	"back-end/pkg/jwt"
+	"back-end/pkg/sanctions"
+	"context"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// sanctionStore es el store con el que UseExtractor rechaza a los usuarios suspendidos o baneados;
+// lo configura EnforceSanctions al iniciar. Los tokens duran meses, así que no alcanza con
+// revisar las sanciones al emitirlos.
+var sanctionStore *sanctions.Store
+
+// EnforceSanctions hace que UseExtractor rechace las peticiones de usuarios suspendidos o baneados.
+func EnforceSanctions(store *sanctions.Store) {
+	sanctionStore = store
+}
+
+// UseExtractor exige un token válido y que el usuario no esté suspendido ni baneado.
 func UseExtractor() fiber.Handler {
+	return extractor(true)
+}
+
+// UseExtractorAllowSanctioned es UseExtractor sin el control de sanciones, para las rutas con las que
+// un usuario suspendido o baneado consulta y apela sus sanciones.
+func UseExtractorAllowSanctioned() fiber.Handler {
+	return extractor(false)
+}
+
+func extractor(enforceSanctions bool) fiber.Handler {
 
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
@@ -20,6 +44,20 @@ func UseExtractor() fiber.Handler {
 				"Message": "Unauthorized",
 			})
 		}
+		if enforceSanctions {
+			if err := checkLoginSanction(_id); err != nil {
+				if sanction, ok := sanctions.As(err); ok {
+					// Misma respuesta que el login, para que la app muestre la sanción y la apelación
+					return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+						"message":  "sanctioned",
+						"sanction": sanction,
+					})
+				}
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"Message": "Error al verificar sanciones",
+				})
+			}
+		}
 		c.Context().SetUserValue("nameUser", nameUser)
 		c.Context().SetUserValue("_id", _id)
 		c.Context().SetUserValue("partner", verified)
@@ -27,3 +65,15 @@ func UseExtractor() fiber.Handler {
 	}
 
 }
+
+// checkLoginSanction devuelve el error de sanctions.Store.CheckLogin del usuario del token.
+func checkLoginSanction(id string) error {
+	if sanctionStore == nil {
+		return nil
+	}
+	userID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	return sanctionStore.CheckLogin(context.Background(), userID)
+}
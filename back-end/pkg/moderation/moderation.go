@@ -0,0 +1,192 @@
+// Package moderation revisa los textos de los usuarios antes de guardarlos: los deja pasar,
+// enmascara partes, los retiene para revisión de un administrador o los rechaza.
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"back-end/pkg/outbox"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Action es lo que se hace con un texto, de menor a mayor severidad.
+type Action string
+
+const (
+	ActionAllow  Action = "allow"  // Se guarda tal cual
+	ActionMask   Action = "mask"   // Se guarda con las partes detectadas ocultas
+	ActionHold   Action = "hold"   // Se guarda oculto hasta que lo revise un administrador
+	ActionReject Action = "reject" // No se guarda
+)
+
+func (a Action) severity() int {
+	switch a {
+	case ActionMask:
+		return 1
+	case ActionHold:
+		return 2
+	case ActionReject:
+		return 3
+	}
+	return 0
+}
+
+// ContentType es el tipo de contenido revisado; coincide con el contentType de los reportes de contenido.
+type ContentType string
+
+const (
+	ContentPost        ContentType = "post"
+	ContentComment     ContentType = "comment"
+	ContentJob         ContentType = "job"
+	ContentApplication ContentType = "application" // Propuesta de una postulación
+	ContentMessage     ContentType = "message"     // Mensaje de chat
+)
+
+// Holdable indica si el contenido se puede retener oculto. Las postulaciones y los mensajes tienen un
+// único destinatario que los espera, así que una retención se convierte en rechazo.
+func (t ContentType) Holdable() bool {
+	return t == ContentPost || t == ContentComment || t == ContentJob
+}
+
+// Verdict es el resultado de clasificar un texto.
+type Verdict struct {
+	Action  Action
+	Reasons []string // Reglas que se cumplieron
+	Text    string   // Texto con las partes enmascaradas
+}
+
+// Classifier decide qué hacer con un texto. La implementación por defecto es RuleClassifier;
+// se puede reemplazar por otra (por ejemplo, un servicio externo) con NewModerator.
+type Classifier interface {
+	Classify(ctx context.Context, contentType ContentType, text string) (Verdict, error)
+}
+
+var (
+	ErrRejected = errors.New("content rejected by moderation")
+	ErrHeld     = errors.New("content held for review")
+)
+
+// Decision es la acción final sobre un contenido con varios textos.
+type Decision struct {
+	Action  Action
+	Reasons []string
+}
+
+// Held indica si el contenido se debe guardar oculto y encolar con Hold.
+func (d Decision) Held() bool {
+	return d.Action == ActionHold
+}
+
+// Err devuelve ErrRejected (con los motivos) si el contenido no se puede guardar.
+func (d Decision) Err() error {
+	if d.Action == ActionReject {
+		return fmt.Errorf("%w: %s", ErrRejected, strings.Join(d.Reasons, ", "))
+	}
+	return nil
+}
+
+// Item identifica un contenido retenido.
+type Item struct {
+	Type     ContentType
+	ID       primitive.ObjectID
+	AuthorID primitive.ObjectID
+	Deferred []outbox.Event // Avisos que se registran recién si un admin aprueba el contenido
+}
+
+// Moderator aplica un Classifier a los textos y encola los contenidos retenidos.
+type Moderator struct {
+	classifier  Classifier
+	mongoClient *mongo.Client
+}
+
+// NewModerator crea un Moderator con el classifier indicado.
+func NewModerator(classifier Classifier, mongoClient *mongo.Client) *Moderator {
+	return &Moderator{classifier: classifier, mongoClient: mongoClient}
+}
+
+// NewDefaultModerator crea un Moderator con las reglas por defecto y las listas configuradas.
+func NewDefaultModerator(mongoClient *mongo.Client) *Moderator {
+	return NewModerator(NewDefaultClassifier(), mongoClient)
+}
+
+// Review clasifica cada texto, reemplaza en su lugar las partes enmascaradas y devuelve la acción
+// más severa. En los contenidos que no se pueden retener, una retención pasa a ser un rechazo.
+func (m *Moderator) Review(ctx context.Context, contentType ContentType, texts ...*string) (Decision, error) {
+	decision := Decision{Action: ActionAllow}
+	for _, text := range texts {
+		if text == nil || *text == "" {
+			continue
+		}
+		verdict, err := m.classifier.Classify(ctx, contentType, *text)
+		if err != nil {
+			return decision, err
+		}
+		if verdict.Text != "" {
+			*text = verdict.Text
+		}
+		decision.Reasons = appendUnique(decision.Reasons, verdict.Reasons...)
+		if verdict.Action.severity() > decision.Action.severity() {
+			decision.Action = verdict.Action
+		}
+	}
+	if decision.Action == ActionHold && !contentType.Holdable() {
+		decision.Action = ActionReject
+	}
+	return decision, nil
+}
+
+// Hold agrega el contenido retenido a la cola de reportes de contenido para que lo revise un
+// administrador. Se llama con el contexto de sesión de la escritura para que ambos se confirmen juntos.
+func (m *Moderator) Hold(ctx context.Context, item Item, decision Decision) error {
+	now := time.Now()
+	push := bson.M{"reports": bson.M{
+		"reporterUserId": primitive.NilObjectID, // Sin usuario: lo reportó la moderación automática
+		"reason":         "automated",
+		"description":    "Moderación automática: " + strings.Join(decision.Reasons, ", "),
+		"reportedAt":     now,
+	}}
+	if len(item.Deferred) > 0 {
+		push["deferredEvents"] = bson.M{"$each": item.Deferred}
+	}
+	_, err := m.mongoClient.Database("NEXO-VECINAL").Collection("content_reports").UpdateOne(ctx,
+		// Mismo criterio que los reportes de usuarios: se suma al reporte que siga sin cerrar
+		bson.M{"reportedContentId": item.ID, "contentType": item.Type, "status": bson.M{"$nin": bson.A{"actioned", "dismissed"}}},
+		bson.M{
+			"$push": push,
+			"$set":  bson.M{"held": true, "authorId": item.AuthorID, "reasons": decision.Reasons, "updatedAt": now},
+			"$setOnInsert": bson.M{
+				"reportedContentId": item.ID,
+				"contentType":       item.Type,
+				"status":            "open",
+				"open":              true, // Índice único de reportes abiertos por contenido
+				"createdAt":         now,
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
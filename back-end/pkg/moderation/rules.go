@@ -0,0 +1,122 @@
+package moderation
+
+import (
+	"back-end/config"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule aplica Action a los textos que cumplen Pattern. Si el patrón tiene un grupo de captura,
+// al enmascarar solo se oculta ese grupo.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Action  Action
+	Types   []ContentType // Vacío aplica a todos los tipos
+}
+
+func (r Rule) appliesTo(contentType ContentType) bool {
+	if len(r.Types) == 0 {
+		return true
+	}
+	for _, t := range r.Types {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleClassifier es el Classifier por defecto: una lista de reglas locales, sin servicios externos.
+type RuleClassifier struct {
+	Rules []Rule
+}
+
+// Classify aplica todas las reglas: enmascara las de ActionMask y devuelve la acción más severa.
+func (c *RuleClassifier) Classify(ctx context.Context, contentType ContentType, text string) (Verdict, error) {
+	verdict := Verdict{Action: ActionAllow, Text: text}
+	for _, rule := range c.Rules {
+		if !rule.appliesTo(contentType) || !rule.Pattern.MatchString(verdict.Text) {
+			continue
+		}
+		verdict.Reasons = appendUnique(verdict.Reasons, rule.Name)
+		if rule.Action == ActionMask {
+			// Las coincidencias pegadas comparten el separador; una segunda pasada oculta las que quedaron
+			for i := 0; i < 2 && rule.Pattern.MatchString(verdict.Text); i++ {
+				verdict.Text = maskMatches(rule.Pattern, verdict.Text)
+			}
+		}
+		if rule.Action.severity() > verdict.Action.severity() {
+			verdict.Action = rule.Action
+		}
+	}
+	return verdict, nil
+}
+
+// maskMatches reemplaza por asteriscos cada coincidencia (o su primer grupo de captura).
+func maskMatches(pattern *regexp.Regexp, text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if len(loc) >= 4 && loc[2] >= 0 {
+			start, end = loc[2], loc[3]
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(strings.Repeat("*", len([]rune(text[start:end]))))
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+var (
+	linkPattern  = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+	phonePattern = regexp.MustCompile(`\+?(?:\d[\s()-]?){7,14}\d`)
+)
+
+// DefaultRules son las reglas de enlaces y teléfonos. En los posts y comentarios un enlace se retiene
+// (es la vía habitual de spam y estafas); en trabajos y postulaciones los enlaces y teléfonos se
+// ocultan para que el contacto pase por la plataforma. En el chat, que es privado, no se aplican.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "link", Pattern: linkPattern, Action: ActionHold, Types: []ContentType{ContentPost, ContentComment}},
+		{Name: "link", Pattern: linkPattern, Action: ActionMask, Types: []ContentType{ContentJob, ContentApplication}},
+		{Name: "phone", Pattern: phonePattern, Action: ActionMask, Types: []ContentType{ContentPost, ContentComment, ContentJob, ContentApplication}},
+	}
+}
+
+// WordRules arma las reglas de una lista separada por comas. Cada palabra se busca completa y sin
+// distinguir mayúsculas; las entradas con prefijo "re:" son expresiones regulares.
+func WordRules(list string, action Action, types ...ContentType) []Rule {
+	var rules []Rule
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		expr := `(?i)(?:^|[^\p{L}\p{N}])(` + regexp.QuoteMeta(entry) + `)(?:$|[^\p{L}\p{N}])`
+		if strings.HasPrefix(entry, "re:") {
+			expr = "(?i)" + strings.TrimPrefix(entry, "re:")
+		}
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			fmt.Println("Regla de moderación inválida:", entry, err)
+			continue
+		}
+		rules = append(rules, Rule{Name: "word:" + strings.TrimPrefix(entry, "re:"), Pattern: pattern, Action: action, Types: types})
+	}
+	return rules
+}
+
+// NewDefaultClassifier combina las reglas por defecto con las listas de palabras configuradas.
+// Las palabras de revisión solo aplican a los contenidos que se pueden retener.
+func NewDefaultClassifier() *RuleClassifier {
+	rules := DefaultRules()
+	rules = append(rules, WordRules(config.ModerationBlockedWords(), ActionReject)...)
+	rules = append(rules, WordRules(config.ModerationReviewWords(), ActionHold, ContentPost, ContentComment, ContentJob)...)
+	rules = append(rules, WordRules(config.ModerationMaskedWords(), ActionMask)...)
+	return &RuleClassifier{Rules: rules}
+}
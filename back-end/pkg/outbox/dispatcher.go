@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Handler entrega un evento; si devuelve error el evento se reintenta con backoff.
+type Handler func(ctx context.Context, event Event) error
+
+// Dispatcher despacha los eventos de los tipos registrados con reintentos y dead-letter.
+type Dispatcher struct {
+	store       *Store
+	handlers    map[string]Handler
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Lease       time.Duration // Tiempo que un evento queda reservado mientras se procesa
+}
+
+// NewDispatcher crea un Dispatcher con los valores de reintento por defecto.
+func NewDispatcher(store *Store) *Dispatcher {
+	return &Dispatcher{
+		store:       store,
+		handlers:    map[string]Handler{},
+		MaxAttempts: 8,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+		Lease:       5 * time.Minute,
+	}
+}
+
+// Handle registra el handler para un tipo de evento.
+func (d *Dispatcher) Handle(eventType string, handler Handler) {
+	d.handlers[eventType] = handler
+}
+
+// Start procesa los eventos pendientes cada interval en segundo plano.
+func (d *Dispatcher) Start(interval time.Duration) {
+	if err := d.store.EnsureIndexes(context.Background()); err != nil {
+		fmt.Println("Error creando índices del outbox:", err)
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			d.DispatchPending(context.Background())
+		}
+	}()
+}
+
+// DispatchPending despacha eventos hasta que no quede ninguno disponible.
+func (d *Dispatcher) DispatchPending(ctx context.Context) {
+	types := make([]string, 0, len(d.handlers))
+	for t := range d.handlers {
+		types = append(types, t)
+	}
+	if len(types) == 0 {
+		return
+	}
+	for {
+		event, err := d.store.claim(ctx, types, d.Lease)
+		if err != nil {
+			fmt.Println("Error tomando evento del outbox:", err)
+			return
+		}
+		if event == nil {
+			return
+		}
+		d.dispatch(ctx, *event)
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event Event) {
+	err := d.handlers[event.Type](ctx, event)
+	if err == nil {
+		if err := d.store.markDone(ctx, event.ID); err != nil {
+			fmt.Println("Error marcando evento como despachado:", event.ID.Hex(), err)
+		}
+		return
+	}
+
+	status := StatusPending
+	if event.Attempts >= d.MaxAttempts {
+		status = StatusDead
+	}
+	next := time.Now().Add(Backoff(event.Attempts, d.BaseBackoff, d.MaxBackoff))
+	if err := d.store.markFailed(ctx, event.ID, status, next, err); err != nil {
+		fmt.Println("Error registrando fallo del evento:", event.ID.Hex(), err)
+	}
+}
+
+// Backoff calcula la espera exponencial antes del próximo intento, acotada por max.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	return wait
+}
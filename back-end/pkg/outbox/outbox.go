@@ -0,0 +1,219 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Estados posibles de un evento del outbox.
+const (
+	StatusPending    = "pending"    // Esperando ser despachado
+	StatusProcessing = "processing" // Tomado por un dispatcher
+	StatusDone       = "done"       // Despachado correctamente
+	StatusDead       = "dead"       // Agotó los reintentos (dead-letter)
+)
+
+// Event es un efecto secundario pendiente (push, recomendaciones, métricas) registrado junto a la escritura de dominio.
+type Event struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type          string             `bson:"type" json:"type"`
+	Payload       bson.Raw           `bson:"payload" json:"-"`
+	PayloadView   bson.M             `bson:"-" json:"payload,omitempty"`
+	Status        string             `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time          `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	LockedUntil   time.Time          `bson:"lockedUntil,omitempty" json:"-"`
+	LastError     string             `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ProcessedAt   *time.Time         `bson:"processedAt,omitempty" json:"processedAt,omitempty"`
+}
+
+// NewEvent crea un evento pendiente serializando el payload en BSON.
+func NewEvent(eventType string, payload interface{}) (Event, error) {
+	raw, err := bson.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("error serializando evento %s: %v", eventType, err)
+	}
+	now := time.Now()
+	return Event{
+		ID:            primitive.NewObjectID(),
+		Type:          eventType,
+		Payload:       raw,
+		Status:        StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Decode deserializa el payload del evento en v.
+func (e Event) Decode(v interface{}) error {
+	return bson.Unmarshal(e.Payload, v)
+}
+
+// Store persiste los eventos en la colección "outbox".
+type Store struct {
+	mongoClient *mongo.Client
+}
+
+// NewStore crea una nueva instancia de Store.
+func NewStore(mongoClient *mongo.Client) *Store {
+	return &Store{mongoClient: mongoClient}
+}
+
+func (s *Store) collection() *mongo.Collection {
+	return s.mongoClient.Database("NEXO-VECINAL").Collection("outbox")
+}
+
+// WithTransaction ejecuta fn dentro de una transacción de Mongo (requiere replica set).
+// Las escrituras de dominio y Record deben usar sessCtx para confirmarse juntas. Si ctx ya
+// tiene una sesión, fn se suma a esa transacción en lugar de abrir otra.
+func (s *Store) WithTransaction(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+	if session := mongo.SessionFromContext(ctx); session != nil {
+		return fn(mongo.NewSessionContext(ctx, session))
+	}
+	session, err := s.mongoClient.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// Record inserta los eventos; con un contexto de sesión forman parte de la misma transacción.
+func (s *Store) Record(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(events))
+	for i, e := range events {
+		docs[i] = e
+	}
+	_, err := s.collection().InsertMany(ctx, docs)
+	return err
+}
+
+// EnsureIndexes crea los índices usados por el dispatcher y la expiración de eventos despachados.
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "type", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+		{Keys: bson.M{"processedAt": 1}, Options: options.Index().SetExpireAfterSeconds(7 * 24 * 3600)},
+	})
+	return err
+}
+
+// claim toma de forma atómica el próximo evento disponible de los tipos indicados.
+func (s *Store) claim(ctx context.Context, types []string, lease time.Duration) (*Event, error) {
+	now := time.Now()
+	filter := bson.M{
+		"type": bson.M{"$in": types},
+		"$or": []bson.M{
+			{"status": StatusPending, "nextAttemptAt": bson.M{"$lte": now}},
+			// Eventos de un dispatcher que se cayó a mitad de proceso
+			{"status": StatusProcessing, "lockedUntil": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": StatusProcessing, "lockedUntil": now.Add(lease), "updatedAt": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"nextAttemptAt": 1}).
+		SetReturnDocument(options.After)
+
+	var event Event
+	err := s.collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (s *Store) markDone(ctx context.Context, id primitive.ObjectID) error {
+	now := time.Now()
+	_, err := s.collection().UpdateByID(ctx, id, bson.M{
+		"$set":   bson.M{"status": StatusDone, "processedAt": now, "updatedAt": now},
+		"$unset": bson.M{"lockedUntil": "", "lastError": ""},
+	})
+	return err
+}
+
+func (s *Store) markFailed(ctx context.Context, id primitive.ObjectID, status string, nextAttempt time.Time, cause error) error {
+	_, err := s.collection().UpdateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"status":        status,
+			"nextAttemptAt": nextAttempt,
+			"lastError":     cause.Error(),
+			"updatedAt":     time.Now(),
+		},
+		"$unset": bson.M{"lockedUntil": ""},
+	})
+	return err
+}
+
+// Reschedule mueve a at los eventos pendientes de eventType cuyo payload coincide con match;
+// las claves de match son campos del payload.
+func (s *Store) Reschedule(ctx context.Context, eventType string, match bson.M, at time.Time) error {
+	filter := bson.M{"type": eventType, "status": StatusPending}
+	for key, value := range match {
+		filter["payload."+key] = value
+	}
+	_, err := s.collection().UpdateMany(ctx, filter, bson.M{
+		"$set": bson.M{"nextAttemptAt": at, "updatedAt": time.Now()},
+	})
+	return err
+}
+
+// DeadLetters devuelve los eventos que agotaron sus reintentos, de a 10 por página.
+func (s *Store) DeadLetters(ctx context.Context, page int) ([]Event, error) {
+	opts := options.Find().
+		SetSort(bson.M{"updatedAt": -1}).
+		SetSkip(int64((page - 1) * 10)).
+		SetLimit(10)
+	cursor, err := s.collection().Find(ctx, bson.M{"status": StatusDead}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []Event
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	for i := range events {
+		var view bson.M
+		if err := events[i].Decode(&view); err == nil {
+			events[i].PayloadView = view
+		}
+	}
+	return events, nil
+}
+
+// Retry vuelve a encolar un evento muerto reiniciando sus intentos.
+func (s *Store) Retry(ctx context.Context, id primitive.ObjectID) error {
+	res, err := s.collection().UpdateOne(ctx, bson.M{"_id": id, "status": StatusDead}, bson.M{
+		"$set": bson.M{"status": StatusPending, "attempts": 0, "nextAttemptAt": time.Now(), "updatedAt": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("evento no encontrado o no está en dead-letter")
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Códigos de error de Expo que se tratan de forma especial.
+const (
+	ErrorDeviceNotRegistered = "DeviceNotRegistered"
+)
+
+// Message es una notificación dirigida a un único token.
+type Message struct {
+	To        string            `json:"to"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty"`
+	ChannelID string            `json:"channelId,omitempty"`
+}
+
+// Ticket es la respuesta del proveedor para un mensaje enviado (mismo orden que los mensajes).
+type Ticket struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"` // "ok" o "error"
+	Message string `json:"message"`
+	Details struct {
+		Error string `json:"error"`
+	} `json:"details"`
+}
+
+// Receipt es el resultado final de la entrega de un ticket.
+type Receipt struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Details struct {
+		Error string `json:"error"`
+	} `json:"details"`
+}
+
+// Sender abstrae al proveedor de notificaciones push.
+type Sender interface {
+	// Send envía un lote de mensajes (como máximo MaxMessagesPerRequest) y devuelve un ticket por mensaje.
+	Send(ctx context.Context, messages []Message) ([]Ticket, error)
+	// Receipts consulta el resultado de entrega de los tickets indicados.
+	Receipts(ctx context.Context, ticketIDs []string) (map[string]Receipt, error)
+}
+
+// Límites de la API de Expo por request.
+const (
+	MaxMessagesPerRequest = 100
+	MaxReceiptsPerRequest = 1000
+)
+
+// ExpoSender envía las notificaciones a través del servicio push de Expo.
+type ExpoSender struct {
+	SendURL     string
+	ReceiptsURL string
+	Client      *http.Client
+}
+
+// NewExpoSender crea un ExpoSender con los endpoints públicos de Expo.
+func NewExpoSender() *ExpoSender {
+	return &ExpoSender{
+		SendURL:     "https://exp.host/--/api/v2/push/send",
+		ReceiptsURL: "https://exp.host/--/api/v2/push/getReceipts",
+		Client:      &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (e *ExpoSender) post(ctx context.Context, url string, body interface{}, out interface{}) error {
+	payloadBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("error serializando el payload: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error enviando la notificación: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error enviando la notificación, status: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Send implementa Sender.
+func (e *ExpoSender) Send(ctx context.Context, messages []Message) ([]Ticket, error) {
+	var resp struct {
+		Data []Ticket `json:"data"`
+	}
+	if err := e.post(ctx, e.SendURL, messages, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Receipts implementa Sender.
+func (e *ExpoSender) Receipts(ctx context.Context, ticketIDs []string) (map[string]Receipt, error) {
+	var resp struct {
+		Data map[string]Receipt `json:"data"`
+	}
+	if err := e.post(ctx, e.ReceiptsURL, map[string][]string{"ids": ticketIDs}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// FakeSender guarda los mensajes en memoria; se usa en desarrollo local (PUSH_PROVIDER=fake).
+type FakeSender struct {
+	mu       sync.Mutex
+	Sent     []Message
+	receipts map[string]Receipt
+	// Unregistered son tokens que el fake reporta como DeviceNotRegistered.
+	Unregistered map[string]bool
+}
+
+// NewFakeSender crea un FakeSender vacío.
+func NewFakeSender() *FakeSender {
+	return &FakeSender{receipts: map[string]Receipt{}, Unregistered: map[string]bool{}}
+}
+
+// Send implementa Sender.
+func (f *FakeSender) Send(ctx context.Context, messages []Message) ([]Ticket, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tickets := make([]Ticket, len(messages))
+	for i, m := range messages {
+		f.Sent = append(f.Sent, m)
+		id := primitive.NewObjectID().Hex()
+		tickets[i] = Ticket{ID: id, Status: "ok"}
+		receipt := Receipt{Status: "ok"}
+		if f.Unregistered[m.To] {
+			receipt.Status = "error"
+			receipt.Details.Error = ErrorDeviceNotRegistered
+		}
+		f.receipts[id] = receipt
+	}
+	return tickets, nil
+}
+
+// Receipts implementa Sender.
+func (f *FakeSender) Receipts(ctx context.Context, ticketIDs []string) (map[string]Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := map[string]Receipt{}
+	for _, id := range ticketIDs {
+		if r, ok := f.receipts[id]; ok {
+			out[id] = r
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,272 @@
+package push
+
+import (
+	"back-end/config"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNoTokens indica que ninguno de los destinatarios tiene dispositivos registrados.
+var ErrNoTokens = errors.New("el usuario no tiene push token registrado")
+
+// Tiempo que se espera antes de consultar un recibo y tiempo que Expo los conserva.
+const (
+	receiptDelay     = 15 * time.Minute
+	receiptRetention = 24 * time.Hour
+)
+
+// Notification es el contenido común enviado a todos los dispositivos de los destinatarios.
+type Notification struct {
+	Title     string
+	Body      string
+	Data      map[string]string
+	ChannelID string // "jobs", "chats", ...
+}
+
+// Service envía notificaciones a usuarios o tokens y mantiene limpios los tokens registrados.
+type Service struct {
+	sender      Sender
+	mongoClient *mongo.Client
+}
+
+// NewService crea un Service con el Sender indicado.
+func NewService(sender Sender, mongoClient *mongo.Client) *Service {
+	return &Service{sender: sender, mongoClient: mongoClient}
+}
+
+var (
+	defaultSenderOnce sync.Once
+	defaultSender     Sender
+)
+
+// NewDefaultService crea un Service con el proveedor configurado en PUSH_PROVIDER ("expo" por defecto o "fake").
+func NewDefaultService(mongoClient *mongo.Client) *Service {
+	defaultSenderOnce.Do(func() {
+		if config.PushProvider() == "fake" {
+			defaultSender = NewFakeSender()
+			return
+		}
+		defaultSender = NewExpoSender()
+	})
+	return NewService(defaultSender, mongoClient)
+}
+
+func (s *Service) users() *mongo.Collection {
+	return s.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+}
+
+func (s *Service) tickets() *mongo.Collection {
+	return s.mongoClient.Database("NEXO-VECINAL").Collection("push_tickets")
+}
+
+// TokensForUsers devuelve todos los tokens (todos los dispositivos) de los usuarios indicados.
+func (s *Service) TokensForUsers(ctx context.Context, userIDs []primitive.ObjectID) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{"pushToken": 1, "pushTokens": 1})
+	cursor, err := s.users().Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar usuarios: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []struct {
+		PushToken  string   `bson:"pushToken"`
+		PushTokens []string `bson:"pushTokens"`
+	}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	var tokens []string
+	for _, u := range users {
+		tokens = append(tokens, u.PushTokens...)
+		tokens = append(tokens, u.PushToken)
+	}
+	return uniqueTokens(tokens), nil
+}
+
+// SendToUsers envía la notificación a todos los dispositivos de los usuarios.
+func (s *Service) SendToUsers(ctx context.Context, userIDs []primitive.ObjectID, n Notification) error {
+	tokens, err := s.TokensForUsers(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return ErrNoTokens
+	}
+	return s.SendToTokens(ctx, tokens, n)
+}
+
+// SendToTokens envía la notificación en lotes, guarda los tickets para consultar recibos
+// y elimina los tokens que Expo informa como no registrados.
+func (s *Service) SendToTokens(ctx context.Context, tokens []string, n Notification) error {
+	tokens = uniqueTokens(tokens)
+	var firstErr error
+	var unregistered []string
+	var pending []interface{}
+
+	for start := 0; start < len(tokens); start += MaxMessagesPerRequest {
+		end := start + MaxMessagesPerRequest
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+		messages := make([]Message, 0, end-start)
+		for _, token := range tokens[start:end] {
+			messages = append(messages, Message{To: token, Title: n.Title, Body: n.Body, Data: n.Data, ChannelID: n.ChannelID})
+		}
+
+		tickets, err := s.sender.Send(ctx, messages)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for i, ticket := range tickets {
+			if i >= len(messages) {
+				break
+			}
+			switch {
+			case ticket.Status == "ok" && ticket.ID != "":
+				pending = append(pending, bson.M{"ticketId": ticket.ID, "token": messages[i].To, "createdAt": time.Now()})
+			case ticket.Details.Error == ErrorDeviceNotRegistered:
+				unregistered = append(unregistered, messages[i].To)
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		if _, err := s.tickets().InsertMany(ctx, pending); err != nil {
+			fmt.Println("Error guardando tickets push:", err)
+		}
+	}
+	if err := s.RemoveTokens(ctx, unregistered); err != nil {
+		fmt.Println("Error eliminando tokens no registrados:", err)
+	}
+	return firstErr
+}
+
+// RemoveTokens quita los tokens indicados de todos los usuarios.
+func (s *Service) RemoveTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	if _, err := s.users().UpdateMany(ctx,
+		bson.M{"pushTokens": bson.M{"$in": tokens}},
+		bson.M{"$pull": bson.M{"pushTokens": bson.M{"$in": tokens}}},
+	); err != nil {
+		return err
+	}
+	_, err := s.users().UpdateMany(ctx,
+		bson.M{"pushToken": bson.M{"$in": tokens}},
+		bson.M{"$set": bson.M{"pushToken": ""}},
+	)
+	return err
+}
+
+// CheckReceipts consulta los recibos de los tickets pendientes y limpia los tokens con DeviceNotRegistered.
+// Recorre los tickets en páginas, así los que todavía no tienen recibo no tapan a los siguientes.
+func (s *Service) CheckReceipts(ctx context.Context) error {
+	now := time.Now()
+	after := primitive.NilObjectID
+	for {
+		last, n, err := s.checkReceiptPage(ctx, now, after)
+		if err != nil {
+			return err
+		}
+		if n < MaxReceiptsPerRequest {
+			return nil
+		}
+		after = last
+	}
+}
+
+// checkReceiptPage procesa hasta MaxReceiptsPerRequest tickets con _id mayor que after y devuelve
+// el último _id leído y cuántos tickets leyó.
+func (s *Service) checkReceiptPage(ctx context.Context, now time.Time, after primitive.ObjectID) (primitive.ObjectID, int, error) {
+	filter := bson.M{"_id": bson.M{"$gt": after}, "createdAt": bson.M{"$lte": now.Add(-receiptDelay)}}
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(MaxReceiptsPerRequest)
+	cursor, err := s.tickets().Find(ctx, filter, opts)
+	if err != nil {
+		return after, 0, err
+	}
+	var tickets []struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		TicketID  string             `bson:"ticketId"`
+		Token     string             `bson:"token"`
+		CreatedAt time.Time          `bson:"createdAt"`
+	}
+	if err := cursor.All(ctx, &tickets); err != nil {
+		return after, 0, err
+	}
+	if len(tickets) == 0 {
+		return after, 0, nil
+	}
+
+	ids := make([]string, len(tickets))
+	for i, t := range tickets {
+		ids[i] = t.TicketID
+	}
+	receipts, err := s.sender.Receipts(ctx, ids)
+	if err != nil {
+		return after, 0, err
+	}
+
+	var unregistered []string
+	var done []primitive.ObjectID
+	for _, t := range tickets {
+		receipt, ok := receipts[t.TicketID]
+		if !ok {
+			// Sin recibo todavía; se descarta cuando Expo ya no lo conserva
+			if now.Sub(t.CreatedAt) > receiptRetention {
+				done = append(done, t.ID)
+			}
+			continue
+		}
+		if receipt.Details.Error == ErrorDeviceNotRegistered {
+			unregistered = append(unregistered, t.Token)
+		}
+		done = append(done, t.ID)
+	}
+
+	if err := s.RemoveTokens(ctx, unregistered); err != nil {
+		return after, 0, err
+	}
+	if len(done) > 0 {
+		if _, err := s.tickets().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": done}}); err != nil {
+			return after, 0, err
+		}
+	}
+	return tickets[len(tickets)-1].ID, len(tickets), nil
+}
+
+// StartReceiptPoller consulta periódicamente los recibos de Expo.
+func (s *Service) StartReceiptPoller(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := s.CheckReceipts(context.Background()); err != nil {
+				fmt.Println("Error consultando recibos push:", err)
+			}
+		}
+	}()
+}
+
+func uniqueTokens(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	out := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
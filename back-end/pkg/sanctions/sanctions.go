@@ -0,0 +1,498 @@
+// Package sanctions guarda las sanciones de los usuarios (advertencias, restricciones, suspensiones y
+// baneos) y las aplica en las escrituras y en el login. Las sanciones vencen solas: se consideran
+// activas mientras no se hayan levantado y su expiresAt no haya pasado.
+package sanctions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Type es el tipo de sanción, de menor a mayor severidad.
+type Type string
+
+const (
+	TypeWarning            Type = "warning"             // Solo queda registrada y la ve el usuario
+	TypePostingRestriction Type = "posting_restriction" // No puede publicar posts, comentarios, trabajos ni postulaciones
+	TypeChatRestriction    Type = "chat_restriction"    // No puede enviar mensajes de chat
+	TypeSuspension         Type = "suspension"          // No puede iniciar sesión ni escribir hasta que venza
+	TypeBan                Type = "ban"                 // Como la suspensión, pero sin vencimiento
+)
+
+func (t Type) severity() int {
+	switch t {
+	case TypePostingRestriction, TypeChatRestriction:
+		return 1
+	case TypeSuspension:
+		return 2
+	case TypeBan:
+		return 3
+	}
+	return 0
+}
+
+// ParseType valida el tipo de sanción recibido en una petición.
+func ParseType(value string) (Type, error) {
+	t := Type(strings.ToLower(strings.TrimSpace(value)))
+	switch t {
+	case TypeWarning, TypePostingRestriction, TypeChatRestriction, TypeSuspension, TypeBan:
+		return t, nil
+	}
+	return "", errors.New("tipo de sanción inválido")
+}
+
+// Scope es la acción que se quiere hacer y que una sanción puede impedir.
+type Scope string
+
+const (
+	ScopeLogin   Scope = "login"
+	ScopePosting Scope = "posting" // Posts, comentarios, trabajos y postulaciones
+	ScopeChat    Scope = "chat"
+)
+
+// Blocks indica si una sanción del tipo impide la acción.
+func (t Type) Blocks(scope Scope) bool {
+	switch t {
+	case TypeSuspension, TypeBan:
+		return true
+	case TypePostingRestriction:
+		return scope == ScopePosting
+	case TypeChatRestriction:
+		return scope == ScopeChat
+	}
+	return false
+}
+
+// Duración por defecto de una advertencia y máxima de las sanciones temporales.
+const (
+	DefaultWarningDuration = 30 * 24 * time.Hour
+	MaxDuration            = 365 * 24 * time.Hour
+)
+
+// AppealStatus es el estado de la apelación de una sanción.
+type AppealStatus string
+
+const (
+	AppealPending  AppealStatus = "pending"
+	AppealAccepted AppealStatus = "accepted" // La sanción se levantó
+	AppealRejected AppealStatus = "rejected"
+)
+
+// Appeal es la apelación del usuario sancionado. Se admite una por sanción.
+type Appeal struct {
+	Text       string       `json:"text" bson:"text"`
+	Status     AppealStatus `json:"status" bson:"status"`
+	CreatedAt  time.Time    `json:"createdAt" bson:"createdAt"`
+	ResolvedAt *time.Time   `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+}
+
+// Sanction es una sanción aplicada por un admin a un usuario.
+type Sanction struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID  `json:"userId" bson:"userId"`
+	Type      Type                `json:"type" bson:"type"`
+	Reason    string              `json:"reason" bson:"reason"`
+	IssuedBy  primitive.ObjectID  `json:"issuedBy" bson:"issuedBy"`
+	CreatedAt time.Time           `json:"createdAt" bson:"createdAt"`
+	ExpiresAt *time.Time          `json:"expiresAt,omitempty" bson:"expiresAt"` // nil en los baneos
+	LiftedAt  *time.Time          `json:"liftedAt,omitempty" bson:"liftedAt"`
+	LiftedBy  *primitive.ObjectID `json:"liftedBy,omitempty" bson:"liftedBy,omitempty"`
+	LiftNote  string              `json:"liftNote,omitempty" bson:"liftNote,omitempty"`
+	Appeal    *Appeal             `json:"appeal,omitempty" bson:"appeal,omitempty"`
+}
+
+// Active indica si la sanción está vigente en now.
+func (s Sanction) Active(now time.Time) bool {
+	return s.LiftedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(now))
+}
+
+// ErrSanctioned es la causa de los errores de Check; con As se obtiene la sanción que impide la acción.
+var ErrSanctioned = errors.New("user sanctioned")
+
+// Error envuelve la sanción que impide una acción.
+type Error struct {
+	Sanction Sanction
+}
+
+func (e *Error) Error() string {
+	if e.Sanction.ExpiresAt == nil {
+		return fmt.Sprintf("%v: %s", ErrSanctioned, e.Sanction.Type)
+	}
+	return fmt.Sprintf("%v: %s until %s", ErrSanctioned, e.Sanction.Type, e.Sanction.ExpiresAt.Format(time.RFC3339))
+}
+
+func (e *Error) Unwrap() error {
+	return ErrSanctioned
+}
+
+// As devuelve la sanción que causó err, si la hay.
+func As(err error) (*Sanction, bool) {
+	var sanctionErr *Error
+	if errors.As(err, &sanctionErr) {
+		return &sanctionErr.Sanction, true
+	}
+	return nil, false
+}
+
+// Store guarda las sanciones en la colección user_sanctions.
+type Store struct {
+	mongoClient *mongo.Client
+}
+
+// NewStore crea un Store sobre la base de la aplicación.
+func NewStore(mongoClient *mongo.Client) *Store {
+	return &Store{mongoClient: mongoClient}
+}
+
+func (s *Store) collection() *mongo.Collection {
+	return s.mongoClient.Database("NEXO-VECINAL").Collection("user_sanctions")
+}
+
+// EnsureIndexes crea los índices de las consultas de sanciones vigentes y de historial.
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "liftedAt", Value: 1}, {Key: "expiresAt", Value: 1}}},
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+	})
+	return err
+}
+
+// activeFilter filtra las sanciones vigentes en now.
+func activeFilter(now time.Time) bson.M {
+	return bson.M{
+		"liftedAt": nil,
+		"$or": bson.A{
+			bson.M{"expiresAt": nil},
+			bson.M{"expiresAt": bson.M{"$gt": now}},
+		},
+	}
+}
+
+// New valida y arma una sanción de adminID a userID. duration se ignora en los baneos; en las
+// advertencias es opcional y en el resto es obligatoria.
+func New(userID, adminID primitive.ObjectID, sanctionType Type, reason string, duration time.Duration) (Sanction, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return Sanction{}, errors.New("el motivo es obligatorio")
+	}
+	if len(reason) > 1000 {
+		return Sanction{}, errors.New("el motivo excede los 1000 caracteres")
+	}
+	if duration < 0 || duration > MaxDuration {
+		return Sanction{}, errors.New("duración inválida")
+	}
+
+	now := time.Now()
+	sanction := Sanction{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Type:      sanctionType,
+		Reason:    reason,
+		IssuedBy:  adminID,
+		CreatedAt: now,
+	}
+	switch {
+	case sanctionType == TypeBan:
+	case sanctionType == TypeWarning && duration == 0:
+		expiresAt := now.Add(DefaultWarningDuration)
+		sanction.ExpiresAt = &expiresAt
+	case duration == 0:
+		return Sanction{}, errors.New("la duración es obligatoria para este tipo de sanción")
+	default:
+		expiresAt := now.Add(duration)
+		sanction.ExpiresAt = &expiresAt
+	}
+	return sanction, nil
+}
+
+// Issue guarda una sanción armada con New. Los baneos además marcan Users.Banned.
+func (s *Store) Issue(ctx context.Context, sanction Sanction) error {
+	if _, err := s.collection().InsertOne(ctx, sanction); err != nil {
+		return fmt.Errorf("failed to issue sanction: %v", err)
+	}
+	forgetLogin(sanction.UserID)
+	if sanction.Type == TypeBan {
+		return s.syncBanned(ctx, sanction.UserID)
+	}
+	return nil
+}
+
+// syncBanned deja Users.Banned, que leen las consultas anteriores a las sanciones, en true solo si el
+// usuario tiene un baneo vigente. Los baneos no vencen, así que alcanza con llamarla al emitir y levantar.
+func (s *Store) syncBanned(ctx context.Context, userID primitive.ObjectID) error {
+	filter := activeFilter(time.Now())
+	filter["userId"] = userID
+	filter["type"] = TypeBan
+	count, err := s.collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to check bans: %v", err)
+	}
+	_, err = s.mongoClient.Database("NEXO-VECINAL").Collection("Users").
+		UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"Banned": count > 0}})
+	if err != nil {
+		return fmt.Errorf("failed to update banned flag: %v", err)
+	}
+	return nil
+}
+
+// liftUpdate levanta una sanción y acepta su apelación pendiente, si la tiene.
+func liftUpdate(adminID primitive.ObjectID, note string, now time.Time) bson.A {
+	return bson.A{
+		bson.M{"$set": bson.M{
+			"liftedAt": now,
+			"liftedBy": adminID,
+			"liftNote": strings.TrimSpace(note),
+			"appeal": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$appeal.status", AppealPending}},
+				bson.M{"$mergeObjects": bson.A{"$appeal", bson.M{"status": AppealAccepted, "resolvedAt": now}}},
+				"$appeal",
+			}},
+		}},
+	}
+}
+
+// Get devuelve una sanción por su _id.
+func (s *Store) Get(ctx context.Context, sanctionID primitive.ObjectID) (*Sanction, error) {
+	var sanction Sanction
+	err := s.collection().FindOne(ctx, bson.M{"_id": sanctionID}).Decode(&sanction)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.New("sanción no encontrada")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sanction: %v", err)
+	}
+	return &sanction, nil
+}
+
+// Lift levanta una sanción vigente. Si tiene una apelación pendiente, la apelación queda aceptada.
+func (s *Store) Lift(ctx context.Context, sanctionID, adminID primitive.ObjectID, note string) error {
+	now := time.Now()
+	filter := activeFilter(now)
+	filter["_id"] = sanctionID
+	var sanction Sanction
+	err := s.collection().FindOneAndUpdate(ctx, filter, liftUpdate(adminID, note, now)).Decode(&sanction)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errors.New("sanción no encontrada o no vigente")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lift sanction: %v", err)
+	}
+	forgetLogin(sanction.UserID)
+	if sanction.Type == TypeBan {
+		return s.syncBanned(ctx, sanction.UserID)
+	}
+	return nil
+}
+
+// LiftBans levanta todos los baneos vigentes de userID y devuelve cuántos levantó.
+func (s *Store) LiftBans(ctx context.Context, userID, adminID primitive.ObjectID, note string) (int64, error) {
+	now := time.Now()
+	filter := activeFilter(now)
+	filter["userId"] = userID
+	filter["type"] = TypeBan
+	res, err := s.collection().UpdateMany(ctx, filter, liftUpdate(adminID, note, now))
+	if err != nil {
+		return 0, fmt.Errorf("failed to lift bans: %v", err)
+	}
+	forgetLogin(userID)
+	return res.ModifiedCount, s.syncBanned(ctx, userID)
+}
+
+// MigrateLegacyBans crea un baneo para cada usuario con Users.Banned y sin baneo vigente, de antes de
+// que existieran las sanciones. Es idempotente y se corre al iniciar.
+func (s *Store) MigrateLegacyBans(ctx context.Context) error {
+	users := s.mongoClient.Database("NEXO-VECINAL").Collection("Users")
+	cursor, err := users.Find(ctx, bson.M{"Banned": true}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to find banned users: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %v", err)
+		}
+		filter := activeFilter(time.Now())
+		filter["userId"] = user.ID
+		filter["type"] = TypeBan
+		count, err := s.collection().CountDocuments(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to check bans: %v", err)
+		}
+		if count > 0 {
+			continue
+		}
+		ban, err := New(user.ID, primitive.NilObjectID, TypeBan, "Baneo anterior al sistema de sanciones", 0)
+		if err != nil {
+			return err
+		}
+		if err := s.Issue(ctx, ban); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// RejectAppeal rechaza la apelación pendiente de una sanción, que sigue vigente.
+func (s *Store) RejectAppeal(ctx context.Context, sanctionID primitive.ObjectID) error {
+	res, err := s.collection().UpdateOne(ctx,
+		bson.M{"_id": sanctionID, "appeal.status": AppealPending},
+		bson.M{"$set": bson.M{"appeal.status": AppealRejected, "appeal.resolvedAt": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reject appeal: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("la sanción no tiene una apelación pendiente")
+	}
+	return nil
+}
+
+// Active devuelve las sanciones vigentes de userID, las más severas primero.
+func (s *Store) Active(ctx context.Context, userID primitive.ObjectID) ([]Sanction, error) {
+	filter := activeFilter(time.Now())
+	filter["userId"] = userID
+	cursor, err := s.collection().Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active sanctions: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	active := []Sanction{}
+	if err := cursor.All(ctx, &active); err != nil {
+		return nil, fmt.Errorf("failed to decode sanctions: %v", err)
+	}
+	// Las más severas primero, para que Check devuelva la que más restringe
+	sort.SliceStable(active, func(i, j int) bool {
+		return active[i].Type.severity() > active[j].Type.severity()
+	})
+	return active, nil
+}
+
+// History devuelve todas las sanciones de userID, vigentes o no, las más recientes primero.
+// Sirve para graduar la siguiente sanción según los antecedentes.
+func (s *Store) History(ctx context.Context, userID primitive.ObjectID) ([]Sanction, error) {
+	cursor, err := s.collection().Find(ctx, bson.M{"userId": userID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sanction history: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	history := []Sanction{}
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, fmt.Errorf("failed to decode sanctions: %v", err)
+	}
+	return history, nil
+}
+
+// Check devuelve un *Error con la sanción vigente más severa que impide la acción, o nil.
+func (s *Store) Check(ctx context.Context, userID primitive.ObjectID, scope Scope) error {
+	active, err := s.Active(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, sanction := range active {
+		if sanction.Type.Blocks(scope) {
+			return &Error{Sanction: sanction}
+		}
+	}
+	return nil
+}
+
+// Tiempo que se reutiliza el resultado de CheckLogin. Las sanciones emitidas o levantadas en esta
+// instancia se aplican en el acto; en las demás, a lo sumo después de loginCacheTTL.
+const loginCacheTTL = 30 * time.Second
+
+type loginEntry struct {
+	sanction *Sanction // nil si nada impide el acceso
+	until    time.Time
+}
+
+// loginCache guarda por usuario el resultado de CheckLogin, que se consulta en cada petición autenticada.
+var loginCache sync.Map
+
+func forgetLogin(userID primitive.ObjectID) {
+	loginCache.Delete(userID)
+}
+
+// CheckLogin es Check con ScopeLogin (suspensiones y baneos) cacheado por usuario durante
+// loginCacheTTL, para el middleware de autenticación.
+func (s *Store) CheckLogin(ctx context.Context, userID primitive.ObjectID) error {
+	now := time.Now()
+	if cached, ok := loginCache.Load(userID); ok {
+		entry := cached.(loginEntry)
+		if now.Before(entry.until) {
+			if entry.sanction != nil && entry.sanction.Active(now) {
+				return &Error{Sanction: *entry.sanction}
+			}
+			if entry.sanction == nil {
+				return nil
+			}
+		}
+	}
+	err := s.Check(ctx, userID, ScopeLogin)
+	entry := loginEntry{until: now.Add(loginCacheTTL)}
+	if sanction, ok := As(err); ok {
+		entry.sanction = sanction
+	} else if err != nil {
+		return err
+	}
+	loginCache.Store(userID, entry)
+	return err
+}
+
+// Appeal registra la apelación de userID sobre una de sus sanciones vigentes. Falla si ya apeló.
+func (s *Store) Appeal(ctx context.Context, sanctionID, userID primitive.ObjectID, text string) (*Sanction, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("la apelación está vacía")
+	}
+	if len(text) > 2000 {
+		return nil, errors.New("la apelación excede los 2000 caracteres")
+	}
+	filter := activeFilter(time.Now())
+	filter["_id"] = sanctionID
+	filter["userId"] = userID
+	filter["appeal"] = nil
+
+	var sanction Sanction
+	err := s.collection().FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"appeal": Appeal{Text: text, Status: AppealPending, CreatedAt: time.Now()}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&sanction)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.New("sanción no encontrada, no vigente o ya apelada")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to appeal sanction: %v", err)
+	}
+	return &sanction, nil
+}
+
+// WithdrawAppeal borra la apelación pendiente de userID, para que pueda volver a enviarla si no
+// llegó a la cola de soporte.
+func (s *Store) WithdrawAppeal(ctx context.Context, sanctionID, userID primitive.ObjectID) error {
+	_, err := s.collection().UpdateOne(ctx,
+		bson.M{"_id": sanctionID, "userId": userID, "appeal.status": AppealPending},
+		bson.M{"$unset": bson.M{"appeal": ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to withdraw appeal: %v", err)
+	}
+	return nil
+}